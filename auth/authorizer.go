@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/erpc/erpc/common"
@@ -19,7 +20,7 @@ type Authorizer struct {
 }
 
 // NewAuthorizer creates a new Authorizer based on the provided configuration
-func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStrategyConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*Authorizer, error) {
+func NewAuthorizer(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.AuthStrategyConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*Authorizer, error) {
 	if cfg == nil {
 		return nil, common.NewErrInvalidConfig("auth strategy config is nil")
 	}
@@ -37,7 +38,7 @@ func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStr
 		if cfg.Jwt == nil {
 			return nil, common.NewErrInvalidConfig("JWT strategy config is nil")
 		}
-		strategy, err = NewJwtStrategy(cfg.Jwt)
+		strategy, err = NewJwtStrategy(appCtx, logger, cfg.Jwt)
 		if err != nil {
 			return nil, err
 		}
@@ -45,7 +46,10 @@ func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStr
 		if cfg.Siwe == nil {
 			return nil, common.NewErrInvalidConfig("SIWE strategy config is nil")
 		}
-		strategy = NewSiweStrategy(cfg.Siwe)
+		strategy, err = NewSiweStrategy(appCtx, logger, cfg.Siwe)
+		if err != nil {
+			return nil, err
+		}
 	case common.AuthTypeNetwork:
 		if cfg.Network == nil {
 			return nil, common.NewErrInvalidConfig("network strategy config is nil")
@@ -54,11 +58,25 @@ func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStr
 		if err != nil {
 			return nil, err
 		}
+	case common.AuthTypeDatabase:
+		if cfg.Database == nil {
+			return nil, common.NewErrInvalidConfig("database strategy config is nil")
+		}
+		strategy, err = NewDatabaseStrategy(appCtx, logger, cfg.Database, rateLimitersRegistry)
+		if err != nil {
+			return nil, err
+		}
+	case common.AuthTypeMtls:
+		if cfg.Mtls == nil {
+			return nil, common.NewErrInvalidConfig("mtls strategy config is nil")
+		}
+		strategy = NewMtlsStrategy(cfg.Mtls)
 	default:
 		return nil, common.NewErrInvalidConfig(fmt.Sprintf("unknown auth strategy type: %s", cfg.Type))
 	}
 
 	return &Authorizer{
+		projectId:            projectId,
 		logger:               logger,
 		cfg:                  cfg,
 		strategy:             strategy,
@@ -103,12 +121,18 @@ func (a *Authorizer) shouldApplyToMethod(method string) bool {
 	return shouldApply
 }
 
-func (a *Authorizer) acquireRateLimitPermit(req *common.NormalizedRequest) error {
-	if a.cfg.RateLimitBudget == "" {
+// acquireRateLimitPermit consumes a permit from the budget of the authenticated user if it has one
+// (e.g. defined per API key), otherwise from the budget defined on the strategy itself.
+func (a *Authorizer) acquireRateLimitPermit(req *common.NormalizedRequest, user *common.User) error {
+	budget := a.cfg.RateLimitBudget
+	if user != nil && user.RateLimitBudget != "" {
+		budget = user.RateLimitBudget
+	}
+	if budget == "" {
 		return nil
 	}
 
-	rlb, errNetLimit := a.rateLimitersRegistry.GetBudget(a.cfg.RateLimitBudget)
+	rlb, errNetLimit := a.rateLimitersRegistry.GetBudget(budget)
 	if errNetLimit != nil {
 		return errNetLimit
 	}
@@ -140,7 +164,7 @@ func (a *Authorizer) acquireRateLimitPermit(req *common.NormalizedRequest) error
 				return common.NewErrAuthRateLimitRuleExceeded(
 					a.projectId,
 					string(a.cfg.Type),
-					a.cfg.RateLimitBudget,
+					budget,
 					fmt.Sprintf("%+v", rule.Config),
 				)
 			} else {
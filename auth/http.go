@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"net/http"
@@ -10,10 +11,11 @@ import (
 	"github.com/erpc/erpc/common"
 )
 
-func NewPayloadFromHttp(projectId string, nq *common.NormalizedRequest, headers http.Header, args url.Values) (*AuthPayload, error) {
+func NewPayloadFromHttp(projectId, networkId string, nq *common.NormalizedRequest, headers http.Header, args url.Values, tlsState *tls.ConnectionState) (*AuthPayload, error) {
 	method, _ := nq.Method()
 	ap := &AuthPayload{
 		ProjectId: projectId,
+		NetworkId: networkId,
 		Method:    method,
 	}
 
@@ -76,6 +78,17 @@ func NewPayloadFromHttp(projectId string, nq *common.NormalizedRequest, headers
 		}
 	}
 
+	// Client certificate is a transport-level credential so it is attached regardless of other credentials,
+	// and only when it is verified against the CA configured for the server.
+	if tlsState != nil && len(tlsState.VerifiedChains) > 0 && len(tlsState.VerifiedChains[0]) > 0 {
+		ap.Mtls = &MtlsPayload{
+			Certificate: tlsState.VerifiedChains[0][0],
+		}
+		if ap.Type == "" {
+			ap.Type = common.AuthTypeMtls
+		}
+	}
+
 	// Add IP-based authentication
 	if ap.Type == "" {
 		xff := headers.Get("X-Forwarded-For")
@@ -0,0 +1,356 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jwksOnDemandFetchTimeout bounds fetches triggered by an unknown kid, as they block the request being authenticated.
+const jwksOnDemandFetchTimeout = 3 * time.Second
+
+// JwksProvider fetches and caches the public keys published by an identity provider as a JWKS document.
+// Keys are refreshed in the background (honoring Cache-Control/Expires headers of the endpoint) and
+// on-demand when a token is signed with an unknown kid, at most once per minRefreshInterval.
+type JwksProvider struct {
+	logger             *zerolog.Logger
+	uri                string
+	httpClient         *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	keysMu    sync.RWMutex
+	keys      map[string]interface{}
+	etag      string
+	lastFetch time.Time
+	nextFetch time.Time
+
+	fetchMu     sync.Mutex
+	lastAttempt time.Time
+}
+
+func NewJwksProvider(
+	appCtx context.Context,
+	logger *zerolog.Logger,
+	uri string,
+	refreshInterval time.Duration,
+	minRefreshInterval time.Duration,
+) *JwksProvider {
+	lg := logger.With().Str("jwksUri", uri).Logger()
+	p := &JwksProvider{
+		logger:             &lg,
+		uri:                uri,
+		httpClient:         &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minRefreshInterval,
+		keys:               make(map[string]interface{}),
+	}
+
+	if err := p.refresh(appCtx, false); err != nil {
+		// Failing to fetch keys at boot must not prevent eRPC from starting,
+		// keys will be fetched again on next refresh or when a token is received.
+		p.logger.Error().Err(err).Msg("failed to fetch initial JWKS")
+	}
+
+	go p.refreshLoop(appCtx)
+
+	return p
+}
+
+// GetKey returns the key identified by kid, refreshing the key set if the kid is unknown.
+func (p *JwksProvider) GetKey(ctx context.Context, kid string) (interface{}, bool) {
+	p.keysMu.RLock()
+	key, ok := p.keys[kid]
+	p.keysMu.RUnlock()
+	if ok {
+		return key, true
+	}
+
+	// Unknown kid usually means the identity provider has rotated its keys
+	if err := p.refresh(ctx, true); err != nil {
+		p.logger.Warn().Err(err).Str("kid", kid).Msg("failed to refresh JWKS for unknown kid")
+	}
+
+	p.keysMu.RLock()
+	defer p.keysMu.RUnlock()
+	key, ok = p.keys[kid]
+	return key, ok
+}
+
+// Keys returns a snapshot of all currently known keys, used when a token has no kid header.
+func (p *JwksProvider) Keys() []interface{} {
+	p.keysMu.RLock()
+	defer p.keysMu.RUnlock()
+	keys := make([]interface{}, 0, len(p.keys))
+	for _, k := range p.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (p *JwksProvider) refreshLoop(ctx context.Context) {
+	for {
+		p.keysMu.RLock()
+		wait := time.Until(p.nextFetch)
+		p.keysMu.RUnlock()
+		if wait < p.minRefreshInterval {
+			wait = p.minRefreshInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := p.refresh(ctx, false); err != nil {
+				p.logger.Warn().Err(err).Msg("failed to refresh JWKS")
+			}
+		}
+	}
+}
+
+func (p *JwksProvider) refresh(ctx context.Context, onDemand bool) error {
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	p.keysMu.RLock()
+	etag := p.etag
+	p.keysMu.RUnlock()
+
+	// Avoid hammering the identity provider when many tokens with unknown kid are received,
+	// failed attempts count as well so that an unreachable endpoint is not retried on every request.
+	if onDemand && time.Since(p.lastAttempt) < p.minRefreshInterval {
+		return nil
+	}
+	p.lastAttempt = time.Now()
+
+	if onDemand {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, jwksOnDemandFetchTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	now := time.Now()
+	if err != nil {
+		p.retryAfter(now.Add(p.minRefreshInterval))
+		return err
+	}
+	defer resp.Body.Close()
+
+	nextFetch := now.Add(p.cacheDuration(resp.Header, now))
+
+	if resp.StatusCode == http.StatusNotModified {
+		p.markFetched(now, nextFetch)
+		p.logger.Debug().Msg("JWKS not modified since last fetch")
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		p.retryAfter(now.Add(p.minRefreshInterval))
+		return fmt.Errorf("unexpected status code %d from JWKS endpoint", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		p.retryAfter(now.Add(p.minRefreshInterval))
+		return err
+	}
+
+	keys, err := parseJwks(p.logger, body)
+	if err != nil {
+		p.retryAfter(now.Add(p.minRefreshInterval))
+		return err
+	}
+
+	p.keysMu.Lock()
+	p.keys = keys
+	p.etag = resp.Header.Get("ETag")
+	p.lastFetch = now
+	p.nextFetch = nextFetch
+	p.keysMu.Unlock()
+
+	p.logger.Debug().Int("keys", len(keys)).Time("nextFetch", nextFetch).Msg("refreshed JWKS")
+
+	return nil
+}
+
+func (p *JwksProvider) markFetched(at, next time.Time) {
+	p.keysMu.Lock()
+	defer p.keysMu.Unlock()
+	p.lastFetch = at
+	p.nextFetch = next
+}
+
+// retryAfter schedules the next background fetch after a failed attempt, keeping lastFetch
+// pointing at the last successful fetch.
+func (p *JwksProvider) retryAfter(next time.Time) {
+	p.keysMu.Lock()
+	defer p.keysMu.Unlock()
+	p.nextFetch = next
+}
+
+// cacheDuration determines how long the fetched key set can be used based on Cache-Control
+// max-age or Expires headers, falling back to the configured refresh interval.
+func (p *JwksProvider) cacheDuration(h http.Header, now time.Time) time.Duration {
+	d := p.refreshInterval
+
+	if cc := h.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			if directive == "no-cache" || directive == "no-store" {
+				return p.minRefreshInterval
+			}
+			if strings.HasPrefix(directive, "max-age=") {
+				if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+					d = time.Duration(secs) * time.Second
+				}
+			}
+		}
+	} else if exp := h.Get("Expires"); exp != "" {
+		if t, err := http.ParseTime(exp); err == nil {
+			d = t.Sub(now)
+		}
+	}
+
+	if d < p.minRefreshInterval {
+		d = p.minRefreshInterval
+	}
+	if d > p.refreshInterval {
+		d = p.refreshInterval
+	}
+
+	return d
+}
+
+// parseJwks returns the signing keys of the set, keys that are unsupported or invalid are skipped
+// so that a single unusable key published by the identity provider does not invalidate the others.
+func parseJwks(logger *zerolog.Logger, body []byte) (map[string]interface{}, error) {
+	var set jsonWebKeySet
+	if err := common.SonicCfg.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logger.Warn().Err(err).Int("index", i).Str("kid", jwk.Kid).Str("kty", jwk.Kty).Msg("skipping unsupported or invalid JWK")
+			continue
+		}
+		kid := jwk.Kid
+		if kid == "" {
+			kid = strconv.Itoa(i)
+		}
+		keys[kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64Url(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64Url(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve: %s", k.Crv)
+		}
+		x, err := decodeBase64Url(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64Url(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve: %s", k.Crv)
+		}
+		x, err := decodeBase64Url(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key size: %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBase64Url(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
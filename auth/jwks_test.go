@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/h2non/gock"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaJwk(t *testing.T, kid string, key *rsa.PrivateKey) map[string]interface{} {
+	t.Helper()
+	return map[string]interface{}{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJwksProvider(t *testing.T) {
+	logger := log.Logger
+
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Run("FetchesKeysAndRefreshesOnUnknownKid", func(t *testing.T) {
+		defer gock.Off()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(200).
+			SetHeader("Cache-Control", "public, max-age=3600").
+			JSON(map[string]interface{}{"keys": []interface{}{rsaJwk(t, "k1", key1)}})
+
+		p := NewJwksProvider(ctx, &logger, "http://idp.localhost/.well-known/jwks.json", time.Hour, 0)
+
+		k, ok := p.GetKey(ctx, "k1")
+		require.True(t, ok)
+		assert.Equal(t, key1.PublicKey.N, k.(*rsa.PublicKey).N)
+
+		// Identity provider rotates keys
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(200).
+			JSON(map[string]interface{}{"keys": []interface{}{rsaJwk(t, "k1", key1), rsaJwk(t, "k2", key2)}})
+
+		k, ok = p.GetKey(ctx, "k2")
+		require.True(t, ok)
+		assert.Equal(t, key2.PublicKey.N, k.(*rsa.PublicKey).N)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("DoesNotRefetchBeforeMinRefreshInterval", func(t *testing.T) {
+		defer gock.Off()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Times(1).
+			Reply(200).
+			JSON(map[string]interface{}{"keys": []interface{}{rsaJwk(t, "k1", key1)}})
+
+		p := NewJwksProvider(ctx, &logger, "http://idp.localhost/.well-known/jwks.json", time.Hour, time.Minute)
+
+		_, ok := p.GetKey(ctx, "unknown")
+		assert.False(t, ok)
+		_, ok = p.GetKey(ctx, "k1")
+		assert.True(t, ok)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("SkipsUnsupportedAndInvalidKeys", func(t *testing.T) {
+		defer gock.Off()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(200).
+			JSON(map[string]interface{}{"keys": []interface{}{
+				map[string]interface{}{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+				map[string]interface{}{"kty": "EC", "kid": "bad-curve", "crv": "P-192", "x": "AA", "y": "AA"},
+				rsaJwk(t, "k1", key1),
+			}})
+
+		p := NewJwksProvider(ctx, &logger, "http://idp.localhost/.well-known/jwks.json", time.Hour, time.Minute)
+
+		k, ok := p.GetKey(ctx, "k1")
+		require.True(t, ok)
+		assert.Equal(t, key1.PublicKey.N, k.(*rsa.PublicKey).N)
+		assert.Len(t, p.Keys(), 1)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("FailedFetchIsRetried", func(t *testing.T) {
+		defer gock.Off()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(503)
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(200).
+			JSON(map[string]interface{}{"keys": []interface{}{rsaJwk(t, "k1", key1)}})
+
+		p := NewJwksProvider(ctx, &logger, "http://idp.localhost/.well-known/jwks.json", time.Hour, 0)
+		lastFetch := func() time.Time {
+			p.keysMu.RLock()
+			defer p.keysMu.RUnlock()
+			return p.lastFetch
+		}
+		_, ok := p.GetKey(ctx, "k1")
+		assert.True(t, ok)
+		assert.False(t, lastFetch().IsZero())
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("CacheDurationRespectsHeaders", func(t *testing.T) {
+		p := &JwksProvider{refreshInterval: time.Hour, minRefreshInterval: 30 * time.Second}
+		now := time.Now()
+
+		h := http.Header{}
+		h.Set("Cache-Control", "max-age=300")
+		assert.Equal(t, 5*time.Minute, p.cacheDuration(h, now))
+
+		h = http.Header{}
+		h.Set("Cache-Control", "max-age=86400")
+		assert.Equal(t, time.Hour, p.cacheDuration(h, now))
+
+		h = http.Header{}
+		h.Set("Cache-Control", "no-cache")
+		assert.Equal(t, 30*time.Second, p.cacheDuration(h, now))
+
+		h = http.Header{}
+		h.Set("Expires", now.Add(10*time.Minute).UTC().Format(http.TimeFormat))
+		d := p.cacheDuration(h, now)
+		assert.InDelta(t, float64(10*time.Minute), float64(d), float64(2*time.Second))
+	})
+
+	t.Run("JwtStrategyVerifiesTokenSignedWithJwksKey", func(t *testing.T) {
+		defer gock.Off()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		gock.New("http://idp.localhost").
+			Get("/.well-known/jwks.json").
+			Reply(200).
+			JSON(map[string]interface{}{"keys": []interface{}{rsaJwk(t, "k1", key1)}})
+
+		s := &JwtStrategy{
+			cfg:    &common.JwtStrategyConfig{},
+			parser: jwt.NewParser(jwt.WithoutClaimsValidation()),
+			keys:   map[string]jwt.Keyfunc{},
+			jwks:   NewJwksProvider(ctx, &logger, "http://idp.localhost/.well-known/jwks.json", time.Hour, time.Minute),
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key1)
+		require.NoError(t, err)
+
+		user, err := s.Authenticate(ctx, &AuthPayload{Jwt: &JwtPayload{Token: signed}})
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", user.Id)
+
+		// Token signed by a key that is not published must be rejected
+		signed, err = token.SignedString(key2)
+		require.NoError(t, err)
+		_, err = s.Authenticate(ctx, &AuthPayload{Jwt: &JwtPayload{Token: signed}})
+		assert.Error(t, err)
+	})
+}
@@ -1,15 +1,21 @@
 package auth
 
-import "github.com/erpc/erpc/common"
+import (
+	"crypto/x509"
+
+	"github.com/erpc/erpc/common"
+)
 
 type AuthPayload struct {
 	ProjectId string
+	NetworkId string
 	Method    string
 	Type      common.AuthType
 	Secret    *SecretPayload
 	Jwt       *JwtPayload
 	Siwe      *SiwePayload
 	Network   *NetworkPayload
+	Mtls      *MtlsPayload
 }
 
 type SecretPayload struct {
@@ -29,3 +35,8 @@ type NetworkPayload struct {
 	Address        string
 	ForwardProxies []string
 }
+
+type MtlsPayload struct {
+	// Leaf certificate presented by the client, already verified against server's CA during TLS handshake.
+	Certificate *x509.Certificate
+}
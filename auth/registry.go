@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/upstream"
@@ -18,7 +19,7 @@ type AuthRegistry struct {
 }
 
 // NewAuthRegistry creates a new group of authorizers for a project
-func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*AuthRegistry, error) {
+func NewAuthRegistry(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.AuthConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*AuthRegistry, error) {
 	if cfg == nil {
 		return nil, common.NewErrInvalidConfig("auth config is nil")
 	}
@@ -31,7 +32,7 @@ func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthC
 
 	for _, strategy := range cfg.Strategies {
 		lg := logger.With().Str("strategy", string(strategy.Type)).Logger()
-		az, err := NewAuthorizer(&lg, projectId, strategy, rateLimitersRegistry)
+		az, err := NewAuthorizer(appCtx, &lg, projectId, strategy, rateLimitersRegistry)
 		if err != nil {
 			return nil, common.NewErrInvalidConfig(fmt.Sprintf("failed to create authorizer for project %s with strategy %s: %v", projectId, strategy.Type, err))
 		}
@@ -42,19 +43,20 @@ func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthC
 }
 
 // Authenticate checks the authentication payload against all registered strategies
-func (r *AuthRegistry) Authenticate(ctx context.Context, nq *common.NormalizedRequest, ap *AuthPayload) error {
+// and returns the identity of the consumer resolved by the first strategy that succeeds.
+func (r *AuthRegistry) Authenticate(ctx context.Context, nq *common.NormalizedRequest, ap *AuthPayload) (*common.User, error) {
 	if ap == nil {
-		return common.NewErrAuthUnauthorized("", "auth payload is nil")
+		return nil, common.NewErrAuthUnauthorized("", "auth payload is nil")
 	}
 
 	if len(r.strategies) == 0 {
 		// If no strategies are configured, allow all requests
-		return nil
+		return nil, nil
 	}
 
 	method, err := nq.Method()
 	if err != nil {
-		return fmt.Errorf("failed to get method from request: %w", err)
+		return nil, fmt.Errorf("failed to get method from request: %w", err)
 	}
 
 	var errs []error
@@ -68,28 +70,48 @@ func (r *AuthRegistry) Authenticate(ctx context.Context, nq *common.NormalizedRe
 			continue
 		}
 
-		if err := az.strategy.Authenticate(ctx, ap); err != nil {
+		user, err := az.strategy.Authenticate(ctx, ap)
+		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
 
+		// Consumer-specific restrictions (e.g. defined per API key) are enforced before consuming any rate limit permit
+		if !user.IsMethodAllowed(method) {
+			return nil, common.NewErrAuthUnauthorized(string(az.cfg.Type), fmt.Sprintf("method %s is not allowed for this consumer", method))
+		}
+		if ap.NetworkId != "" && !user.IsNetworkAllowed(ap.NetworkId) {
+			return nil, common.NewErrAuthUnauthorized(string(az.cfg.Type), fmt.Sprintf("network %s is not allowed for this consumer", ap.NetworkId))
+		}
+
 		// If authentication is passed then apply and consume the rate limit
-		if err := az.acquireRateLimitPermit(nq); err != nil {
-			return err
+		if err := az.acquireRateLimitPermit(nq, user); err != nil {
+			return nil, err
 		}
 
 		// If a strategy succeeds, we consider the request authenticated
-		return nil
+		return user, nil
 	}
 
 	if len(errs) == 1 {
-		return errs[0]
+		return nil, errs[0]
 	}
 
 	if len(errs) == 0 {
-		return common.NewErrAuthUnauthorized("", "no auth strategy matched make sure correct headers or query strings are provided")
+		return nil, common.NewErrAuthUnauthorized("", "no auth strategy matched make sure correct headers or query strings are provided")
 	}
 
 	// If no strategy matched or succeeded, consider the request unauthorized
-	return common.NewErrAuthUnauthorized("", errors.Join(errs...).Error())
+	return nil, common.NewErrAuthUnauthorized("", errors.Join(errs...).Error())
+}
+
+// IssueSiweNonce issues a nonce from the first siwe strategy that has nonce issuance enabled.
+func (r *AuthRegistry) IssueSiweNonce(ctx context.Context) (string, time.Time, error) {
+	for _, az := range r.strategies {
+		if s, ok := az.strategy.(*SiweStrategy); ok && s.nonces != nil {
+			return s.IssueNonce(ctx)
+		}
+	}
+
+	return "", time.Time{}, common.NewErrInvalidRequest(fmt.Errorf("no siwe auth strategy with nonce issuance is configured for project %s", r.projectId))
 }
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog"
+)
+
+// SiweNonceRangeKey is the range key under which issued nonces are stored, where partition key is the nonce itself.
+const SiweNonceRangeKey = "siwe-nonce"
+
+const (
+	siweNonceIssued      = "issued"
+	siweNonceBoundPrefix = "bound:"
+)
+
+// SiweNonceStore issues single-use nonces for SIWE messages. An issued nonce is bound to the
+// first signed message that uses it, so the same nonce cannot be used to sign in again with
+// another message, and a message whose nonce was not issued by eRPC is rejected.
+type SiweNonceStore struct {
+	logger    *zerolog.Logger
+	connector data.Connector
+	ttl       time.Duration
+
+	// Serializes check-and-bind within this instance, connectors do not provide compare-and-set so
+	// concurrent first uses of the same nonce on different instances can both be accepted.
+	bindMu sync.Mutex
+}
+
+func NewSiweNonceStore(appCtx context.Context, logger *zerolog.Logger, cfg *common.SiweNonceConfig) (*SiweNonceStore, error) {
+	connector, err := data.NewConnector(appCtx, logger, cfg.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := time.ParseDuration(cfg.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nonce ttl: %w", err)
+	}
+
+	return &SiweNonceStore{
+		logger:    logger,
+		connector: connector,
+		ttl:       ttl,
+	}, nil
+}
+
+// Issue generates a new nonce and stores it until it expires.
+func (s *SiweNonceStore) Issue(ctx context.Context) (string, time.Time, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	nonce := hex.EncodeToString(b)
+	expiresAt := time.Now().Add(s.ttl)
+
+	if err := s.connector.Set(ctx, nonce, SiweNonceRangeKey, siweNonceIssued, &s.ttl); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return nonce, expiresAt, nil
+}
+
+// Consume binds the nonce to the given message hash on first use and keeps the binding until
+// sessionEnd, which must be set so that no binding is stored forever. Further uses are only accepted
+// for the same message.
+func (s *SiweNonceStore) Consume(ctx context.Context, nonce, messageHash string, sessionEnd time.Time) error {
+	if sessionEnd.IsZero() {
+		return common.NewErrAuthUnauthorized("siwe", "session end is required to consume a nonce")
+	}
+	ttl := time.Until(sessionEnd)
+	if ttl <= 0 {
+		return common.NewErrAuthUnauthorized("siwe", "session is expired")
+	}
+
+	bound := siweNonceBoundPrefix + messageHash
+
+	value, err := s.connector.Get(ctx, data.ConnectorMainIndex, nonce, SiweNonceRangeKey)
+	if err == nil && value == bound {
+		return nil
+	}
+
+	s.bindMu.Lock()
+	defer s.bindMu.Unlock()
+
+	value, err = s.connector.Get(ctx, data.ConnectorMainIndex, nonce, SiweNonceRangeKey)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			return common.NewErrAuthUnauthorized("siwe", "nonce is unknown or expired")
+		}
+		s.logger.Error().Err(err).Msg("failed to load SIWE nonce")
+		return common.NewErrAuthUnauthorized("siwe", "failed to verify nonce")
+	}
+
+	switch {
+	case value == bound:
+		return nil
+	case strings.HasPrefix(value, siweNonceBoundPrefix):
+		return common.NewErrAuthUnauthorized("siwe", "nonce is already used by another message")
+	case value != siweNonceIssued:
+		return common.NewErrAuthUnauthorized("siwe", "nonce is invalid")
+	}
+
+	if err := s.connector.Set(ctx, nonce, SiweNonceRangeKey, bound, &ttl); err != nil {
+		s.logger.Error().Err(err).Msg("failed to store SIWE nonce binding")
+		return common.NewErrAuthUnauthorized("siwe", "failed to verify nonce")
+	}
+
+	return nil
+}
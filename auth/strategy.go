@@ -1,8 +1,12 @@
 package auth
 
-import "context"
+import (
+	"context"
+
+	"github.com/erpc/erpc/common"
+)
 
 type AuthStrategy interface {
 	Supports(ap *AuthPayload) bool
-	Authenticate(ctx context.Context, ap *AuthPayload) error
+	Authenticate(ctx context.Context, ap *AuthPayload) (*common.User, error)
 }
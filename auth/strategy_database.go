@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/upstream"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/rs/zerolog"
+)
+
+// DatabaseApiKeyRangeKey is the range key under which API key records are stored,
+// where partition key is the hex-encoded sha256 hash of the API key itself.
+const DatabaseApiKeyRangeKey = "apikey"
+
+// DatabaseApiKey is the JSON record stored in the connector for each API key.
+type DatabaseApiKey struct {
+	Owner           string     `json:"owner"`
+	AllowedNetworks []string   `json:"allowedNetworks,omitempty"`
+	AllowedMethods  []string   `json:"allowedMethods,omitempty"`
+	RateLimitBudget string     `json:"rateLimitBudget,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	Revoked         bool       `json:"revoked,omitempty"`
+}
+
+type databaseCacheEntry struct {
+	key *DatabaseApiKey
+	err error
+}
+
+type DatabaseStrategy struct {
+	logger               *zerolog.Logger
+	cfg                  *common.DatabaseStrategyConfig
+	connector            data.Connector
+	cache                *expirable.LRU[string, *databaseCacheEntry]
+	rateLimitersRegistry *upstream.RateLimitersRegistry
+}
+
+var _ AuthStrategy = &DatabaseStrategy{}
+
+func NewDatabaseStrategy(appCtx context.Context, logger *zerolog.Logger, cfg *common.DatabaseStrategyConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*DatabaseStrategy, error) {
+	connector, err := data.NewConnector(appCtx, logger, cfg.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := time.ParseDuration(cfg.CacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cacheTTL: %w", err)
+	}
+
+	return &DatabaseStrategy{
+		logger:               logger,
+		cfg:                  cfg,
+		connector:            connector,
+		cache:                expirable.NewLRU[string, *databaseCacheEntry](cfg.CacheMaxItems, nil, ttl),
+		rateLimitersRegistry: rateLimitersRegistry,
+	}, nil
+}
+
+func (s *DatabaseStrategy) Supports(ap *AuthPayload) bool {
+	// API keys are provided the same way as secret tokens (query string, header or basic auth)
+	return ap.Type == common.AuthTypeSecret
+}
+
+func (s *DatabaseStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (*common.User, error) {
+	if ap.Secret == nil || ap.Secret.Value == "" {
+		return nil, common.NewErrAuthUnauthorized("database", "missing api key")
+	}
+
+	hash := HashApiKey(ap.Secret.Value)
+
+	entry, ok := s.cache.Get(hash)
+	if !ok {
+		key, err := s.loadKey(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		entry = &databaseCacheEntry{key: key, err: s.validateKey(key)}
+		s.cache.Add(hash, entry)
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	key := entry.key
+	if key == nil {
+		return nil, common.NewErrAuthUnauthorized("database", "invalid api key")
+	}
+	if key.Revoked {
+		return nil, common.NewErrAuthUnauthorized("database", "api key is revoked")
+	}
+	if key.ExpiresAt != nil && !time.Now().Before(*key.ExpiresAt) {
+		return nil, common.NewErrAuthUnauthorized("database", "api key is expired")
+	}
+
+	return &common.User{
+		Id:              key.Owner,
+		RateLimitBudget: key.RateLimitBudget,
+		AllowedNetworks: key.AllowedNetworks,
+		AllowedMethods:  key.AllowedMethods,
+	}, nil
+}
+
+// loadKey fetches the key record from the connector. A missing record is returned as nil
+// (so that it gets cached as a negative result) while connector failures are returned as errors.
+func (s *DatabaseStrategy) loadKey(ctx context.Context, hash string) (*DatabaseApiKey, error) {
+	value, err := s.connector.Get(ctx, data.ConnectorMainIndex, hash, DatabaseApiKeyRangeKey)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			return nil, nil
+		}
+		s.logger.Error().Err(err).Msg("failed to load api key from database")
+		return nil, common.NewErrAuthUnauthorized("database", "failed to verify api key")
+	}
+
+	key := &DatabaseApiKey{}
+	if err := common.SonicCfg.UnmarshalFromString(value, key); err != nil {
+		s.logger.Error().Err(err).Msg("failed to parse api key record stored in database")
+		return nil, common.NewErrAuthUnauthorized("database", "failed to verify api key")
+	}
+
+	return key, nil
+}
+
+// validateKey checks references of the key record once when it is loaded, so that a misconfigured key
+// is rejected on authentication rather than failing each request later on.
+func (s *DatabaseStrategy) validateKey(key *DatabaseApiKey) error {
+	if key == nil || key.RateLimitBudget == "" || s.rateLimitersRegistry == nil {
+		return nil
+	}
+	if _, err := s.rateLimitersRegistry.GetBudget(key.RateLimitBudget); err != nil {
+		s.logger.Error().Err(err).Str("owner", key.Owner).Msg("api key references an unknown rate limit budget")
+		return common.NewErrAuthUnauthorized("database", fmt.Sprintf("rate limit budget %s of api key is not defined", key.RateLimitBudget))
+	}
+	return nil
+}
+
+// HashApiKey returns the hex-encoded sha256 hash of the API key, which is how keys are looked up in the database.
+func HashApiKey(apiKey string) string {
+	h := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(h[:])
+}
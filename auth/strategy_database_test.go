@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/upstream"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseStrategy(t *testing.T) {
+	logger := log.Logger
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rlr, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{
+		Budgets: []*common.RateLimitBudgetConfig{{Id: "key-budget"}},
+	}, &logger)
+	require.NoError(t, err)
+
+	newStrategy := func(t *testing.T) *DatabaseStrategy {
+		cfg := &common.DatabaseStrategyConfig{
+			Connector: &common.ConnectorConfig{Id: "apikeys", Driver: common.DriverMemory},
+			CacheTTL:  "100ms",
+		}
+		cfg.SetDefaults()
+		s, err := NewDatabaseStrategy(ctx, &logger, cfg, rlr)
+		require.NoError(t, err)
+		return s
+	}
+
+	storeKey := func(t *testing.T, s *DatabaseStrategy, apiKey string, key *DatabaseApiKey) {
+		value, err := common.SonicCfg.MarshalToString(key)
+		require.NoError(t, err)
+		require.NoError(t, s.connector.Set(ctx, HashApiKey(apiKey), DatabaseApiKeyRangeKey, value, nil))
+	}
+
+	authenticate := func(s *DatabaseStrategy, apiKey string) (*common.User, error) {
+		return s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSecret, Secret: &SecretPayload{Value: apiKey}})
+	}
+
+	t.Run("LooksUpKeyByHash", func(t *testing.T) {
+		s := newStrategy(t)
+		storeKey(t, s, "my-key", &DatabaseApiKey{
+			Owner:           "alice",
+			AllowedNetworks: []string{"evm:1"},
+			RateLimitBudget: "key-budget",
+		})
+
+		user, err := authenticate(s, "my-key")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user.Id)
+		assert.Equal(t, "key-budget", user.RateLimitBudget)
+		assert.Equal(t, []string{"evm:1"}, user.AllowedNetworks)
+
+		// The plain key itself is never used as a lookup key
+		_, err = s.connector.Get(ctx, data.ConnectorMainIndex, "my-key", DatabaseApiKeyRangeKey)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeRecordNotFound))
+	})
+
+	t.Run("CachesPositiveResultUntilTTLExpires", func(t *testing.T) {
+		s := newStrategy(t)
+		storeKey(t, s, "revocable", &DatabaseApiKey{Owner: "bob"})
+
+		_, err := authenticate(s, "revocable")
+		require.NoError(t, err)
+
+		storeKey(t, s, "revocable", &DatabaseApiKey{Owner: "bob", Revoked: true})
+		_, err = authenticate(s, "revocable")
+		assert.NoError(t, err, "revocation should not be visible before cache expires")
+
+		time.Sleep(150 * time.Millisecond)
+		_, err = authenticate(s, "revocable")
+		assert.ErrorContains(t, err, "revoked")
+	})
+
+	t.Run("CachesNegativeResultUntilTTLExpires", func(t *testing.T) {
+		s := newStrategy(t)
+
+		_, err := authenticate(s, "not-yet-created")
+		assert.ErrorContains(t, err, "invalid api key")
+
+		storeKey(t, s, "not-yet-created", &DatabaseApiKey{Owner: "carol"})
+		_, err = authenticate(s, "not-yet-created")
+		assert.ErrorContains(t, err, "invalid api key")
+
+		time.Sleep(150 * time.Millisecond)
+		user, err := authenticate(s, "not-yet-created")
+		require.NoError(t, err)
+		assert.Equal(t, "carol", user.Id)
+	})
+
+	t.Run("RejectsExpiredKey", func(t *testing.T) {
+		s := newStrategy(t)
+		past := time.Now().Add(-time.Minute)
+		storeKey(t, s, "expired", &DatabaseApiKey{Owner: "dave", ExpiresAt: &past})
+
+		_, err := authenticate(s, "expired")
+		assert.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("RejectsKeyWithUnknownBudgetOnLoad", func(t *testing.T) {
+		s := newStrategy(t)
+		storeKey(t, s, "bad-budget", &DatabaseApiKey{Owner: "erin", RateLimitBudget: "missing-budget"})
+
+		_, err := authenticate(s, "bad-budget")
+		assert.ErrorContains(t, err, "missing-budget")
+	})
+
+	t.Run("DoesNotCacheConnectorErrors", func(t *testing.T) {
+		s := newStrategy(t)
+		mc := data.NewMockConnector("apikeys")
+		mc.On("Get", mock.Anything, data.ConnectorMainIndex, HashApiKey("flaky"), DatabaseApiKeyRangeKey).
+			Return("", errors.New("connection refused")).Once()
+		mc.On("Get", mock.Anything, data.ConnectorMainIndex, HashApiKey("flaky"), DatabaseApiKeyRangeKey).
+			Return(`{"owner":"frank"}`, nil).Once()
+		s.connector = mc
+
+		_, err := authenticate(s, "flaky")
+		assert.ErrorContains(t, err, "failed to verify api key")
+
+		user, err := authenticate(s, "flaky")
+		require.NoError(t, err)
+		assert.Equal(t, "frank", user.Id)
+		mc.AssertExpectations(t)
+	})
+
+	t.Run("EnforcesPerKeyNetworksAndMethods", func(t *testing.T) {
+		strategyCfg := &common.AuthStrategyConfig{
+			Type: common.AuthTypeDatabase,
+			Database: &common.DatabaseStrategyConfig{
+				Connector: &common.ConnectorConfig{Id: "apikeys-registry", Driver: common.DriverMemory},
+			},
+		}
+		strategyCfg.SetDefaults()
+		registry, err := NewAuthRegistry(ctx, &logger, "test", &common.AuthConfig{
+			Strategies: []*common.AuthStrategyConfig{strategyCfg},
+		}, rlr)
+		require.NoError(t, err)
+
+		s := registry.strategies[0].strategy.(*DatabaseStrategy)
+		storeKey(t, s, "restricted", &DatabaseApiKey{
+			Owner:           "grace",
+			AllowedNetworks: []string{"evm:1"},
+			AllowedMethods:  []string{"eth_get*"},
+		})
+
+		authenticateRequest := func(networkId, method string) error {
+			nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`))
+			_, err := registry.Authenticate(ctx, nq, &AuthPayload{
+				Type:      common.AuthTypeSecret,
+				NetworkId: networkId,
+				Secret:    &SecretPayload{Value: "restricted"},
+			})
+			return err
+		}
+
+		assert.NoError(t, authenticateRequest("evm:1", "eth_getBalance"))
+		assert.ErrorContains(t, authenticateRequest("evm:1", "eth_sendRawTransaction"), "method eth_sendRawTransaction is not allowed")
+		assert.ErrorContains(t, authenticateRequest("evm:137", "eth_getBalance"), "network evm:137 is not allowed")
+	})
+}
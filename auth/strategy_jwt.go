@@ -3,25 +3,30 @@ package auth
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"encoding/pem"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
 )
 
 type JwtStrategy struct {
 	cfg    *common.JwtStrategyConfig
 	parser *jwt.Parser
 	keys   map[string]jwt.Keyfunc
+	jwks   *JwksProvider
 }
 
 var _ AuthStrategy = &JwtStrategy{}
 
-func NewJwtStrategy(cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
+func NewJwtStrategy(appCtx context.Context, logger *zerolog.Logger, cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
 	// Parse and store verification keys
 	var keys map[string]jwt.Keyfunc = make(map[string]jwt.Keyfunc)
 	for kid, keyData := range cfg.VerificationKeys {
@@ -34,10 +39,24 @@ func NewJwtStrategy(cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
 		}
 	}
 
+	var jwks *JwksProvider
+	if cfg.JwksUri != "" {
+		refreshInterval, err := time.ParseDuration(cfg.JwksRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwksRefreshInterval: %w", err)
+		}
+		minRefreshInterval, err := time.ParseDuration(cfg.JwksMinRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwksMinRefreshInterval: %w", err)
+		}
+		jwks = NewJwksProvider(appCtx, logger, cfg.JwksUri, refreshInterval, minRefreshInterval)
+	}
+
 	return &JwtStrategy{
 		cfg:    cfg,
 		parser: jwt.NewParser(jwt.WithoutClaimsValidation()),
 		keys:   keys,
+		jwks:   jwks,
 	}, nil
 }
 
@@ -45,46 +64,150 @@ func (s *JwtStrategy) Supports(ap *AuthPayload) bool {
 	return ap.Type == common.AuthTypeJwt
 }
 
-func (s *JwtStrategy) Authenticate(ctx context.Context, ap *AuthPayload) error {
+func (s *JwtStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (*common.User, error) {
 	token, _, err := s.parser.ParseUnverified(ap.Jwt.Token, jwt.MapClaims{})
 	if err != nil {
-		return common.NewErrAuthUnauthorized("jwt", "failed to parse JWT")
+		return nil, common.NewErrAuthUnauthorized("jwt", "failed to parse JWT")
 	}
 
 	if len(s.cfg.AllowedAlgorithms) > 0 {
 		if !contains(s.cfg.AllowedAlgorithms, token.Method.Alg()) {
-			return common.NewErrAuthUnauthorized("jwt", "unexpected signing method")
+			return nil, common.NewErrAuthUnauthorized("jwt", "unexpected signing method")
 		}
 	}
 
-	key, err := s.findVerificationKey(token)
+	key, err := s.findVerificationKey(ctx, token)
 	if err != nil {
-		return common.NewErrAuthUnauthorized("jwt", err.Error())
+		return nil, common.NewErrAuthUnauthorized("jwt", err.Error())
 	}
 
 	// Verify the signature
 	if _, err := jwt.Parse(ap.Jwt.Token, key); err != nil {
-		return common.NewErrAuthUnauthorized("jwt", "invalid signature")
+		return nil, common.NewErrAuthUnauthorized("jwt", "invalid signature")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return common.NewErrAuthUnauthorized("jwt", "invalid JWT claims")
+		return nil, common.NewErrAuthUnauthorized("jwt", "invalid JWT claims")
 	}
 
 	if err := s.validateClaims(claims); err != nil {
-		return common.NewErrAuthUnauthorized("jwt", err.Error())
+		return nil, common.NewErrAuthUnauthorized("jwt", err.Error())
+	}
+
+	user := &common.User{}
+	if sub, ok := claims["sub"].(string); ok {
+		user.Id = sub
+	}
+
+	if s.cfg.ClaimsMapping != nil {
+		if err := s.applyClaimsMapping(user, claims); err != nil {
+			return nil, common.NewErrAuthUnauthorized("jwt", err.Error())
+		}
+	}
+
+	return user, nil
+}
+
+// applyClaimsMapping translates the configured claims into consumer-specific restrictions,
+// which are then enforced for each request (allowed networks/methods and rate limit budget).
+func (s *JwtStrategy) applyClaimsMapping(user *common.User, claims jwt.MapClaims) error {
+	cm := s.cfg.ClaimsMapping
+
+	if cm.UserIdClaim != "" {
+		if v, ok := claims[cm.UserIdClaim]; ok {
+			user.Id = claimValueToString(v)
+		}
+	}
+
+	if cm.NetworksClaim != "" {
+		if v, ok := claims[cm.NetworksClaim]; ok {
+			networks := claimToStrings(v)
+			if len(networks) == 0 {
+				// An explicitly empty list must not be treated as "no restriction"
+				return fmt.Errorf("the '%s' claim does not allow any network", cm.NetworksClaim)
+			}
+			for i, n := range networks {
+				if !strings.Contains(n, ":") {
+					// Plain chain ids are considered to be EVM networks
+					networks[i] = fmt.Sprintf("%s:%s", common.ArchitectureEvm, n)
+				}
+			}
+			user.AllowedNetworks = networks
+		}
+	}
+
+	if cm.MethodsClaim != "" {
+		if v, ok := claims[cm.MethodsClaim]; ok {
+			methods := claimToStrings(v)
+			if len(methods) == 0 {
+				return fmt.Errorf("the '%s' claim does not allow any method", cm.MethodsClaim)
+			}
+			user.AllowedMethods = methods
+		}
+	}
+
+	if cm.TierClaim != "" {
+		if v, ok := claims[cm.TierClaim]; ok {
+			tier := claimValueToString(v)
+			budget, ok := cm.TierBudgets[tier]
+			if !ok {
+				return fmt.Errorf("the '%s' tier is not allowed", tier)
+			}
+			user.RateLimitBudget = budget
+		}
 	}
 
 	return nil
 }
 
-func (s *JwtStrategy) findVerificationKey(token *jwt.Token) (jwt.Keyfunc, error) {
+// claimToStrings accepts either an array claim or a comma-separated string claim.
+func claimToStrings(v interface{}) []string {
+	var result []string
+	switch vv := v.(type) {
+	case []interface{}:
+		for _, item := range vv {
+			if str := strings.TrimSpace(claimValueToString(item)); str != "" {
+				result = append(result, str)
+			}
+		}
+	case string:
+		for _, item := range strings.Split(vv, ",") {
+			if str := strings.TrimSpace(item); str != "" {
+				result = append(result, str)
+			}
+		}
+	default:
+		if str := claimValueToString(vv); str != "" {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+func claimValueToString(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case float64:
+		// JSON numbers are decoded as float64 (e.g. chain ids)
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", vv)
+	}
+}
+
+func (s *JwtStrategy) findVerificationKey(ctx context.Context, token *jwt.Token) (jwt.Keyfunc, error) {
 	kid, ok := token.Header["kid"].(string)
 	if ok {
 		if key, exists := s.keys[kid]; exists {
 			return key, nil
 		}
+		if s.jwks != nil {
+			if key, exists := s.jwks.GetKey(ctx, kid); exists {
+				return staticKeyfunc(key), nil
+			}
+		}
 	}
 
 	// If no kid is provided or the kid doesn't match, try all keys
@@ -93,10 +216,24 @@ func (s *JwtStrategy) findVerificationKey(token *jwt.Token) (jwt.Keyfunc, error)
 			return key, nil
 		}
 	}
+	if s.jwks != nil && !ok {
+		for _, key := range s.jwks.Keys() {
+			kf := staticKeyfunc(key)
+			if isCompatibleKeyType(kf, token.Method) {
+				return kf, nil
+			}
+		}
+	}
 
 	return nil, fmt.Errorf("no suitable verification key found")
 }
 
+func staticKeyfunc(key interface{}) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		return key, nil
+	}
+}
+
 func (s *JwtStrategy) validateClaims(claims jwt.MapClaims) error {
 	if err := claims.Valid(); err != nil {
 		return fmt.Errorf("invalid standard claims: %w", err)
@@ -176,6 +313,9 @@ func isCompatibleKeyType(keyFn jwt.Keyfunc, method jwt.SigningMethod) bool {
 	case *jwt.SigningMethodHMAC:
 		_, ok := key.([]byte)
 		return ok
+	case *jwt.SigningMethodEd25519:
+		_, ok := key.(ed25519.PublicKey)
+		return ok
 	default:
 		return false
 	}
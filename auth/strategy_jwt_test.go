@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/upstream"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJwtStrategy_ClaimsMapping(t *testing.T) {
+	s := &JwtStrategy{
+		cfg: &common.JwtStrategyConfig{
+			ClaimsMapping: &common.JwtClaimsMappingConfig{
+				UserIdClaim:   "sub",
+				NetworksClaim: "networks",
+				MethodsClaim:  "methods",
+				TierClaim:     "tier",
+				TierBudgets: map[string]string{
+					"free": "free-budget",
+					"pro":  "pro-budget",
+				},
+			},
+		},
+	}
+
+	t.Run("MapsClaimsToUser", func(t *testing.T) {
+		user := &common.User{}
+		err := s.applyClaimsMapping(user, jwt.MapClaims{
+			"sub":      "tenant-1",
+			"networks": []interface{}{float64(1), "evm:42161"},
+			"methods":  "eth_getBalance, eth_call",
+			"tier":     "pro",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "tenant-1", user.Id)
+		assert.Equal(t, []string{"evm:1", "evm:42161"}, user.AllowedNetworks)
+		assert.Equal(t, []string{"eth_getBalance", "eth_call"}, user.AllowedMethods)
+		assert.Equal(t, "pro-budget", user.RateLimitBudget)
+
+		assert.True(t, user.IsNetworkAllowed("evm:1"))
+		assert.False(t, user.IsNetworkAllowed("evm:10"))
+		assert.True(t, user.IsMethodAllowed("eth_call"))
+		assert.False(t, user.IsMethodAllowed("eth_getLogs"))
+	})
+
+	t.Run("MissingClaimsMeanNoRestriction", func(t *testing.T) {
+		user := &common.User{}
+		err := s.applyClaimsMapping(user, jwt.MapClaims{"sub": "tenant-2"})
+		require.NoError(t, err)
+
+		assert.True(t, user.IsNetworkAllowed("evm:10"))
+		assert.True(t, user.IsMethodAllowed("eth_getLogs"))
+		assert.Equal(t, "", user.RateLimitBudget)
+	})
+
+	t.Run("RejectsUnknownTier", func(t *testing.T) {
+		err := s.applyClaimsMapping(&common.User{}, jwt.MapClaims{"tier": "enterprise"})
+		assert.Error(t, err)
+	})
+
+	t.Run("RejectsEmptyNetworksClaim", func(t *testing.T) {
+		err := s.applyClaimsMapping(&common.User{}, jwt.MapClaims{"networks": []interface{}{}})
+		assert.Error(t, err)
+	})
+}
+
+func TestJwtStrategy_EnforcesClaimsThroughRegistry(t *testing.T) {
+	logger := log.Logger
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rlCfg := &common.RateLimiterConfig{
+		Budgets: []*common.RateLimitBudgetConfig{{Id: "pro-budget"}},
+	}
+	rlr, err := upstream.NewRateLimitersRegistry(rlCfg, &logger)
+	require.NoError(t, err)
+
+	authCfg := &common.AuthConfig{
+		Strategies: []*common.AuthStrategyConfig{{
+			Type: common.AuthTypeJwt,
+			Jwt: &common.JwtStrategyConfig{
+				VerificationKeys: map[string]string{"k1": "test-secret"},
+				ClaimsMapping: &common.JwtClaimsMappingConfig{
+					NetworksClaim: "networks",
+					MethodsClaim:  "methods",
+					TierClaim:     "tier",
+					TierBudgets:   map[string]string{"pro": "pro-budget"},
+				},
+			},
+		}},
+	}
+	for _, s := range authCfg.Strategies {
+		s.SetDefaults()
+	}
+	require.NoError(t, authCfg.Validate(&common.Config{RateLimiters: rlCfg}))
+
+	registry, err := NewAuthRegistry(ctx, &logger, "test", authCfg, rlr)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":      "tenant-1",
+		"networks": []interface{}{float64(1)},
+		"methods":  "eth_get*",
+		"tier":     "pro",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	authenticate := func(networkId, method string) (*common.User, error) {
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`))
+		return registry.Authenticate(ctx, nq, &AuthPayload{
+			Type:      common.AuthTypeJwt,
+			NetworkId: networkId,
+			Jwt:       &JwtPayload{Token: signed},
+		})
+	}
+
+	user, err := authenticate("evm:1", "eth_getBalance")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-1", user.Id)
+	assert.Equal(t, "pro-budget", user.RateLimitBudget)
+
+	_, err = authenticate("evm:1", "eth_sendRawTransaction")
+	assert.ErrorContains(t, err, "method eth_sendRawTransaction is not allowed")
+
+	_, err = authenticate("evm:137", "eth_getBalance")
+	assert.ErrorContains(t, err, "network evm:137 is not allowed")
+
+	t.Run("ValidationRejectsUnknownTierBudget", func(t *testing.T) {
+		err := authCfg.Validate(&common.Config{RateLimiters: &common.RateLimiterConfig{}})
+		assert.ErrorContains(t, err, "tierBudgets.pro budget 'pro-budget' does not exist")
+	})
+}
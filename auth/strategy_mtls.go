@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+
+	"github.com/erpc/erpc/common"
+)
+
+type MtlsStrategy struct {
+	cfg                 *common.MtlsStrategyConfig
+	allowedFingerprints map[string]bool
+	identityBudgets     map[string]string
+}
+
+var _ AuthStrategy = &MtlsStrategy{}
+
+func NewMtlsStrategy(cfg *common.MtlsStrategyConfig) *MtlsStrategy {
+	s := &MtlsStrategy{
+		cfg:                 cfg,
+		allowedFingerprints: make(map[string]bool, len(cfg.AllowedFingerprints)),
+		identityBudgets:     make(map[string]string, len(cfg.IdentityBudgets)),
+	}
+
+	for _, fp := range cfg.AllowedFingerprints {
+		s.allowedFingerprints[normalizeFingerprint(fp)] = true
+	}
+
+	// Budgets can be keyed either by fingerprint or by subject identity
+	for identity, budget := range cfg.IdentityBudgets {
+		if fp := normalizeFingerprint(identity); isFingerprint(fp) {
+			s.identityBudgets[fp] = budget
+		} else {
+			s.identityBudgets[identity] = budget
+		}
+	}
+
+	return s
+}
+
+func (s *MtlsStrategy) Supports(ap *AuthPayload) bool {
+	return ap.Mtls != nil && ap.Mtls.Certificate != nil
+}
+
+func (s *MtlsStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (*common.User, error) {
+	if ap.Mtls == nil || ap.Mtls.Certificate == nil {
+		return nil, common.NewErrAuthUnauthorized("mtls", "missing verified client certificate")
+	}
+
+	cert := ap.Mtls.Certificate
+	fingerprint := SpkiFingerprint(cert)
+	identities := certificateIdentities(cert)
+
+	matched := ""
+	if s.allowedFingerprints[fingerprint] {
+		matched = fingerprint
+	} else {
+		matched = s.matchSubject(identities)
+	}
+	if matched == "" {
+		return nil, common.NewErrAuthUnauthorized("mtls", "client certificate is not allowed")
+	}
+
+	user := &common.User{Id: fingerprint}
+	if len(identities) > 0 {
+		user.Id = identities[0]
+	}
+
+	// Most specific budget wins: fingerprint first, then the identity that matched the allowlist, then any other identity
+	if budget, ok := s.identityBudgets[fingerprint]; ok {
+		user.RateLimitBudget = budget
+	} else if budget, ok := s.identityBudgets[matched]; ok {
+		user.RateLimitBudget = budget
+	} else {
+		for _, id := range identities {
+			if budget, ok := s.identityBudgets[id]; ok {
+				user.RateLimitBudget = budget
+				break
+			}
+		}
+	}
+
+	return user, nil
+}
+
+func (s *MtlsStrategy) matchSubject(identities []string) string {
+	for _, pattern := range s.cfg.AllowedSubjects {
+		for _, id := range identities {
+			if match, err := common.WildcardMatch(pattern, id); err == nil && match {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// certificateIdentities returns subject CN followed by all SANs (DNS names, emails and URIs) of the certificate.
+func certificateIdentities(cert *x509.Certificate) []string {
+	ids := make([]string, 0, 1+len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.URIs))
+	if cert.Subject.CommonName != "" {
+		ids = append(ids, cert.Subject.CommonName)
+	}
+	ids = append(ids, cert.DNSNames...)
+	ids = append(ids, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		ids = append(ids, u.String())
+	}
+	return ids
+}
+
+// SpkiFingerprint returns the hex-encoded sha256 hash of the certificate's SubjectPublicKeyInfo,
+// which stays the same when a certificate is re-issued for the same key pair.
+func SpkiFingerprint(cert *x509.Certificate) string {
+	h := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(h[:])
+}
+
+func normalizeFingerprint(fp string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fp), ":", ""))
+}
+
+func isFingerprint(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selfSignedCert(t *testing.T, cn string, dnsNames ...string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestMtlsStrategy(t *testing.T) {
+	ctx := context.Background()
+	indexer := selfSignedCert(t, "indexer", "indexer.internal.svc")
+	billing := selfSignedCert(t, "billing")
+	unknown := selfSignedCert(t, "unknown")
+
+	billingFp := SpkiFingerprint(billing)
+	colonFp := strings.ToUpper(billingFp[:2]) + ":" + billingFp[2:]
+
+	s := NewMtlsStrategy(&common.MtlsStrategyConfig{
+		AllowedSubjects:     []string{"*.internal.svc"},
+		AllowedFingerprints: []string{colonFp},
+		IdentityBudgets: map[string]string{
+			"indexer.internal.svc": "indexer-budget",
+			billingFp:              "billing-budget",
+		},
+	})
+
+	t.Run("AuthorizesBySubjectAlternativeName", func(t *testing.T) {
+		user, err := s.Authenticate(ctx, &AuthPayload{Mtls: &MtlsPayload{Certificate: indexer}})
+		require.NoError(t, err)
+		assert.Equal(t, "indexer", user.Id)
+		assert.Equal(t, "indexer-budget", user.RateLimitBudget)
+	})
+
+	t.Run("AuthorizesBySpkiFingerprint", func(t *testing.T) {
+		user, err := s.Authenticate(ctx, &AuthPayload{Mtls: &MtlsPayload{Certificate: billing}})
+		require.NoError(t, err)
+		assert.Equal(t, "billing", user.Id)
+		assert.Equal(t, "billing-budget", user.RateLimitBudget)
+	})
+
+	t.Run("RejectsCertificateNotInAllowlists", func(t *testing.T) {
+		_, err := s.Authenticate(ctx, &AuthPayload{Mtls: &MtlsPayload{Certificate: unknown}})
+		assert.Error(t, err)
+	})
+
+	t.Run("DoesNotSupportPayloadWithoutCertificate", func(t *testing.T) {
+		assert.False(t, s.Supports(&AuthPayload{Type: common.AuthTypeSecret}))
+	})
+}
@@ -2,53 +2,143 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
 	"github.com/spruceid/siwe-go"
 )
 
+// siweClockSkew tolerates small clock differences between the signer and eRPC for issuedAt and notBefore.
+const siweClockSkew = 30 * time.Second
+
 type SiweStrategy struct {
-	cfg *common.SiweStrategyConfig
+	cfg             *common.SiweStrategyConfig
+	sessionLifetime time.Duration
+	nonces          *SiweNonceStore
 }
 
 var _ AuthStrategy = &SiweStrategy{}
 
-func NewSiweStrategy(cfg *common.SiweStrategyConfig) *SiweStrategy {
-	return &SiweStrategy{cfg: cfg}
+func NewSiweStrategy(appCtx context.Context, logger *zerolog.Logger, cfg *common.SiweStrategyConfig) (*SiweStrategy, error) {
+	s := &SiweStrategy{cfg: cfg}
+
+	if cfg.SessionLifetime != "" {
+		d, err := time.ParseDuration(cfg.SessionLifetime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sessionLifetime: %w", err)
+		}
+		s.sessionLifetime = d
+	}
+
+	if cfg.Nonce != nil {
+		nonces, err := NewSiweNonceStore(appCtx, logger, cfg.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		s.nonces = nonces
+	}
+
+	return s, nil
 }
 
 func (s *SiweStrategy) Supports(ap *AuthPayload) bool {
 	return ap.Type == common.AuthTypeSiwe
 }
 
-func (s *SiweStrategy) Authenticate(ctx context.Context, ap *AuthPayload) error {
+func (s *SiweStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (*common.User, error) {
 	if ap.Siwe == nil {
-		return common.NewErrAuthUnauthorized("siwe", "missing SIWE payload")
+		return nil, common.NewErrAuthUnauthorized("siwe", "missing SIWE payload")
 	}
 
 	// Parse the SIWE message
 	message, err := siwe.ParseMessage(ap.Siwe.Message)
 	if err != nil {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to parse SIWE message: %s", err))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to parse SIWE message: %s", err))
 	}
 
 	// Verify the signature
 	if _, err := message.VerifyEIP191(ap.Siwe.Signature); err != nil {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to verify SIWE signature: %s", err))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to verify SIWE signature: %s", err))
 	}
 
 	// Check if the domain is allowed
 	if !s.isDomainAllowed(message.GetDomain()) {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("domain %s is not allowed", message.GetDomain()))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("domain %s is not allowed", message.GetDomain()))
+	}
+
+	// Verify the message is within its validity window
+	sessionEnd, err := s.validateTimes(message, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the nonce was issued by us and is not used by another message
+	if s.nonces != nil {
+		h := sha256.Sum256([]byte(message.String()))
+		if err := s.nonces.Consume(ctx, message.GetNonce(), hex.EncodeToString(h[:]), sessionEnd); err != nil {
+			return nil, err
+		}
+	}
+
+	return &common.User{
+		Id: message.GetAddress().Hex(),
+	}, nil
+}
+
+// IssueNonce returns a new nonce that clients must include in their next SIWE message.
+func (s *SiweStrategy) IssueNonce(ctx context.Context) (string, time.Time, error) {
+	if s.nonces == nil {
+		return "", time.Time{}, common.NewErrInvalidRequest(fmt.Errorf("nonce issuance is not enabled for siwe strategy"))
+	}
+	return s.nonces.Issue(ctx)
+}
+
+// validateTimes checks issuedAt, notBefore and expirationTime of the message, and returns when
+// the sign-in session ends based on expirationTime and the configured session lifetime (zero if unbounded).
+func (s *SiweStrategy) validateTimes(message *siwe.Message, now time.Time) (time.Time, error) {
+	issuedAt, err := time.Parse(time.RFC3339, message.GetIssuedAt())
+	if err != nil {
+		return time.Time{}, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("invalid issuedAt in SIWE message: %s", err))
+	}
+	if issuedAt.After(now.Add(siweClockSkew)) {
+		return time.Time{}, common.NewErrAuthUnauthorized("siwe", "SIWE message is issued in the future")
+	}
+
+	if nb := message.GetNotBefore(); nb != nil && *nb != "" {
+		notBefore, err := time.Parse(time.RFC3339, *nb)
+		if err != nil {
+			return time.Time{}, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("invalid notBefore in SIWE message: %s", err))
+		}
+		if now.Add(siweClockSkew).Before(notBefore) {
+			return time.Time{}, common.NewErrAuthUnauthorized("siwe", "SIWE message is not yet valid")
+		}
+	}
+
+	var sessionEnd time.Time
+	if exp := message.GetExpirationTime(); exp != nil && *exp != "" {
+		expiresAt, err := time.Parse(time.RFC3339, *exp)
+		if err != nil {
+			return time.Time{}, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("invalid expirationTime in SIWE message: %s", err))
+		}
+		sessionEnd = expiresAt
+	}
+
+	if s.sessionLifetime > 0 {
+		lifetimeEnd := issuedAt.Add(s.sessionLifetime)
+		if sessionEnd.IsZero() || lifetimeEnd.Before(sessionEnd) {
+			sessionEnd = lifetimeEnd
+		}
 	}
 
-	// Verify the message is not expired
-	if ok, err := message.ValidNow(); !ok {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("SIWE message expired: %s", err))
+	if !sessionEnd.IsZero() && !now.Before(sessionEnd) {
+		return time.Time{}, common.NewErrAuthUnauthorized("siwe", "SIWE message expired")
 	}
 
-	return nil
+	return sessionEnd, nil
 }
 
 func (s *SiweStrategy) isDomainAllowed(domain string) bool {
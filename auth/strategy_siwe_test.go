@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog/log"
+	"github.com/spruceid/siwe-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signSiweMessage(t *testing.T, nonce string, options map[string]interface{}) *SiwePayload {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	msg, err := siwe.InitMessage("app.localhost", address, "https://app.localhost", nonce, options)
+	require.NoError(t, err)
+
+	data := msg.String()
+	hash := crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)))
+	sig, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	return &SiwePayload{Message: msg.String(), Signature: hexutil.Encode(sig)}
+}
+
+func TestSiweStrategy(t *testing.T) {
+	logger := log.Logger
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := &common.SiweStrategyConfig{
+		AllowedDomains:  []string{"app.localhost"},
+		SessionLifetime: "1h",
+		Nonce:           &common.SiweNonceConfig{},
+	}
+	cfg.SetDefaults()
+
+	s, err := NewSiweStrategy(ctx, &logger, cfg)
+	require.NoError(t, err)
+
+	t.Run("AcceptsIssuedNonceOnlyForFirstMessage", func(t *testing.T) {
+		nonce, expiresAt, err := s.IssueNonce(ctx)
+		require.NoError(t, err)
+		assert.True(t, expiresAt.After(time.Now()))
+
+		first := signSiweMessage(t, nonce, nil)
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: first})
+		require.NoError(t, err)
+
+		// Same message keeps working for the rest of its session
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: first})
+		require.NoError(t, err)
+
+		// Another message cannot reuse the nonce
+		second := signSiweMessage(t, nonce, nil)
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: second})
+		assert.ErrorContains(t, err, "already used")
+	})
+
+	t.Run("RejectsNonceNotIssuedByServer", func(t *testing.T) {
+		payload := signSiweMessage(t, siwe.GenerateNonce(), nil)
+		_, err := s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: payload})
+		assert.ErrorContains(t, err, "unknown or expired")
+	})
+
+	t.Run("ValidatesNotBeforeAndExpirationTime", func(t *testing.T) {
+		nonce, _, err := s.IssueNonce(ctx)
+		require.NoError(t, err)
+
+		payload := signSiweMessage(t, nonce, map[string]interface{}{
+			"notBefore": time.Now().Add(time.Hour),
+		})
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: payload})
+		assert.ErrorContains(t, err, "not yet valid")
+
+		payload = signSiweMessage(t, nonce, map[string]interface{}{
+			"issuedAt":       time.Now().Add(-2 * time.Hour),
+			"expirationTime": time.Now().Add(-time.Minute),
+		})
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: payload})
+		assert.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("EnforcesSessionLifetime", func(t *testing.T) {
+		nonce, _, err := s.IssueNonce(ctx)
+		require.NoError(t, err)
+
+		payload := signSiweMessage(t, nonce, map[string]interface{}{
+			"issuedAt":       time.Now().Add(-2 * time.Hour),
+			"expirationTime": time.Now().Add(24 * time.Hour),
+		})
+		_, err = s.Authenticate(ctx, &AuthPayload{Type: common.AuthTypeSiwe, Siwe: payload})
+		assert.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("BoundsSessionLifetimeWhenNoncesAreEnabled", func(t *testing.T) {
+		dcfg := &common.SiweStrategyConfig{
+			AllowedDomains: []string{"app.localhost"},
+			Nonce:          &common.SiweNonceConfig{},
+		}
+		dcfg.SetDefaults()
+		assert.Equal(t, "24h", dcfg.SessionLifetime)
+		require.NoError(t, dcfg.Validate())
+
+		dcfg.SessionLifetime = ""
+		assert.ErrorContains(t, dcfg.Validate(), "sessionLifetime is required")
+	})
+
+	t.Run("NeverBindsNonceWithoutSessionEnd", func(t *testing.T) {
+		nonce, _, err := s.IssueNonce(ctx)
+		require.NoError(t, err)
+
+		err = s.nonces.Consume(ctx, nonce, "hash", time.Time{})
+		assert.ErrorContains(t, err, "session end is required")
+	})
+}
@@ -30,6 +30,18 @@ func init() {
 func main() {
 	logger := log.With().Logger()
 
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		summary, err := erpc.Replay(context.Background(), logger, afero.NewOsFs(), os.Stdout, os.Args[2:])
+		if err != nil {
+			logger.Error().Msgf("failed to replay captured requests: %v", err)
+			util.OsExit(util.ExitCodeReplayFailed)
+		}
+		if summary.Mismatch > 0 {
+			util.OsExit(util.ExitCodeReplayMismatch)
+		}
+		return
+	}
+
 	logger.Info().Msgf("starting eRPC version: %s, commit: %s", common.ErpcVersion, common.ErpcCommitSha)
 
 	err := erpc.Init(
@@ -33,9 +33,13 @@ type Config struct {
 	Projects     []*ProjectConfig   `yaml:"projects" json:"projects"`
 	RateLimiters *RateLimiterConfig `yaml:"rateLimiters" json:"rateLimiters"`
 	Metrics      *MetricsConfig     `yaml:"metrics" json:"metrics"`
+	Tracing      *TracingConfig     `yaml:"tracing,omitempty" json:"tracing"`
 }
 
 func (c *Config) HasRateLimiterBudget(id string) bool {
+	if c.RateLimiters == nil {
+		return false
+	}
 	for _, budget := range c.RateLimiters.Budgets {
 		if budget.Id == id {
 			return true
@@ -45,17 +49,48 @@ func (c *Config) HasRateLimiterBudget(id string) bool {
 }
 
 type ServerConfig struct {
-	ListenV4     *bool           `yaml:"listenV4,omitempty" json:"listenV4"`
-	HttpHostV4   *string         `yaml:"httpHostV4,omitempty" json:"httpHostV4"`
-	ListenV6     *bool           `yaml:"listenV6,omitempty" json:"listenV6"`
-	HttpHostV6   *string         `yaml:"httpHostV6,omitempty" json:"httpHostV6"`
-	HttpPort     *int            `yaml:"httpPort,omitempty" json:"httpPort"`
-	MaxTimeout   *string         `yaml:"maxTimeout,omitempty" json:"maxTimeout"`
-	ReadTimeout  *string         `yaml:"readTimeout,omitempty" json:"readTimeout"`
-	WriteTimeout *string         `yaml:"writeTimeout,omitempty" json:"writeTimeout"`
-	EnableGzip   *bool           `yaml:"enableGzip,omitempty" json:"enableGzip"`
-	TLS          *TLSConfig      `yaml:"tls,omitempty" json:"tls"`
-	Aliasing     *AliasingConfig `yaml:"aliasing" json:"aliasing"`
+	ListenV4     *bool            `yaml:"listenV4,omitempty" json:"listenV4"`
+	HttpHostV4   *string          `yaml:"httpHostV4,omitempty" json:"httpHostV4"`
+	ListenV6     *bool            `yaml:"listenV6,omitempty" json:"listenV6"`
+	HttpHostV6   *string          `yaml:"httpHostV6,omitempty" json:"httpHostV6"`
+	HttpPort     *int             `yaml:"httpPort,omitempty" json:"httpPort"`
+	MaxTimeout   *string          `yaml:"maxTimeout,omitempty" json:"maxTimeout"`
+	ReadTimeout  *string          `yaml:"readTimeout,omitempty" json:"readTimeout"`
+	WriteTimeout *string          `yaml:"writeTimeout,omitempty" json:"writeTimeout"`
+	EnableGzip   *bool            `yaml:"enableGzip,omitempty" json:"enableGzip"`
+	TLS          *TLSConfig       `yaml:"tls,omitempty" json:"tls"`
+	Aliasing     *AliasingConfig  `yaml:"aliasing" json:"aliasing"`
+	AccessLog    *AccessLogConfig `yaml:"accessLog,omitempty" json:"accessLog"`
+	// DebugHeaders controls whether responses carry X-ERPC-* headers exposing routing decisions
+	// (upstream, cache, attempts, retries, hedges and duration), and whether batch items can carry
+	// a "debug" field when requested via the debug directive.
+	DebugHeaders *bool `yaml:"debugHeaders,omitempty" json:"debugHeaders"`
+}
+
+// AccessLogConfig enables writing one structured (JSON) record per served request to stdout or a file.
+type AccessLogConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Output is either "stdout" or "file".
+	Output string `yaml:"output,omitempty" json:"output"`
+	// FilePath is required when output is "file", the file is rotated once it reaches maxSizeMB.
+	FilePath   string `yaml:"filePath,omitempty" json:"filePath"`
+	MaxSizeMB  int    `yaml:"maxSizeMB,omitempty" json:"maxSizeMB"`
+	MaxBackups int    `yaml:"maxBackups,omitempty" json:"maxBackups"`
+	// SampleRate (between 0 and 1) applies to requests that do not match any of the sampling rules,
+	// 0 disables logging of such requests.
+	SampleRate *float64                   `yaml:"sampleRate,omitempty" json:"sampleRate"`
+	Sampling   []*AccessLogSamplingConfig `yaml:"sampling,omitempty" json:"sampling"`
+	// IncludeParams adds request params to each record, params of methods matching redactMethods are redacted.
+	IncludeParams bool     `yaml:"includeParams,omitempty" json:"includeParams"`
+	RedactMethods []string `yaml:"redactMethods,omitempty" json:"redactMethods"`
+}
+
+// AccessLogSamplingConfig sets the sample rate of requests matching a method and response status code,
+// both of which can use "*" as wildcard (e.g. "eth_get*" and "5*"). The first matching rule applies.
+type AccessLogSamplingConfig struct {
+	Method string  `yaml:"method,omitempty" json:"method"`
+	Status string  `yaml:"status,omitempty" json:"status"`
+	Rate   float64 `yaml:"rate" json:"rate"`
 }
 
 type AdminConfig struct {
@@ -295,6 +330,48 @@ type ProjectConfig struct {
 	Networks         []*NetworkConfig   `yaml:"networks,omitempty" json:"networks"`
 	RateLimitBudget  string             `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget"`
 	HealthCheck      *HealthCheckConfig `yaml:"healthCheck,omitempty" json:"healthCheck"`
+	Capture          *CaptureConfig     `yaml:"capture,omitempty" json:"capture"`
+	Metering         *MeteringConfig    `yaml:"metering,omitempty" json:"metering"`
+}
+
+// MeteringConfig aggregates usage per consumer (the identity resolved by auth strategies) per minute in memory,
+// and periodically flushes completed minutes to a postgresql table or appends them to a newline-delimited JSON file.
+type MeteringConfig struct {
+	File                string                      `yaml:"file,omitempty" json:"file"`
+	Connector           *ConnectorConfig            `yaml:"connector,omitempty" json:"connector"`
+	TTL                 string                      `yaml:"ttl,omitempty" json:"ttl" tstype:"Duration"`
+	FlushInterval       string                      `yaml:"flushInterval,omitempty" json:"flushInterval" tstype:"Duration"`
+	DefaultComputeUnits int                         `yaml:"defaultComputeUnits,omitempty" json:"defaultComputeUnits"`
+	ComputeUnits        []*MethodComputeUnitsConfig `yaml:"computeUnits,omitempty" json:"computeUnits"`
+}
+
+// MethodComputeUnitsConfig sets how many compute units a request counts for when its method matches
+// the given pattern (can use "*" as wildcard). The first matching entry applies.
+type MethodComputeUnitsConfig struct {
+	Method string `yaml:"method" json:"method"`
+	Units  int    `yaml:"units" json:"units"`
+}
+
+// CaptureConfig stores requests along with the raw response (or error) of each upstream attempt and the final
+// response, for requests matching one of the sampling rules (or sent with the capture directive when allowed).
+// Captures are appended to a local JSONL file which can be replayed, or written to a connector (keyed by capture id).
+type CaptureConfig struct {
+	File      string                   `yaml:"file,omitempty" json:"file"`
+	Connector *ConnectorConfig         `yaml:"connector,omitempty" json:"connector"`
+	TTL       string                   `yaml:"ttl,omitempty" json:"ttl" tstype:"Duration"`
+	Sampling  []*CaptureSamplingConfig `yaml:"sampling,omitempty" json:"sampling"`
+	// AllowDirective lets clients capture their requests with the capture directive.
+	AllowDirective bool `yaml:"allowDirective,omitempty" json:"allowDirective"`
+	// Params of methods matching any of these patterns are redacted in the captured request.
+	RedactMethods []string `yaml:"redactMethods,omitempty" json:"redactMethods"`
+}
+
+// CaptureSamplingConfig captures a ratio of requests (between 0 and 1) of networks and methods matching
+// the given patterns, both of which can use "*" as wildcard.
+type CaptureSamplingConfig struct {
+	Network string  `yaml:"network,omitempty" json:"network"`
+	Method  string  `yaml:"method,omitempty" json:"method"`
+	Rate    float64 `yaml:"rate" json:"rate"`
 }
 
 type NetworkDefaults struct {
@@ -302,6 +379,8 @@ type NetworkDefaults struct {
 	Failsafe          *FailsafeConfig          `yaml:"failsafe,omitempty" json:"failsafe"`
 	SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty" json:"selectionPolicy"`
 	DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty" json:"directiveDefaults"`
+	UpstreamSelection *UpstreamSelectionConfig `yaml:"upstreamSelection,omitempty" json:"upstreamSelection"`
+	UpstreamGroups    []*UpstreamGroupConfig   `yaml:"upstreamGroups,omitempty" json:"upstreamGroups"`
 }
 
 type CORSConfig struct {
@@ -328,6 +407,28 @@ type UpstreamConfig struct {
 	RateLimitBudget              string                   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget"`
 	RateLimitAutoTune            *RateLimitAutoTuneConfig `yaml:"rateLimitAutoTune,omitempty" json:"rateLimitAutoTune"`
 	Routing                      *RoutingConfig           `yaml:"routing,omitempty" json:"routing"`
+	MaxConcurrency               int                      `yaml:"maxConcurrency,omitempty" json:"maxConcurrency"`
+	MaxConcurrencyQueueTimeout   string                   `yaml:"maxConcurrencyQueueTimeout,omitempty" json:"maxConcurrencyQueueTimeout" tstype:"Duration"`
+	HealthProbes                 []*HealthProbeConfig     `yaml:"healthProbes,omitempty" json:"healthProbes"`
+}
+
+// HealthProbeConfig defines an active probe periodically sent to the upstream independent of user traffic.
+type HealthProbeConfig struct {
+	Method           string                   `yaml:"method" json:"method"`
+	Params           []interface{}            `yaml:"params,omitempty" json:"params"`
+	Interval         string                   `yaml:"interval,omitempty" json:"interval" tstype:"Duration"`
+	Timeout          string                   `yaml:"timeout,omitempty" json:"timeout" tstype:"Duration"`
+	Expect           *HealthProbeExpectConfig `yaml:"expect,omitempty" json:"expect"`
+	FailureThreshold int                      `yaml:"failureThreshold,omitempty" json:"failureThreshold"`
+	SuccessThreshold int                      `yaml:"successThreshold,omitempty" json:"successThreshold"`
+	CordonMethod     string                   `yaml:"cordonMethod,omitempty" json:"cordonMethod"`
+}
+
+// HealthProbeExpectConfig defines how a probe result is matched, all defined conditions must pass.
+type HealthProbeExpectConfig struct {
+	NotEmpty bool        `yaml:"notEmpty,omitempty" json:"notEmpty"`
+	Equals   interface{} `yaml:"equals,omitempty" json:"equals"`
+	Pattern  string      `yaml:"pattern,omitempty" json:"pattern"`
 }
 
 type RoutingConfig struct {
@@ -344,6 +445,7 @@ type ScoreMultiplierConfig struct {
 	ThrottledRate   float64 `yaml:"throttledRate" json:"throttledRate"`
 	BlockHeadLag    float64 `yaml:"blockHeadLag" json:"blockHeadLag"`
 	FinalizationLag float64 `yaml:"finalizationLag" json:"finalizationLag"`
+	InFlight        float64 `yaml:"inFlight" json:"inFlight"`
 }
 
 func (u *UpstreamConfig) MarshalJSON() ([]byte, error) {
@@ -379,6 +481,8 @@ type EvmUpstreamConfig struct {
 	NodeType                 EvmNodeType `yaml:"nodeType,omitempty" json:"nodeType"`
 	StatePollerInterval      string      `yaml:"statePollerInterval,omitempty" json:"statePollerInterval"`
 	MaxAvailableRecentBlocks int64       `yaml:"maxAvailableRecentBlocks,omitempty" json:"maxAvailableRecentBlocks"`
+	MaxBlockHeadLag          int64       `yaml:"maxBlockHeadLag,omitempty" json:"maxBlockHeadLag"`
+	MaxFinalizationLag       int64       `yaml:"maxFinalizationLag,omitempty" json:"maxFinalizationLag"`
 }
 
 type FailsafeConfig struct {
@@ -386,6 +490,51 @@ type FailsafeConfig struct {
 	CircuitBreaker *CircuitBreakerPolicyConfig `yaml:"circuitBreaker" json:"circuitBreaker"`
 	Timeout        *TimeoutPolicyConfig        `yaml:"timeout" json:"timeout"`
 	Hedge          *HedgePolicyConfig          `yaml:"hedge" json:"hedge"`
+
+	// MethodOverrides replace timeout, retry and/or hedge policies for matching methods (first match wins),
+	// e.g. to allow a longer timeout for debug_traceBlockByNumber or large eth_getLogs.
+	MethodOverrides []*FailsafeMethodOverrideConfig `yaml:"methodOverrides,omitempty" json:"methodOverrides"`
+
+	// RetryBudget caps retries and hedges of this network or upstream (shared across all methods)
+	// to avoid multiplying load when all upstreams are degraded.
+	RetryBudget *RetryBudgetConfig `yaml:"retryBudget,omitempty" json:"retryBudget"`
+}
+
+// RetryBudgetConfig is a token bucket where each request deposits "ratio" tokens (expiring after "window"),
+// and each retry or hedge withdraws one token. When the bucket is empty retries and hedges are suppressed.
+// Ratio and MinRetries can explicitly be set to 0, e.g. to only allow minRetries regardless of traffic.
+type RetryBudgetConfig struct {
+	Ratio      *float64 `yaml:"ratio,omitempty" json:"ratio"`
+	Window     string   `yaml:"window" json:"window" tstype:"Duration"`
+	MinRetries *int     `yaml:"minRetries,omitempty" json:"minRetries"`
+}
+
+type FailsafeMethodOverrideConfig struct {
+	Method  string               `yaml:"method" json:"method"`
+	Retry   *RetryPolicyConfig   `yaml:"retry,omitempty" json:"retry"`
+	Timeout *TimeoutPolicyConfig `yaml:"timeout,omitempty" json:"timeout"`
+	Hedge   *HedgePolicyConfig   `yaml:"hedge,omitempty" json:"hedge"`
+}
+
+// WithMethodOverride returns a copy of the failsafe config where policies defined in the override
+// replace the base ones, and the rest of policies are inherited from the base config.
+func (f *FailsafeConfig) WithMethodOverride(o *FailsafeMethodOverrideConfig) *FailsafeConfig {
+	merged := &FailsafeConfig{
+		Retry:          f.Retry,
+		CircuitBreaker: f.CircuitBreaker,
+		Timeout:        f.Timeout,
+		Hedge:          f.Hedge,
+	}
+	if o.Retry != nil {
+		merged.Retry = o.Retry
+	}
+	if o.Timeout != nil {
+		merged.Timeout = o.Timeout
+	}
+	if o.Hedge != nil {
+		merged.Hedge = o.Hedge
+	}
+	return merged
 }
 
 type RetryPolicyConfig struct {
@@ -402,15 +551,47 @@ type CircuitBreakerPolicyConfig struct {
 	HalfOpenAfter            string `yaml:"halfOpenAfter" json:"halfOpenAfter"`
 	SuccessThresholdCount    uint   `yaml:"successThresholdCount" json:"successThresholdCount"`
 	SuccessThresholdCapacity uint   `yaml:"successThresholdCapacity" json:"successThresholdCapacity"`
+
+	// Scope defines how breakers are keyed for an upstream, so that failures of one method
+	// (e.g. debug_traceTransaction) do not block healthy traffic of other methods.
+	Scope        CircuitBreakerScope                `yaml:"scope,omitempty" json:"scope"`
+	MethodGroups []*CircuitBreakerMethodGroupConfig `yaml:"methodGroups,omitempty" json:"methodGroups"`
+}
+
+type CircuitBreakerScope string
+
+const (
+	CircuitBreakerScopeUpstream    CircuitBreakerScope = "upstream"
+	CircuitBreakerScopeMethod      CircuitBreakerScope = "method"
+	CircuitBreakerScopeMethodGroup CircuitBreakerScope = "methodGroup"
+)
+
+type CircuitBreakerMethodGroupConfig struct {
+	Name    string `yaml:"name" json:"name"`
+	Methods string `yaml:"methods" json:"methods"`
 }
 
 type TimeoutPolicyConfig struct {
 	Duration string `yaml:"duration" json:"duration" tstype:"Duration"`
+
+	// When quantile is set the timeout is derived from observed latency (quantile × factor) per upstream and method,
+	// clamped between minDuration and maxDuration. Duration is used until there is enough data.
+	Quantile    float64 `yaml:"quantile,omitempty" json:"quantile"`
+	Factor      float64 `yaml:"factor,omitempty" json:"factor"`
+	MinDuration string  `yaml:"minDuration,omitempty" json:"minDuration" tstype:"Duration"`
+	MaxDuration string  `yaml:"maxDuration,omitempty" json:"maxDuration" tstype:"Duration"`
 }
 
 type HedgePolicyConfig struct {
 	Delay    string `yaml:"delay" json:"delay"`
 	MaxCount int    `yaml:"maxCount" json:"maxCount"`
+
+	// When quantile is set the hedge delay is derived from observed latency (quantile × factor) of the upstream
+	// being tried, clamped between minDelay and maxDelay. Delay is used until there is enough data.
+	Quantile float64 `yaml:"quantile,omitempty" json:"quantile"`
+	Factor   float64 `yaml:"factor,omitempty" json:"factor"`
+	MinDelay string  `yaml:"minDelay,omitempty" json:"minDelay" tstype:"Duration"`
+	MaxDelay string  `yaml:"maxDelay,omitempty" json:"maxDelay" tstype:"Duration"`
 }
 
 type RateLimiterConfig struct {
@@ -430,7 +611,31 @@ type RateLimitRuleConfig struct {
 }
 
 type HealthCheckConfig struct {
-	ScoreMetricsWindowSize string `yaml:"scoreMetricsWindowSize" json:"scoreMetricsWindowSize"`
+	ScoreMetricsWindowSize string                   `yaml:"scoreMetricsWindowSize" json:"scoreMetricsWindowSize"`
+	MinHealthyUpstreams    *int                     `yaml:"minHealthyUpstreams,omitempty" json:"minHealthyUpstreams"`
+	MaxErrorRate           *float64                 `yaml:"maxErrorRate,omitempty" json:"maxErrorRate"`
+	SharedState            *SharedStateConfig       `yaml:"sharedState,omitempty" json:"sharedState"`
+	Persistence            *HealthPersistenceConfig `yaml:"persistence,omitempty" json:"persistence"`
+}
+
+// HealthPersistenceConfig periodically snapshots tracked metrics, cordons and autotuned rate limit budgets
+// to a connector or a local file, so that routing decisions survive restarts.
+type HealthPersistenceConfig struct {
+	File          string           `yaml:"file,omitempty" json:"file"`
+	Connector     *ConnectorConfig `yaml:"connector,omitempty" json:"connector"`
+	Interval      string           `yaml:"interval,omitempty" json:"interval" tstype:"Duration"`
+	MaxAge        string           `yaml:"maxAge,omitempty" json:"maxAge" tstype:"Duration"`
+	DecayHalfLife string           `yaml:"decayHalfLife,omitempty" json:"decayHalfLife" tstype:"Duration"`
+}
+
+// SharedStateConfig enables sharing upstream health signals (cordons, error rates, rate-limit hits
+// and latest blocks) across eRPC replicas so that the whole fleet reacts to an outage together.
+// Signals are broadcast via pub/sub of the connector, which must use the redis driver.
+type SharedStateConfig struct {
+	Connector    *ConnectorConfig `yaml:"connector" json:"connector"`
+	Channel      string           `yaml:"channel,omitempty" json:"channel"`
+	SyncInterval string           `yaml:"syncInterval,omitempty" json:"syncInterval" tstype:"Duration"`
+	StaleAfter   string           `yaml:"staleAfter,omitempty" json:"staleAfter" tstype:"Duration"`
 }
 
 type NetworkConfig struct {
@@ -440,11 +645,57 @@ type NetworkConfig struct {
 	Evm               *EvmNetworkConfig        `yaml:"evm,omitempty" json:"evm"`
 	SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty" json:"selectionPolicy"`
 	DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty" json:"directiveDefaults"`
+	UpstreamSelection *UpstreamSelectionConfig `yaml:"upstreamSelection,omitempty" json:"upstreamSelection"`
+
+	// UpstreamGroups are tried in order (matched by upstream "group"), the next group is only used when
+	// all upstreams of previous groups are exhausted or fewer than minHealthyUpstreams of them are healthy.
+	// Upstreams of groups not listed here are tried last.
+	UpstreamGroups []*UpstreamGroupConfig `yaml:"upstreamGroups,omitempty" json:"upstreamGroups"`
+}
+
+type UpstreamGroupConfig struct {
+	Name                string `yaml:"name" json:"name"`
+	MinHealthyUpstreams int    `yaml:"minHealthyUpstreams,omitempty" json:"minHealthyUpstreams"`
+}
+
+type UpstreamSelectionMode string
+
+const (
+	// Always try upstreams in order of their score, the best upstream receives all traffic until its score drops.
+	UpstreamSelectionModeOrdered UpstreamSelectionMode = "ordered"
+	// Pick upstreams by random sampling weighted by their score, so that load is spread proportionally.
+	UpstreamSelectionModeWeightedRandom UpstreamSelectionMode = "weighted-random"
+	// Pick the better of two random upstreams based on in-flight requests and p90 latency.
+	UpstreamSelectionModePowerOfTwoChoices UpstreamSelectionMode = "power-of-two-choices"
+)
+
+type UpstreamSelectionConfig struct {
+	Mode   UpstreamSelectionMode `yaml:"mode,omitempty" json:"mode"`
+	Sticky *StickySessionConfig  `yaml:"sticky,omitempty" json:"sticky"`
+}
+
+type StickySessionKey string
+
+const (
+	StickySessionKeyIdentity StickySessionKey = "identity"
+	StickySessionKeyHeader   StickySessionKey = "header"
+	StickySessionKeyIP       StickySessionKey = "ip"
+)
+
+type StickySessionConfig struct {
+	// What identifies a client session: authenticated consumer identity, a request header or client IP.
+	KeyBy StickySessionKey `yaml:"keyBy,omitempty" json:"keyBy"`
+	// Header name used as session key when keyBy is "header".
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+	// How long requests of a session are pinned to the upstream that served it.
+	Window      string `yaml:"window,omitempty" json:"window" tstype:"Duration"`
+	MaxSessions int    `yaml:"maxSessions,omitempty" json:"maxSessions"`
 }
 
 type DirectiveDefaultsConfig struct {
 	RetryEmpty    *bool   `yaml:"retryEmpty,omitempty" json:"retryEmpty"`
 	RetryPending  *bool   `yaml:"retryPending,omitempty" json:"retryPending"`
+	VerifyEmpty   *bool   `yaml:"verifyEmpty,omitempty" json:"verifyEmpty"`
 	SkipCacheRead *bool   `yaml:"skipCacheRead,omitempty" json:"skipCacheRead"`
 	UseUpstream   *string `yaml:"useUpstream,omitempty" json:"useUpstream"`
 }
@@ -530,10 +781,12 @@ func (c *SelectionPolicyConfig) MarshalJSON() ([]byte, error) {
 type AuthType string
 
 const (
-	AuthTypeSecret  AuthType = "secret"
-	AuthTypeJwt     AuthType = "jwt"
-	AuthTypeSiwe    AuthType = "siwe"
-	AuthTypeNetwork AuthType = "network"
+	AuthTypeSecret   AuthType = "secret"
+	AuthTypeJwt      AuthType = "jwt"
+	AuthTypeSiwe     AuthType = "siwe"
+	AuthTypeNetwork  AuthType = "network"
+	AuthTypeDatabase AuthType = "database"
+	AuthTypeMtls     AuthType = "mtls"
 )
 
 type AuthConfig struct {
@@ -545,11 +798,13 @@ type AuthStrategyConfig struct {
 	AllowMethods    []string `yaml:"allowMethods,omitempty" json:"allowMethods,omitempty"`
 	RateLimitBudget string   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget,omitempty"`
 
-	Type    AuthType               `yaml:"type" json:"type" tstype:"TsAuthType"`
-	Network *NetworkStrategyConfig `yaml:"network,omitempty" json:"network,omitempty"`
-	Secret  *SecretStrategyConfig  `yaml:"secret,omitempty" json:"secret,omitempty"`
-	Jwt     *JwtStrategyConfig     `yaml:"jwt,omitempty" json:"jwt,omitempty"`
-	Siwe    *SiweStrategyConfig    `yaml:"siwe,omitempty" json:"siwe,omitempty"`
+	Type     AuthType                `yaml:"type" json:"type" tstype:"TsAuthType"`
+	Network  *NetworkStrategyConfig  `yaml:"network,omitempty" json:"network,omitempty"`
+	Secret   *SecretStrategyConfig   `yaml:"secret,omitempty" json:"secret,omitempty"`
+	Jwt      *JwtStrategyConfig      `yaml:"jwt,omitempty" json:"jwt,omitempty"`
+	Siwe     *SiweStrategyConfig     `yaml:"siwe,omitempty" json:"siwe,omitempty"`
+	Database *DatabaseStrategyConfig `yaml:"database,omitempty" json:"database,omitempty"`
+	Mtls     *MtlsStrategyConfig     `yaml:"mtls,omitempty" json:"mtls,omitempty"`
 }
 
 type SecretStrategyConfig struct {
@@ -569,10 +824,57 @@ type JwtStrategyConfig struct {
 	AllowedAlgorithms []string          `yaml:"allowedAlgorithms" json:"allowedAlgorithms"`
 	RequiredClaims    []string          `yaml:"requiredClaims" json:"requiredClaims"`
 	VerificationKeys  map[string]string `yaml:"verificationKeys" json:"verificationKeys"`
+	// JWKS endpoint of the identity provider, used in addition to (or instead of) static verification keys.
+	JwksUri                string `yaml:"jwksUri,omitempty" json:"jwksUri,omitempty"`
+	JwksRefreshInterval    string `yaml:"jwksRefreshInterval,omitempty" json:"jwksRefreshInterval,omitempty" tstype:"Duration"`
+	JwksMinRefreshInterval string `yaml:"jwksMinRefreshInterval,omitempty" json:"jwksMinRefreshInterval,omitempty" tstype:"Duration"`
+	// Optional mapping of token claims to per-consumer authorization (allowed networks, methods and rate limit budget).
+	ClaimsMapping *JwtClaimsMappingConfig `yaml:"claimsMapping,omitempty" json:"claimsMapping,omitempty"`
+}
+
+type JwtClaimsMappingConfig struct {
+	// Claim used as the consumer identifier (default: "sub").
+	UserIdClaim string `yaml:"userIdClaim,omitempty" json:"userIdClaim,omitempty"`
+	// Claim containing the allowed networks, either chain ids (e.g. 42161) or network ids (e.g. "evm:42161").
+	NetworksClaim string `yaml:"networksClaim,omitempty" json:"networksClaim,omitempty"`
+	// Claim containing the allowed methods, wildcards are supported (e.g. "eth_*").
+	MethodsClaim string `yaml:"methodsClaim,omitempty" json:"methodsClaim,omitempty"`
+	// Claim containing the tier of the consumer, which selects a rate limit budget from tierBudgets.
+	TierClaim   string            `yaml:"tierClaim,omitempty" json:"tierClaim,omitempty"`
+	TierBudgets map[string]string `yaml:"tierBudgets,omitempty" json:"tierBudgets,omitempty"`
+}
+
+type DatabaseStrategyConfig struct {
+	// Connector where API keys are stored, keyed by the hex-encoded sha256 hash of the key.
+	Connector *ConnectorConfig `yaml:"connector" json:"connector" tstype:"TsConnectorConfig"`
+	// How long a verified (or rejected) key is cached in memory, which also
+	// defines how quickly a revoked key stops being accepted.
+	CacheTTL      string `yaml:"cacheTTL,omitempty" json:"cacheTTL" tstype:"Duration"`
+	CacheMaxItems int    `yaml:"cacheMaxItems,omitempty" json:"cacheMaxItems"`
+}
+
+type MtlsStrategyConfig struct {
+	// Allowed client certificate identities matched against subject CN and SANs (DNS, email, URI), wildcards are supported.
+	AllowedSubjects []string `yaml:"allowedSubjects,omitempty" json:"allowedSubjects,omitempty"`
+	// Allowed hex-encoded sha256 fingerprints of client certificates' public key (SPKI).
+	AllowedFingerprints []string `yaml:"allowedFingerprints,omitempty" json:"allowedFingerprints,omitempty"`
+	// Rate limit budget per certificate identity, keyed by subject CN/SAN or SPKI fingerprint.
+	IdentityBudgets map[string]string `yaml:"identityBudgets,omitempty" json:"identityBudgets,omitempty"`
 }
 
 type SiweStrategyConfig struct {
 	AllowedDomains []string `yaml:"allowedDomains" json:"allowedDomains"`
+	// Maximum duration a signed message is accepted for after its issuedAt, even if its expirationTime is later.
+	SessionLifetime string `yaml:"sessionLifetime,omitempty" json:"sessionLifetime,omitempty" tstype:"Duration"`
+	// When set, messages must carry a nonce issued by eRPC which is bound to the first message that uses it.
+	Nonce *SiweNonceConfig `yaml:"nonce,omitempty" json:"nonce,omitempty"`
+}
+
+type SiweNonceConfig struct {
+	// Connector where issued nonces are stored, which must be shared when running multiple eRPC instances.
+	Connector *ConnectorConfig `yaml:"connector,omitempty" json:"connector,omitempty" tstype:"TsConnectorConfig"`
+	// How long an issued nonce can be used to sign in before it expires.
+	TTL string `yaml:"ttl,omitempty" json:"ttl" tstype:"Duration"`
 }
 
 type NetworkStrategyConfig struct {
@@ -591,6 +893,18 @@ type MetricsConfig struct {
 	Port     *int    `yaml:"port" json:"port"`
 }
 
+// TracingConfig enables exporting request traces to an OpenTelemetry collector via OTLP over HTTP.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Endpoint of the OTLP/HTTP collector (e.g. http://localhost:4318), "/v1/traces" is appended when no path is set.
+	Endpoint      string            `yaml:"endpoint" json:"endpoint"`
+	Headers       map[string]string `yaml:"headers,omitempty" json:"headers"`
+	ServiceName   string            `yaml:"serviceName,omitempty" json:"serviceName"`
+	SampleRate    *float64          `yaml:"sampleRate,omitempty" json:"sampleRate"`
+	BatchSize     int               `yaml:"batchSize,omitempty" json:"batchSize"`
+	FlushInterval string            `yaml:"flushInterval,omitempty" json:"flushInterval" tstype:"Duration"`
+}
+
 var cfgInstance *Config
 
 // LoadConfig loads the configuration from the specified file.
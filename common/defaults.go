@@ -71,6 +71,10 @@ func (c *Config) SetDefaults() {
 	}
 	c.Metrics.SetDefaults()
 
+	if c.Tracing != nil {
+		c.Tracing.SetDefaults()
+	}
+
 	if c.Admin != nil {
 		c.Admin.SetDefaults()
 	}
@@ -380,6 +384,43 @@ func (s *ServerConfig) SetDefaults() {
 	if s.EnableGzip == nil {
 		s.EnableGzip = util.BoolPtr(true)
 	}
+	if s.DebugHeaders == nil {
+		s.DebugHeaders = util.BoolPtr(true)
+	}
+	if s.AccessLog != nil {
+		s.AccessLog.SetDefaults()
+	}
+}
+
+func (a *AccessLogConfig) SetDefaults() {
+	if a.Output == "" {
+		a.Output = "stdout"
+	}
+	if a.MaxSizeMB == 0 {
+		a.MaxSizeMB = 100
+	}
+	if a.MaxBackups == 0 {
+		a.MaxBackups = 5
+	}
+	if a.SampleRate == nil {
+		a.SampleRate = util.Float64Ptr(1)
+	}
+	if a.RedactMethods == nil {
+		a.RedactMethods = []string{
+			"eth_sendRawTransaction",
+			"eth_sendTransaction",
+			"eth_sign*",
+			"personal_*",
+		}
+	}
+	for _, rule := range a.Sampling {
+		if rule.Method == "" {
+			rule.Method = "*"
+		}
+		if rule.Status == "" {
+			rule.Status = "*"
+		}
+	}
 }
 
 func (m *MetricsConfig) SetDefaults() {
@@ -394,6 +435,21 @@ func (m *MetricsConfig) SetDefaults() {
 	}
 }
 
+func (t *TracingConfig) SetDefaults() {
+	if t.ServiceName == "" {
+		t.ServiceName = "erpc"
+	}
+	if t.SampleRate == nil {
+		t.SampleRate = util.Float64Ptr(1)
+	}
+	if t.BatchSize == 0 {
+		t.BatchSize = 512
+	}
+	if t.FlushInterval == "" {
+		t.FlushInterval = "5s"
+	}
+}
+
 func (a *AdminConfig) SetDefaults() {
 	if a.Auth != nil {
 		a.Auth.SetDefaults()
@@ -526,6 +582,59 @@ func (p *ProjectConfig) SetDefaults() {
 		p.HealthCheck = &HealthCheckConfig{}
 	}
 	p.HealthCheck.SetDefaults()
+	if p.Capture != nil {
+		p.Capture.SetDefaults()
+	}
+	if p.Metering != nil {
+		p.Metering.SetDefaults()
+	}
+}
+
+func (m *MeteringConfig) SetDefaults() {
+	if m.Connector != nil {
+		if m.Connector.Driver == DriverPostgreSQL && m.Connector.PostgreSQL == nil {
+			m.Connector.PostgreSQL = &PostgreSQLConnectorConfig{}
+		}
+		// Usage has its own table schema, so it must not share the default cache table
+		if m.Connector.PostgreSQL != nil && m.Connector.PostgreSQL.Table == "" {
+			m.Connector.PostgreSQL.Table = "erpc_usage"
+		}
+		m.Connector.SetDefaults()
+	}
+	if m.TTL == "" {
+		m.TTL = "2160h"
+	}
+	if m.FlushInterval == "" {
+		m.FlushInterval = "1m"
+	}
+	if m.DefaultComputeUnits == 0 {
+		m.DefaultComputeUnits = 1
+	}
+}
+
+func (c *CaptureConfig) SetDefaults() {
+	if c.Connector != nil {
+		c.Connector.SetDefaults()
+	}
+	if c.TTL == "" {
+		c.TTL = "168h"
+	}
+	if c.RedactMethods == nil {
+		c.RedactMethods = []string{
+			"eth_sendRawTransaction",
+			"eth_sendTransaction",
+			"eth_sign*",
+			"personal_*",
+		}
+	}
+	for _, rule := range c.Sampling {
+		if rule.Network == "" {
+			rule.Network = "*"
+		}
+		if rule.Method == "" {
+			rule.Method = "*"
+		}
+	}
 }
 
 func (u *UpstreamConfig) ApplyDefaults(defaults *UpstreamConfig) {
@@ -562,6 +671,8 @@ func (u *UpstreamConfig) ApplyDefaults(defaults *UpstreamConfig) {
 			NodeType:                 defaults.Evm.NodeType,
 			StatePollerInterval:      defaults.Evm.StatePollerInterval,
 			MaxAvailableRecentBlocks: defaults.Evm.MaxAvailableRecentBlocks,
+			MaxBlockHeadLag:          defaults.Evm.MaxBlockHeadLag,
+			MaxFinalizationLag:       defaults.Evm.MaxFinalizationLag,
 		}
 	}
 	if u.JsonRpc == nil && defaults.JsonRpc != nil {
@@ -584,6 +695,19 @@ func (u *UpstreamConfig) ApplyDefaults(defaults *UpstreamConfig) {
 	if u.AutoIgnoreUnsupportedMethods == nil && defaults.AutoIgnoreUnsupportedMethods != nil {
 		u.AutoIgnoreUnsupportedMethods = defaults.AutoIgnoreUnsupportedMethods
 	}
+	if u.MaxConcurrency == 0 {
+		u.MaxConcurrency = defaults.MaxConcurrency
+	}
+	if u.MaxConcurrencyQueueTimeout == "" {
+		u.MaxConcurrencyQueueTimeout = defaults.MaxConcurrencyQueueTimeout
+	}
+	if u.HealthProbes == nil && defaults.HealthProbes != nil {
+		u.HealthProbes = make([]*HealthProbeConfig, 0, len(defaults.HealthProbes))
+		for _, probe := range defaults.HealthProbes {
+			cp := *probe
+			u.HealthProbes = append(u.HealthProbes, &cp)
+		}
+	}
 }
 
 func (u *UpstreamConfig) SetDefaults() {
@@ -632,6 +756,10 @@ func (u *UpstreamConfig) SetDefaults() {
 		u.Routing.SetDefaults()
 	}
 
+	for _, probe := range u.HealthProbes {
+		probe.SetDefaults()
+	}
+
 	// By default if any allowed methods are specified, all other methods are ignored (unless ignoreMethods is explicitly defined by user)
 	// Similar to how common network security policies work.
 	if u.AllowMethods != nil {
@@ -641,6 +769,27 @@ func (u *UpstreamConfig) SetDefaults() {
 	}
 }
 
+func (p *HealthProbeConfig) SetDefaults() {
+	if p.Params == nil {
+		p.Params = []interface{}{}
+	}
+	if p.Interval == "" {
+		p.Interval = "30s"
+	}
+	if p.Timeout == "" {
+		p.Timeout = "5s"
+	}
+	if p.FailureThreshold == 0 {
+		p.FailureThreshold = 3
+	}
+	if p.SuccessThreshold == 0 {
+		p.SuccessThreshold = 1
+	}
+	if p.CordonMethod == "" {
+		p.CordonMethod = p.Method
+	}
+}
+
 func (e *EvmUpstreamConfig) SetDefaults() {
 	if e.StatePollerInterval == "" {
 		e.StatePollerInterval = "30s"
@@ -682,6 +831,13 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 			n.DirectiveDefaults = &DirectiveDefaultsConfig{}
 			*n.DirectiveDefaults = *defaults.DirectiveDefaults
 		}
+		if n.UpstreamSelection == nil && defaults.UpstreamSelection != nil {
+			n.UpstreamSelection = &UpstreamSelectionConfig{}
+			*n.UpstreamSelection = *defaults.UpstreamSelection
+		}
+		if n.UpstreamGroups == nil && defaults.UpstreamGroups != nil {
+			n.UpstreamGroups = defaults.UpstreamGroups
+		}
 	} else if n.Failsafe != nil {
 		n.Failsafe.SetDefaults(sysDefCfg.Failsafe)
 	} else {
@@ -705,7 +861,8 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 		anyUpstreamInFallbackGroup := slices.ContainsFunc(upstreams, func(u *UpstreamConfig) bool {
 			return u.Group == "fallback"
 		})
-		if anyUpstreamInFallbackGroup && n.SelectionPolicy == nil {
+		// Explicit upstream groups already decide when fallback upstreams are used
+		if anyUpstreamInFallbackGroup && n.SelectionPolicy == nil && len(n.UpstreamGroups) == 0 {
 			defCfg := NewDefaultNetworkConfig(upstreams)
 			n.SelectionPolicy = defCfg.SelectionPolicy
 		}
@@ -713,6 +870,34 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 	if n.SelectionPolicy != nil {
 		n.SelectionPolicy.SetDefaults()
 	}
+	if n.UpstreamSelection != nil {
+		n.UpstreamSelection.SetDefaults()
+	}
+}
+
+func (s *UpstreamSelectionConfig) SetDefaults() {
+	if s.Mode == "" {
+		s.Mode = UpstreamSelectionModeOrdered
+	}
+	if s.Sticky != nil {
+		s.Sticky.SetDefaults()
+	}
+}
+
+func (s *StickySessionConfig) SetDefaults() {
+	if s.KeyBy == "" {
+		if s.Header != "" {
+			s.KeyBy = StickySessionKeyHeader
+		} else {
+			s.KeyBy = StickySessionKeyIdentity
+		}
+	}
+	if s.Window == "" {
+		s.Window = "30s"
+	}
+	if s.MaxSessions == 0 {
+		s.MaxSessions = 100_000
+	}
 }
 
 const DefaultEvmFinalityDepth = 1024
@@ -745,12 +930,73 @@ func (f *FailsafeConfig) SetDefaults(defaults *FailsafeConfig) {
 			f.CircuitBreaker.SetDefaults(nil)
 		}
 	}
+	if f.Hedge != nil {
+		if defaults != nil && defaults.Hedge != nil {
+			f.Hedge.SetDefaults(defaults.Hedge)
+		} else {
+			f.Hedge.SetDefaults(nil)
+		}
+	}
+	if f.RetryBudget == nil && defaults != nil && defaults.RetryBudget != nil {
+		f.RetryBudget = &RetryBudgetConfig{}
+		*f.RetryBudget = *defaults.RetryBudget
+	}
+	if f.RetryBudget != nil {
+		f.RetryBudget.SetDefaults()
+	}
+	if f.MethodOverrides == nil && defaults != nil {
+		f.MethodOverrides = defaults.MethodOverrides
+	}
+	for _, o := range f.MethodOverrides {
+		if o.Timeout != nil {
+			o.Timeout.SetDefaults(f.Timeout)
+		}
+		if o.Retry != nil {
+			o.Retry.SetDefaults(f.Retry)
+		}
+		if o.Hedge != nil {
+			o.Hedge.SetDefaults(f.Hedge)
+		}
+	}
+}
+
+func (r *RetryBudgetConfig) SetDefaults() {
+	if r.Ratio == nil {
+		r.Ratio = util.Float64Ptr(0.2)
+	}
+	if r.Window == "" {
+		r.Window = "10s"
+	}
+	if r.MinRetries == nil {
+		r.MinRetries = util.IntPtr(10)
+	}
 }
 
 func (t *TimeoutPolicyConfig) SetDefaults(defaults *TimeoutPolicyConfig) {
 	if defaults != nil && t.Duration == "" {
 		t.Duration = defaults.Duration
 	}
+	if t.Quantile == 0 && defaults != nil && defaults.Quantile != 0 {
+		t.Quantile = defaults.Quantile
+		if t.Factor == 0 {
+			t.Factor = defaults.Factor
+		}
+		if t.MinDuration == "" {
+			t.MinDuration = defaults.MinDuration
+		}
+		if t.MaxDuration == "" {
+			t.MaxDuration = defaults.MaxDuration
+		}
+	}
+	if t.Quantile > 0 {
+		if t.Factor == 0 {
+			t.Factor = 2
+		}
+		if t.MaxDuration == "" {
+			// By default the fixed duration acts as the upper bound of adaptive timeout
+			t.MaxDuration = t.Duration
+		}
+	}
 }
 
 func (r *RetryPolicyConfig) SetDefaults(defaults *RetryPolicyConfig) {
@@ -799,6 +1045,21 @@ func (h *HedgePolicyConfig) SetDefaults(defaults *HedgePolicyConfig) {
 			h.Delay = "100ms"
 		}
 	}
+	if h.Quantile == 0 && defaults != nil && defaults.Quantile != 0 {
+		h.Quantile = defaults.Quantile
+		if h.Factor == 0 {
+			h.Factor = defaults.Factor
+		}
+		if h.MinDelay == "" {
+			h.MinDelay = defaults.MinDelay
+		}
+		if h.MaxDelay == "" {
+			h.MaxDelay = defaults.MaxDelay
+		}
+	}
+	if h.Quantile > 0 && h.Factor == 0 {
+		h.Factor = 1
+	}
 }
 
 func (c *CircuitBreakerPolicyConfig) SetDefaults(defaults *CircuitBreakerPolicyConfig) {
@@ -809,6 +1070,16 @@ func (c *CircuitBreakerPolicyConfig) SetDefaults(defaults *CircuitBreakerPolicyC
 			c.HalfOpenAfter = "5m"
 		}
 	}
+	if c.Scope == "" {
+		if defaults != nil && defaults.Scope != "" {
+			c.Scope = defaults.Scope
+		} else {
+			c.Scope = CircuitBreakerScopeUpstream
+		}
+	}
+	if c.MethodGroups == nil && defaults != nil {
+		c.MethodGroups = defaults.MethodGroups
+	}
 }
 
 func (r *RateLimitAutoTuneConfig) SetDefaults() {
@@ -851,6 +1122,10 @@ var DefaultScoreMultiplier = &ScoreMultiplierConfig{
 	BlockHeadLag:    2.0,
 	FinalizationLag: 1.0,
 
+	// In-flight requests are only a score input when explicitly configured,
+	// as the value is a point-in-time snapshot taken at each score refresh.
+	InFlight: 0.0,
+
 	Overall: 1.0,
 }
 
@@ -978,13 +1253,81 @@ func (s *AuthStrategyConfig) SetDefaults() {
 		s.Type = AuthTypeSiwe
 		s.Siwe.SetDefaults()
 	}
+
+	if s.Type == AuthTypeDatabase && s.Database == nil {
+		s.Database = &DatabaseStrategyConfig{}
+	}
+	if s.Database != nil {
+		s.Type = AuthTypeDatabase
+		s.Database.SetDefaults()
+	}
+
+	if s.Type == AuthTypeMtls && s.Mtls == nil {
+		s.Mtls = &MtlsStrategyConfig{}
+	}
+	if s.Mtls != nil {
+		s.Type = AuthTypeMtls
+		s.Mtls.SetDefaults()
+	}
 }
 
 func (s *SecretStrategyConfig) SetDefaults() {}
 
-func (j *JwtStrategyConfig) SetDefaults() {}
+func (j *JwtStrategyConfig) SetDefaults() {
+	if j.JwksUri != "" {
+		if j.JwksRefreshInterval == "" {
+			j.JwksRefreshInterval = "1h"
+		}
+		if j.JwksMinRefreshInterval == "" {
+			j.JwksMinRefreshInterval = "30s"
+		}
+	}
+	if j.ClaimsMapping != nil {
+		j.ClaimsMapping.SetDefaults()
+	}
+}
+
+func (c *JwtClaimsMappingConfig) SetDefaults() {
+	if c.UserIdClaim == "" {
+		c.UserIdClaim = "sub"
+	}
+}
+
+func (s *SiweStrategyConfig) SetDefaults() {
+	if s.Nonce != nil {
+		s.Nonce.SetDefaults()
+		// Nonce bindings are kept until the session ends, so sessions must be bounded
+		if s.SessionLifetime == "" {
+			s.SessionLifetime = "24h"
+		}
+	}
+}
+
+func (n *SiweNonceConfig) SetDefaults() {
+	if n.Connector == nil {
+		n.Connector = &ConnectorConfig{
+			Driver: DriverMemory,
+		}
+	}
+	n.Connector.SetDefaults()
+	if n.TTL == "" {
+		n.TTL = "5m"
+	}
+}
+
+func (m *MtlsStrategyConfig) SetDefaults() {}
 
-func (s *SiweStrategyConfig) SetDefaults() {}
+func (d *DatabaseStrategyConfig) SetDefaults() {
+	if d.Connector != nil {
+		d.Connector.SetDefaults()
+	}
+	if d.CacheTTL == "" {
+		d.CacheTTL = "5s"
+	}
+	if d.CacheMaxItems == 0 {
+		d.CacheMaxItems = 10_000
+	}
+}
 
 func (n *NetworkStrategyConfig) SetDefaults() {}
 
@@ -1042,6 +1385,48 @@ func (h *HealthCheckConfig) SetDefaults() {
 	if h.ScoreMetricsWindowSize == "" {
 		h.ScoreMetricsWindowSize = "30m"
 	}
+	if h.MinHealthyUpstreams == nil {
+		h.MinHealthyUpstreams = util.IntPtr(1)
+	}
+	if h.MaxErrorRate == nil {
+		h.MaxErrorRate = util.Float64Ptr(0.99)
+	}
+	if h.SharedState != nil {
+		h.SharedState.SetDefaults()
+	}
+	if h.Persistence != nil {
+		h.Persistence.SetDefaults()
+	}
+}
+
+func (p *HealthPersistenceConfig) SetDefaults() {
+	if p.Connector != nil {
+		p.Connector.SetDefaults()
+	}
+	if p.Interval == "" {
+		p.Interval = "1m"
+	}
+	if p.MaxAge == "" {
+		p.MaxAge = "1h"
+	}
+	if p.DecayHalfLife == "" {
+		p.DecayHalfLife = "10m"
+	}
+}
+
+func (s *SharedStateConfig) SetDefaults() {
+	if s.Connector != nil {
+		s.Connector.SetDefaults()
+	}
+	if s.Channel == "" {
+		s.Channel = "erpc-shared-health"
+	}
+	if s.SyncInterval == "" {
+		s.SyncInterval = "5s"
+	}
+	if s.StaleAfter == "" {
+		s.StaleAfter = "30s"
+	}
 }
 
 func NewDefaultNetworkConfig(upstreams []*UpstreamConfig) *NetworkConfig {
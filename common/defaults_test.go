@@ -3,6 +3,7 @@ package common
 import (
 	"testing"
 
+	"github.com/erpc/erpc/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +122,17 @@ func TestSetDefaults_NetworkConfig(t *testing.T) {
 		assert.Nil(t, network.Failsafe.Retry)
 	})
 }
+
+func TestSetDefaults_HealthCheckConfig(t *testing.T) {
+	t.Run("DefaultsMaxErrorRateWhenUnset", func(t *testing.T) {
+		hc := &HealthCheckConfig{}
+		hc.SetDefaults()
+		assert.Equal(t, 0.99, *hc.MaxErrorRate)
+	})
+
+	t.Run("KeepsExplicitZeroMaxErrorRate", func(t *testing.T) {
+		hc := &HealthCheckConfig{MaxErrorRate: util.Float64Ptr(0)}
+		hc.SetDefaults()
+		assert.Equal(t, 0.0, *hc.MaxErrorRate)
+	})
+}
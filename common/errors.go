@@ -732,6 +732,7 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 		timeout := 0
 		serverError := 0
 		rateLimit := 0
+		saturated := 0
 		cbOpen := 0
 		billing := 0
 		skips := 0
@@ -740,8 +741,10 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 		transport := 0
 		cancelled := 0
 		unsynced := 0
+		lagging := 0
 		excluded := 0
 		nodeTypeMismatch := 0
+		cordoned := 0
 
 		for _, e := range joinedErr.Unwrap() {
 			if HasErrorCode(e, ErrCodeEndpointUnsupported) {
@@ -754,6 +757,9 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 				HasErrorCode(e, ErrCodeUpstreamRateLimitRuleExceeded) {
 				rateLimit++
 				continue
+			} else if HasErrorCode(e, ErrCodeUpstreamMaxConcurrencyReached) {
+				saturated++
+				continue
 			} else if HasErrorCode(e, ErrCodeEndpointBillingIssue) {
 				billing++
 				continue
@@ -778,12 +784,19 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 			} else if HasErrorCode(e, ErrCodeUpstreamSyncing) {
 				unsynced++
 				continue
+			} else if HasErrorCode(e, ErrCodeUpstreamLagging) {
+				lagging++
+				continue
 			} else if HasErrorCode(e, ErrCodeUpstreamExcludedByPolicy) {
 				excluded++
 				continue
 			} else if HasErrorCode(e, ErrCodeUpstreamNodeTypeMismatch) {
 				nodeTypeMismatch++
 				continue
+			} else if HasErrorCode(e, ErrCodeUpstreamCordoned) {
+				// Checked before skips as cordoned upstreams are reported wrapped in ErrUpstreamRequestSkipped
+				cordoned++
+				continue
 			} else if HasErrorCode(e, ErrCodeUpstreamMethodIgnored, ErrCodeUpstreamRequestSkipped) {
 				skips++
 				continue
@@ -808,6 +821,9 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 		if rateLimit > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d upstream rate limited", rateLimit))
 		}
+		if saturated > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d upstream at max concurrency", saturated))
+		}
 		if cbOpen > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d upstream circuit breaker open", cbOpen))
 		}
@@ -823,12 +839,18 @@ func (e *ErrUpstreamsExhausted) SummarizeCauses() string {
 		if unsynced > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d syncing nodes", unsynced))
 		}
+		if lagging > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d lagging nodes", lagging))
+		}
 		if excluded > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d upstream excluded by policy", excluded))
 		}
 		if nodeTypeMismatch > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d node type mismatches", nodeTypeMismatch))
 		}
+		if cordoned > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d upstream cordoned", cordoned))
+		}
 		if skips > 0 {
 			reasons = append(reasons, fmt.Sprintf("%d upstream skipped", skips))
 		}
@@ -1035,6 +1057,42 @@ var NewErrUpstreamSyncing = func(upstreamId string) error {
 	}
 }
 
+type ErrUpstreamLagging struct{ BaseError }
+
+const ErrCodeUpstreamLagging ErrorCode = "ErrUpstreamLagging"
+
+var NewErrUpstreamLagging = func(upstreamId string, lagType string, lag int64, maxLag int64) error {
+	return &ErrUpstreamLagging{
+		BaseError{
+			Code:    ErrCodeUpstreamLagging,
+			Message: fmt.Sprintf("upstream %s (%d blocks) exceeds max allowed (%d blocks)", lagType, lag, maxLag),
+			Details: map[string]interface{}{
+				"upstreamId": upstreamId,
+				"lagType":    lagType,
+				"lag":        lag,
+				"maxLag":     maxLag,
+			},
+		},
+	}
+}
+
+type ErrUpstreamCordoned struct{ BaseError }
+
+const ErrCodeUpstreamCordoned ErrorCode = "ErrUpstreamCordoned"
+
+var NewErrUpstreamCordoned = func(upstreamId string, method string) error {
+	return &ErrUpstreamCordoned{
+		BaseError{
+			Code:    ErrCodeUpstreamCordoned,
+			Message: "upstream is temporarily cordoned",
+			Details: map[string]interface{}{
+				"upstreamId": upstreamId,
+				"method":     method,
+			},
+		},
+	}
+}
+
 type ErrUpstreamNotAllowed struct{ BaseError }
 
 var NewErrUpstreamNotAllowed = func(upstreamId string) error {
@@ -1417,6 +1475,27 @@ func (e *ErrUpstreamRateLimitRuleExceeded) ErrorStatusCode() int {
 	return http.StatusTooManyRequests
 }
 
+type ErrUpstreamMaxConcurrencyReached struct{ BaseError }
+
+const ErrCodeUpstreamMaxConcurrencyReached ErrorCode = "ErrUpstreamMaxConcurrencyReached"
+
+var NewErrUpstreamMaxConcurrencyReached = func(upstreamId string, maxConcurrency int) error {
+	return &ErrUpstreamMaxConcurrencyReached{
+		BaseError{
+			Code:    ErrCodeUpstreamMaxConcurrencyReached,
+			Message: "upstream reached max concurrent in-flight requests",
+			Details: map[string]interface{}{
+				"upstreamId":     upstreamId,
+				"maxConcurrency": maxConcurrency,
+			},
+		},
+	}
+}
+
+func (e *ErrUpstreamMaxConcurrencyReached) ErrorStatusCode() int {
+	return http.StatusTooManyRequests
+}
+
 type ErrUpstreamExcludedByPolicy struct{ BaseError }
 
 const ErrCodeUpstreamExcludedByPolicy ErrorCode = "ErrUpstreamExcludedByPolicy"
@@ -1572,6 +1651,25 @@ func (e *ErrEndpointCapacityExceeded) ErrorStatusCode() int {
 	return 429
 }
 
+// RateLimitHints returns the reset time, the limit and its window reported by the remote endpoint
+// (via Retry-After or X-RateLimit-* headers) if they were captured when the error was normalized.
+func (e *ErrEndpointCapacityExceeded) RateLimitHints() (resetAt time.Time, limit int, window time.Duration) {
+	var jre *ErrJsonRpcExceptionInternal
+	if !errors.As(e.Cause, &jre) || jre.Details == nil {
+		return
+	}
+	if v, ok := jre.Details["rateLimitResetAt"].(time.Time); ok {
+		resetAt = v
+	}
+	if v, ok := jre.Details["rateLimitLimit"].(int); ok {
+		limit = v
+	}
+	if v, ok := jre.Details["rateLimitWindow"].(time.Duration); ok {
+		window = v
+	}
+	return
+}
+
 type ErrEndpointBillingIssue struct{ BaseError }
 
 const ErrCodeEndpointBillingIssue = "ErrEndpointBillingIssue"
@@ -1887,6 +1985,7 @@ func IsCapacityIssue(err error) bool {
 		ErrCodeProjectRateLimitRuleExceeded,
 		ErrCodeNetworkRateLimitRuleExceeded,
 		ErrCodeUpstreamRateLimitRuleExceeded,
+		ErrCodeUpstreamMaxConcurrencyReached,
 		ErrCodeAuthRateLimitRuleExceeded,
 		ErrCodeEndpointCapacityExceeded,
 	)
@@ -0,0 +1,20 @@
+package common
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrUpstreamsExhausted_SummarizeCauses(t *testing.T) {
+	t.Run("CountsCordonedUpstreamsWrappedInSkips", func(t *testing.T) {
+		ers := &sync.Map{}
+		ers.Store("rpc1", NewErrUpstreamRequestSkipped(NewErrUpstreamCordoned("rpc1", "eth_call"), "rpc1"))
+		ers.Store("rpc2", NewErrUpstreamRequestSkipped(NewErrUpstreamMethodIgnored("eth_call", "rpc2"), "rpc2"))
+
+		err := NewErrUpstreamsExhausted(nil, ers, "prjA", "evm:123", "eth_call", time.Second, 0, 0, 0).(*ErrUpstreamsExhausted)
+		assert.Equal(t, "1 upstream cordoned, 1 upstream skipped", err.SummarizeCauses())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,6 +29,20 @@ type RequestDirectives struct {
 	// you can set this value to "false" via Headers.
 	RetryPending bool `json:"retryPending"`
 
+	// Instruct the proxy to confirm an empty response (for eth_getLogs, eth_getTransactionReceipt and eth_getBlockByNumber)
+	// against another upstream that is synced at least up to the requested block, before returning it.
+	// Only confirmed empty responses are cached, and if the other upstream has the data its response is returned instead.
+	VerifyEmpty bool `json:"verifyEmpty"`
+
+	// Instruct the proxy to capture the request, the raw response (or error) of each upstream attempt
+	// and the final response, so that it can be inspected or replayed later.
+	// This only takes effect when capture is configured for the project.
+	Capture bool `json:"capture"`
+
+	// Instruct the proxy to add a "debug" field to each item of a batch response, with the upstream, cache status,
+	// attempts, retries, hedges and duration (single responses carry this information in X-ERPC-* headers).
+	Debug bool `json:"debug"`
+
 	// Instruct the proxy to skip cache reads for example to force freshness,
 	// or override some cache corruption.
 	SkipCacheRead bool `json:"skipCacheRead"`
@@ -41,6 +56,12 @@ type RequestDirectives struct {
 	ByPassMethodExclusion bool `json:"byPassMethodExclusion"`
 }
 
+// ClientInfo describes the client that sent the request, used for routing decisions such as session affinity.
+type ClientInfo struct {
+	IP      string
+	Headers http.Header
+}
+
 type NormalizedRequest struct {
 	sync.RWMutex
 
@@ -54,6 +75,10 @@ type NormalizedRequest struct {
 
 	lastValidResponse atomic.Pointer[NormalizedResponse]
 	lastUpstream      atomic.Value
+	upstreamsTried    []string
+	upstreamsTriedMu  sync.Mutex
+	user              atomic.Pointer[User]
+	client            atomic.Pointer[ClientInfo]
 	evmBlockRef       atomic.Value
 	evmBlockNumber    atomic.Value
 }
@@ -72,9 +97,27 @@ func (r *NormalizedRequest) SetLastUpstream(upstream Upstream) *NormalizedReques
 		return r
 	}
 	r.lastUpstream.Store(upstream)
+	if upstream != nil {
+		id := upstream.Config().Id
+		r.upstreamsTriedMu.Lock()
+		if !slices.Contains(r.upstreamsTried, id) {
+			r.upstreamsTried = append(r.upstreamsTried, id)
+		}
+		r.upstreamsTriedMu.Unlock()
+	}
 	return r
 }
 
+// UpstreamsTried returns ids of all upstreams this request was forwarded to, in order of first attempt.
+func (r *NormalizedRequest) UpstreamsTried() []string {
+	if r == nil {
+		return nil
+	}
+	r.upstreamsTriedMu.Lock()
+	defer r.upstreamsTriedMu.Unlock()
+	return slices.Clone(r.upstreamsTried)
+}
+
 func (r *NormalizedRequest) LastUpstream() Upstream {
 	if r == nil {
 		return nil
@@ -99,6 +142,36 @@ func (r *NormalizedRequest) LastValidResponse() *NormalizedResponse {
 	return r.lastValidResponse.Load()
 }
 
+func (r *NormalizedRequest) SetUser(user *User) {
+	if r == nil {
+		return
+	}
+	r.user.Store(user)
+}
+
+func (r *NormalizedRequest) SetClientInfo(info *ClientInfo) {
+	if r == nil {
+		return
+	}
+	r.client.Store(info)
+}
+
+// ClientInfo returns information about the client that sent the request (nil if not received over http).
+func (r *NormalizedRequest) ClientInfo() *ClientInfo {
+	if r == nil {
+		return nil
+	}
+	return r.client.Load()
+}
+
+// User returns the consumer identity resolved during authentication (nil if auth is not enabled).
+func (r *NormalizedRequest) User() *User {
+	if r == nil {
+		return nil
+	}
+	return r.user.Load()
+}
+
 func (r *NormalizedRequest) Network() Network {
 	if r == nil {
 		return nil
@@ -151,6 +224,9 @@ func (r *NormalizedRequest) ApplyDirectiveDefaults(directiveDefaults *DirectiveD
 	if directiveDefaults.RetryPending != nil {
 		r.directives.RetryPending = *directiveDefaults.RetryPending
 	}
+	if directiveDefaults.VerifyEmpty != nil {
+		r.directives.VerifyEmpty = *directiveDefaults.VerifyEmpty
+	}
 	if directiveDefaults.SkipCacheRead != nil {
 		r.directives.SkipCacheRead = *directiveDefaults.SkipCacheRead
 	}
@@ -169,7 +245,10 @@ func (r *NormalizedRequest) ApplyDirectivesFromHttp(headers http.Header, queryAr
 
 	r.directives.RetryEmpty = headers.Get("X-ERPC-Retry-Empty") != "false"
 	r.directives.RetryPending = headers.Get("X-ERPC-Retry-Pending") == "true"
+	r.directives.VerifyEmpty = headers.Get("X-ERPC-Verify-Empty") == "true"
 	r.directives.SkipCacheRead = headers.Get("X-ERPC-Skip-Cache-Read") == "true"
+	r.directives.Capture = headers.Get("X-ERPC-Capture") == "true"
+	r.directives.Debug = headers.Get("X-ERPC-Debug") == "true"
 	r.directives.UseUpstream = headers.Get("X-ERPC-Use-Upstream")
 
 	if useUpstream := queryArgs.Get("use-upstream"); useUpstream != "" {
@@ -184,9 +263,21 @@ func (r *NormalizedRequest) ApplyDirectivesFromHttp(headers http.Header, queryAr
 		r.directives.RetryPending = strings.ToLower(strings.TrimSpace(retryPending)) == "true"
 	}
 
+	if verifyEmpty := queryArgs.Get("verify-empty"); verifyEmpty != "" {
+		r.directives.VerifyEmpty = strings.ToLower(strings.TrimSpace(verifyEmpty)) == "true"
+	}
+
 	if skipCacheRead := queryArgs.Get("skip-cache-read"); skipCacheRead != "" {
 		r.directives.SkipCacheRead = strings.ToLower(strings.TrimSpace(skipCacheRead)) != "false"
 	}
+
+	if capture := queryArgs.Get("capture"); capture != "" {
+		r.directives.Capture = strings.ToLower(strings.TrimSpace(capture)) == "true"
+	}
+
+	if debug := queryArgs.Get("debug"); debug != "" {
+		r.directives.Debug = strings.ToLower(strings.TrimSpace(debug)) == "true"
+	}
 }
 
 func (r *NormalizedRequest) SkipCacheRead() bool {
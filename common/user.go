@@ -0,0 +1,36 @@
+package common
+
+// User is the identity of a consumer resolved by an auth strategy. Besides the identifier
+// it can carry per-consumer restrictions (e.g. stored alongside an API key in the database).
+type User struct {
+	Id              string   `json:"id"`
+	RateLimitBudget string   `json:"rateLimitBudget,omitempty"`
+	AllowedNetworks []string `json:"allowedNetworks,omitempty"`
+	AllowedMethods  []string `json:"allowedMethods,omitempty"`
+}
+
+// IsNetworkAllowed returns true if the user has no network restrictions or the network matches one of the allowed patterns.
+func (u *User) IsNetworkAllowed(networkId string) bool {
+	if u == nil || len(u.AllowedNetworks) == 0 {
+		return true
+	}
+	for _, pattern := range u.AllowedNetworks {
+		if match, err := WildcardMatch(pattern, networkId); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMethodAllowed returns true if the user has no method restrictions or the method matches one of the allowed patterns.
+func (u *User) IsMethodAllowed(method string) bool {
+	if u == nil || len(u.AllowedMethods) == 0 {
+		return true
+	}
+	for _, pattern := range u.AllowedMethods {
+		if match, err := WildcardMatch(pattern, method); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -20,8 +21,13 @@ func (c *Config) Validate() error {
 			return err
 		}
 	}
+	if c.Tracing != nil {
+		if err := c.Tracing.Validate(); err != nil {
+			return err
+		}
+	}
 	if c.Admin != nil {
-		if err := c.Admin.Validate(); err != nil {
+		if err := c.Admin.Validate(c); err != nil {
 			return err
 		}
 	}
@@ -73,12 +79,47 @@ func (s *ServerConfig) Validate() error {
 	if err != nil {
 		return fmt.Errorf("server.maxTimeout is invalid (must be like 500ms, 2s, etc): %w", err)
 	}
+	if s.AccessLog != nil {
+		if err := s.AccessLog.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (a *AdminConfig) Validate() error {
+func (a *AccessLogConfig) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+	switch a.Output {
+	case "stdout":
+	case "file":
+		if a.FilePath == "" {
+			return fmt.Errorf("server.accessLog.filePath is required when server.accessLog.output is file")
+		}
+	default:
+		return fmt.Errorf("server.accessLog.output must be either stdout or file, got: %s", a.Output)
+	}
+	if a.MaxSizeMB < 0 {
+		return fmt.Errorf("server.accessLog.maxSizeMB must not be negative")
+	}
+	if a.MaxBackups < 0 {
+		return fmt.Errorf("server.accessLog.maxBackups must not be negative")
+	}
+	if a.SampleRate != nil && (*a.SampleRate < 0 || *a.SampleRate > 1) {
+		return fmt.Errorf("server.accessLog.sampleRate must be between 0 and 1")
+	}
+	for _, rule := range a.Sampling {
+		if rule.Rate < 0 || rule.Rate > 1 {
+			return fmt.Errorf("server.accessLog.sampling.*.rate must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+func (a *AdminConfig) Validate(c *Config) error {
 	if a.Auth != nil {
-		if err := a.Auth.Validate(); err != nil {
+		if err := a.Auth.Validate(c); err != nil {
 			return err
 		}
 	}
@@ -102,6 +143,25 @@ func (m *MetricsConfig) Validate() error {
 	return nil
 }
 
+func (t *TracingConfig) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.Endpoint == "" {
+		return fmt.Errorf("tracing.endpoint is required when tracing.enabled is true")
+	}
+	if t.SampleRate != nil && (*t.SampleRate < 0 || *t.SampleRate > 1) {
+		return fmt.Errorf("tracing.sampleRate must be between 0 and 1")
+	}
+	if t.BatchSize <= 0 {
+		return fmt.Errorf("tracing.batchSize must be greater than 0")
+	}
+	if _, err := time.ParseDuration(t.FlushInterval); err != nil {
+		return fmt.Errorf("tracing.flushInterval is invalid: %v", err)
+	}
+	return nil
+}
+
 func (r *RateLimiterConfig) Validate() error {
 	if len(r.Budgets) > 0 {
 		for _, budget := range r.Budgets {
@@ -361,7 +421,7 @@ func (p *ProjectConfig) Validate(c *Config) error {
 		}
 	}
 	if p.Auth != nil {
-		if err := p.Auth.Validate(); err != nil {
+		if err := p.Auth.Validate(c); err != nil {
 			return err
 		}
 	}
@@ -375,6 +435,16 @@ func (p *ProjectConfig) Validate(c *Config) error {
 			return err
 		}
 	}
+	if p.Capture != nil {
+		if err := p.Capture.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Metering != nil {
+		if err := p.Metering.Validate(); err != nil {
+			return err
+		}
+	}
 	if p.RateLimitBudget != "" {
 		if !c.HasRateLimiterBudget(p.RateLimitBudget) {
 			return fmt.Errorf("project.*.rateLimitBudget '%s' does not exist in config.rateLimiters", p.RateLimitBudget)
@@ -383,19 +453,19 @@ func (p *ProjectConfig) Validate(c *Config) error {
 	return nil
 }
 
-func (a *AuthConfig) Validate() error {
+func (a *AuthConfig) Validate(c *Config) error {
 	if a.Strategies == nil || len(a.Strategies) == 0 {
 		return fmt.Errorf("project.*.auth.strategies is required, add at least one strategy")
 	}
 	for _, strategy := range a.Strategies {
-		if err := strategy.Validate(); err != nil {
+		if err := strategy.Validate(c); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *AuthStrategyConfig) Validate() error {
+func (s *AuthStrategyConfig) Validate(c *Config) error {
 	if s.Type == "" {
 		return fmt.Errorf("auth.*.type is required")
 	}
@@ -418,7 +488,7 @@ func (s *AuthStrategyConfig) Validate() error {
 		if s.Jwt == nil {
 			return fmt.Errorf("auth.*.jwt is required for jwt strategy")
 		}
-		if err := s.Jwt.Validate(); err != nil {
+		if err := s.Jwt.Validate(c); err != nil {
 			return err
 		}
 	case AuthTypeSiwe:
@@ -428,12 +498,28 @@ func (s *AuthStrategyConfig) Validate() error {
 		if err := s.Siwe.Validate(); err != nil {
 			return err
 		}
+	case AuthTypeDatabase:
+		if s.Database == nil {
+			return fmt.Errorf("auth.*.database is required for database strategy")
+		}
+		if err := s.Database.Validate(); err != nil {
+			return err
+		}
+	case AuthTypeMtls:
+		if s.Mtls == nil {
+			return fmt.Errorf("auth.*.mtls is required for mtls strategy")
+		}
+		if err := s.Mtls.Validate(); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("auth.*.type '%s' is invalid must be one of: %v", s.Type, []AuthType{
 			AuthTypeNetwork,
 			AuthTypeSecret,
 			AuthTypeJwt,
 			AuthTypeSiwe,
+			AuthTypeDatabase,
+			AuthTypeMtls,
 		})
 	}
 	return nil
@@ -450,14 +536,102 @@ func (s *SecretStrategyConfig) Validate() error {
 	return nil
 }
 
-func (j *JwtStrategyConfig) Validate() error {
-	if j.VerificationKeys == nil || len(j.VerificationKeys) == 0 {
-		return fmt.Errorf("auth.*.jwt.verificationKeys is required, add at least one verification key")
+func (j *JwtStrategyConfig) Validate(c *Config) error {
+	if len(j.VerificationKeys) == 0 && j.JwksUri == "" {
+		return fmt.Errorf("auth.*.jwt.verificationKeys or auth.*.jwt.jwksUri is required, add at least one verification key or a JWKS endpoint")
+	}
+	if j.JwksUri != "" {
+		refreshInterval, err := time.ParseDuration(j.JwksRefreshInterval)
+		if err != nil {
+			return fmt.Errorf("auth.*.jwt.jwksRefreshInterval is invalid: %w", err)
+		}
+		if refreshInterval <= 0 {
+			return fmt.Errorf("auth.*.jwt.jwksRefreshInterval must be greater than 0")
+		}
+		minRefreshInterval, err := time.ParseDuration(j.JwksMinRefreshInterval)
+		if err != nil {
+			return fmt.Errorf("auth.*.jwt.jwksMinRefreshInterval is invalid: %w", err)
+		}
+		// Failed fetches are retried after minRefreshInterval, so 0 would hammer the JWKS endpoint
+		if minRefreshInterval <= 0 {
+			return fmt.Errorf("auth.*.jwt.jwksMinRefreshInterval must be greater than 0")
+		}
+	}
+	if j.ClaimsMapping != nil {
+		if err := j.ClaimsMapping.Validate(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *JwtClaimsMappingConfig) Validate(c *Config) error {
+	if len(m.TierBudgets) > 0 && m.TierClaim == "" {
+		return fmt.Errorf("auth.*.jwt.claimsMapping.tierClaim is required when tierBudgets is defined")
+	}
+	if m.TierClaim != "" && len(m.TierBudgets) == 0 {
+		return fmt.Errorf("auth.*.jwt.claimsMapping.tierBudgets is required when tierClaim is defined")
+	}
+	for tier, budget := range m.TierBudgets {
+		if !c.HasRateLimiterBudget(budget) {
+			return fmt.Errorf("auth.*.jwt.claimsMapping.tierBudgets.%s budget '%s' does not exist in config.rateLimiters", tier, budget)
+		}
 	}
 	return nil
 }
 
 func (s *SiweStrategyConfig) Validate() error {
+	if s.SessionLifetime != "" {
+		if _, err := time.ParseDuration(s.SessionLifetime); err != nil {
+			return fmt.Errorf("auth.*.siwe.sessionLifetime is invalid: %w", err)
+		}
+	}
+	if s.Nonce != nil {
+		if s.SessionLifetime == "" {
+			return fmt.Errorf("auth.*.siwe.sessionLifetime is required when nonce is configured")
+		}
+		if err := s.Nonce.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *SiweNonceConfig) Validate() error {
+	if n.Connector == nil {
+		return fmt.Errorf("auth.*.siwe.nonce.connector is required")
+	}
+	if err := n.Connector.Validate(); err != nil {
+		return err
+	}
+	if _, err := time.ParseDuration(n.TTL); err != nil {
+		return fmt.Errorf("auth.*.siwe.nonce.ttl is invalid: %w", err)
+	}
+	return nil
+}
+
+func (m *MtlsStrategyConfig) Validate() error {
+	if len(m.AllowedSubjects) == 0 && len(m.AllowedFingerprints) == 0 {
+		return fmt.Errorf("auth.*.mtls.allowedSubjects or auth.*.mtls.allowedFingerprints is required, use \"*\" subject to allow any certificate verified by server.tls.caFile")
+	}
+	return nil
+}
+
+func (d *DatabaseStrategyConfig) Validate() error {
+	if d.Connector == nil {
+		return fmt.Errorf("auth.*.database.connector is required")
+	}
+	if err := d.Connector.Validate(); err != nil {
+		return err
+	}
+	if d.CacheTTL != "" {
+		if _, err := time.ParseDuration(d.CacheTTL); err != nil {
+			return fmt.Errorf("auth.*.database.cacheTTL is invalid: %w", err)
+		}
+	}
+	if d.CacheMaxItems < 0 {
+		return fmt.Errorf("auth.*.database.cacheMaxItems must be greater than or equal to 0")
+	}
 	return nil
 }
 
@@ -472,6 +646,133 @@ func (h *HealthCheckConfig) Validate() error {
 	if h.ScoreMetricsWindowSize == "" {
 		return fmt.Errorf("project.*.healthCheck.scoreMetricsWindowSize is required")
 	}
+	if h.MinHealthyUpstreams != nil && *h.MinHealthyUpstreams < 0 {
+		return fmt.Errorf("project.*.healthCheck.minHealthyUpstreams must be greater than or equal to 0")
+	}
+	if h.MaxErrorRate != nil && (*h.MaxErrorRate < 0 || *h.MaxErrorRate > 1) {
+		return fmt.Errorf("project.*.healthCheck.maxErrorRate must be between 0 and 1")
+	}
+	if h.SharedState != nil {
+		if err := h.SharedState.Validate(); err != nil {
+			return err
+		}
+	}
+	if h.Persistence != nil {
+		if err := h.Persistence.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *HealthPersistenceConfig) Validate() error {
+	if (p.File == "") == (p.Connector == nil) {
+		return fmt.Errorf("project.*.healthCheck.persistence must have exactly one of file or connector")
+	}
+	if p.Connector != nil {
+		if err := p.Connector.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, f := range []struct{ name, value string }{
+		{"interval", p.Interval},
+		{"maxAge", p.MaxAge},
+		{"decayHalfLife", p.DecayHalfLife},
+	} {
+		name := f.name
+		d, err := time.ParseDuration(f.value)
+		if err != nil {
+			return fmt.Errorf("project.*.healthCheck.persistence.%s is invalid (must be like 1m, 1h, etc): %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("project.*.healthCheck.persistence.%s must be greater than 0", name)
+		}
+	}
+	return nil
+}
+
+func (c *CaptureConfig) Validate() error {
+	if (c.File == "") == (c.Connector == nil) {
+		return fmt.Errorf("project.*.capture must have exactly one of file or connector")
+	}
+	if c.Connector != nil {
+		if err := c.Connector.Validate(); err != nil {
+			return err
+		}
+	}
+	if d, err := time.ParseDuration(c.TTL); err != nil || d <= 0 {
+		return fmt.Errorf("project.*.capture.ttl is invalid (must be like 24h, 168h, etc): %v", c.TTL)
+	}
+	for _, rule := range c.Sampling {
+		if rule.Rate < 0 || rule.Rate > 1 {
+			return fmt.Errorf("project.*.capture.sampling.*.rate must be between 0 and 1")
+		}
+	}
+	return nil
+}
+
+func (m *MeteringConfig) Validate() error {
+	if (m.File == "") == (m.Connector == nil) {
+		return fmt.Errorf("project.*.metering must have exactly one of file or connector")
+	}
+	if m.Connector != nil {
+		if m.Connector.Driver != DriverPostgreSQL {
+			return fmt.Errorf("project.*.metering.connector.driver must be postgresql, as usage is written to a table")
+		}
+		if err := m.Connector.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, f := range []struct{ name, value string }{
+		{"ttl", m.TTL},
+		{"flushInterval", m.FlushInterval},
+	} {
+		d, err := time.ParseDuration(f.value)
+		if err != nil {
+			return fmt.Errorf("project.*.metering.%s is invalid (must be like 1m, 1h, etc): %w", f.name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("project.*.metering.%s must be greater than 0", f.name)
+		}
+	}
+	if m.DefaultComputeUnits < 0 {
+		return fmt.Errorf("project.*.metering.defaultComputeUnits must not be negative")
+	}
+	for _, cu := range m.ComputeUnits {
+		if cu.Method == "" {
+			return fmt.Errorf("project.*.metering.computeUnits.*.method is required")
+		}
+		if cu.Units < 0 {
+			return fmt.Errorf("project.*.metering.computeUnits.*.units must not be negative")
+		}
+	}
+	return nil
+}
+
+func (s *SharedStateConfig) Validate() error {
+	if s.Connector == nil {
+		return fmt.Errorf("project.*.healthCheck.sharedState.connector is required")
+	}
+	if s.Connector.Driver != DriverRedis {
+		return fmt.Errorf("project.*.healthCheck.sharedState.connector.driver must be redis, but '%s' is provided", s.Connector.Driver)
+	}
+	if err := s.Connector.Validate(); err != nil {
+		return err
+	}
+	syncInterval, err := time.ParseDuration(s.SyncInterval)
+	if err != nil {
+		return fmt.Errorf("project.*.healthCheck.sharedState.syncInterval is invalid (must be like 5s, 1m, etc): %w", err)
+	}
+	if syncInterval <= 0 {
+		return fmt.Errorf("project.*.healthCheck.sharedState.syncInterval must be greater than 0")
+	}
+	staleAfter, err := time.ParseDuration(s.StaleAfter)
+	if err != nil {
+		return fmt.Errorf("project.*.healthCheck.sharedState.staleAfter is invalid (must be like 30s, 1m, etc): %w", err)
+	}
+	if staleAfter <= syncInterval {
+		return fmt.Errorf("project.*.healthCheck.sharedState.staleAfter must be greater than syncInterval")
+	}
 	return nil
 }
 
@@ -516,6 +817,50 @@ func (u *UpstreamConfig) Validate(c *Config) error {
 			return fmt.Errorf("upstream.*.rateLimitBudget '%s' does not exist in config.rateLimiters", u.RateLimitBudget)
 		}
 	}
+	if u.MaxConcurrency < 0 {
+		return fmt.Errorf("upstream.*.maxConcurrency must be greater than or equal to 0")
+	}
+	if u.MaxConcurrencyQueueTimeout != "" {
+		if u.MaxConcurrency == 0 {
+			return fmt.Errorf("upstream.*.maxConcurrencyQueueTimeout requires upstream.*.maxConcurrency to be set")
+		}
+		if _, err := time.ParseDuration(u.MaxConcurrencyQueueTimeout); err != nil {
+			return fmt.Errorf("upstream.*.maxConcurrencyQueueTimeout is invalid (must be like 100ms, 1s, etc): %w", err)
+		}
+	}
+	for _, probe := range u.HealthProbes {
+		if err := probe.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *HealthProbeConfig) Validate() error {
+	if p.Method == "" {
+		return fmt.Errorf("upstream.*.healthProbes.*.method is required")
+	}
+	interval, err := time.ParseDuration(p.Interval)
+	if err != nil {
+		return fmt.Errorf("upstream.*.healthProbes.*.interval is invalid (must be like 10s, 1m, etc): %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("upstream.*.healthProbes.*.interval must be greater than 0")
+	}
+	if _, err := time.ParseDuration(p.Timeout); err != nil {
+		return fmt.Errorf("upstream.*.healthProbes.*.timeout is invalid (must be like 1s, 5s, etc): %w", err)
+	}
+	if p.FailureThreshold < 1 {
+		return fmt.Errorf("upstream.*.healthProbes.*.failureThreshold must be greater than 0")
+	}
+	if p.SuccessThreshold < 1 {
+		return fmt.Errorf("upstream.*.healthProbes.*.successThreshold must be greater than 0")
+	}
+	if p.Expect != nil && p.Expect.Pattern != "" {
+		if _, err := regexp.Compile(p.Expect.Pattern); err != nil {
+			return fmt.Errorf("upstream.*.healthProbes.*.expect.pattern is invalid: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -542,6 +887,12 @@ func (e *EvmUpstreamConfig) Validate(u *UpstreamConfig) error {
 			return fmt.Errorf("upstream.*.evm.nodeType '%s' is invalid must be one of: %v", e.NodeType, allowed)
 		}
 	}
+	if e.MaxBlockHeadLag < 0 {
+		return fmt.Errorf("upstream.*.evm.maxBlockHeadLag must be greater than or equal to 0")
+	}
+	if e.MaxFinalizationLag < 0 {
+		return fmt.Errorf("upstream.*.evm.maxFinalizationLag must be greater than or equal to 0")
+	}
 	return nil
 }
 
@@ -566,6 +917,61 @@ func (f *FailsafeConfig) Validate() error {
 			return err
 		}
 	}
+	for _, o := range f.MethodOverrides {
+		if err := o.Validate(); err != nil {
+			return err
+		}
+	}
+	if f.RetryBudget != nil {
+		if err := f.RetryBudget.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RetryBudgetConfig) Validate() error {
+	if r.Ratio != nil && *r.Ratio < 0 {
+		return fmt.Errorf("failsafe.retryBudget.ratio must be greater than or equal to 0")
+	}
+	if r.MinRetries != nil && *r.MinRetries < 0 {
+		return fmt.Errorf("failsafe.retryBudget.minRetries must be greater than or equal to 0")
+	}
+	window, err := time.ParseDuration(r.Window)
+	if err != nil {
+		return fmt.Errorf("failsafe.retryBudget.window is invalid: %v", err)
+	}
+	if window <= 0 {
+		return fmt.Errorf("failsafe.retryBudget.window must be greater than 0")
+	}
+	return nil
+}
+
+func (o *FailsafeMethodOverrideConfig) Validate() error {
+	if o.Method == "" {
+		return fmt.Errorf("upstream.*.failsafe.methodOverrides.*.method is required")
+	}
+	if _, err := WildcardMatch(o.Method, "eth_call"); err != nil {
+		return fmt.Errorf("upstream.*.failsafe.methodOverrides.*.method is invalid: %w", err)
+	}
+	if o.Retry == nil && o.Timeout == nil && o.Hedge == nil {
+		return fmt.Errorf("upstream.*.failsafe.methodOverrides.*: at least one of retry, timeout or hedge is required for method '%s'", o.Method)
+	}
+	if o.Timeout != nil {
+		if err := o.Timeout.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.Retry != nil {
+		if err := o.Retry.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.Hedge != nil {
+		if err := o.Hedge.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -577,6 +983,39 @@ func (t *TimeoutPolicyConfig) Validate() error {
 	if err != nil {
 		return fmt.Errorf("upstream.*.failsafe.timeout.duration is invalid (must be like 500ms, 2s, etc): %w", err)
 	}
+	if t.Quantile > 0 && t.MaxDuration == "" {
+		// Timeout policy is sized from maxDuration, without it adaptive timeouts would be cut off
+		return fmt.Errorf("upstream.*.failsafe.timeout.maxDuration is required when quantile is set")
+	}
+	return validateAdaptiveDuration("upstream.*.failsafe.timeout", "Duration", t.Quantile, t.Factor, t.MinDuration, t.MaxDuration)
+}
+
+// validateAdaptiveDuration validates quantile-based durations shared by timeout and hedge policies.
+func validateAdaptiveDuration(path, boundSuffix string, quantile, factor float64, minValue, maxValue string) error {
+	if quantile == 0 {
+		return nil
+	}
+	if quantile < 0 || quantile > 1 {
+		return fmt.Errorf("%s.quantile must be between 0 and 1", path)
+	}
+	if factor <= 0 {
+		return fmt.Errorf("%s.factor must be greater than 0", path)
+	}
+	var minDur, maxDur time.Duration
+	var err error
+	if minValue != "" {
+		if minDur, err = time.ParseDuration(minValue); err != nil {
+			return fmt.Errorf("%s.min%s is invalid (must be like 500ms, 2s, etc): %w", path, boundSuffix, err)
+		}
+	}
+	if maxValue != "" {
+		if maxDur, err = time.ParseDuration(maxValue); err != nil {
+			return fmt.Errorf("%s.max%s is invalid (must be like 500ms, 2s, etc): %w", path, boundSuffix, err)
+		}
+		if maxDur < minDur {
+			return fmt.Errorf("%s.max%s must be greater than or equal to min%s", path, boundSuffix, boundSuffix)
+		}
+	}
 	return nil
 }
 
@@ -616,7 +1055,7 @@ func (h *HedgePolicyConfig) Validate() error {
 	if err != nil {
 		return fmt.Errorf("upstream.*.failsafe.hedge.delay is invalid (must be like 500ms, 2s, etc): %w", err)
 	}
-	return nil
+	return validateAdaptiveDuration("upstream.*.failsafe.hedge", "Delay", h.Quantile, h.Factor, h.MinDelay, h.MaxDelay)
 }
 
 func (c *CircuitBreakerPolicyConfig) Validate() error {
@@ -645,6 +1084,28 @@ func (c *CircuitBreakerPolicyConfig) Validate() error {
 	if c.SuccessThresholdCount > c.SuccessThresholdCapacity {
 		return fmt.Errorf("upstream.*.failsafe.circuitBreaker.successThresholdCount must be less than or equal to failureThresholdCapacity")
 	}
+	allowedScopes := []CircuitBreakerScope{
+		CircuitBreakerScopeUpstream,
+		CircuitBreakerScopeMethod,
+		CircuitBreakerScopeMethodGroup,
+	}
+	if c.Scope != "" && !slices.Contains(allowedScopes, c.Scope) {
+		return fmt.Errorf("upstream.*.failsafe.circuitBreaker.scope '%s' is invalid must be one of: %v", c.Scope, allowedScopes)
+	}
+	if c.Scope == CircuitBreakerScopeMethodGroup && len(c.MethodGroups) == 0 {
+		return fmt.Errorf("upstream.*.failsafe.circuitBreaker.methodGroups is required when scope is methodGroup")
+	}
+	for _, g := range c.MethodGroups {
+		if g.Name == "" {
+			return fmt.Errorf("upstream.*.failsafe.circuitBreaker.methodGroups.*.name is required")
+		}
+		if g.Methods == "" {
+			return fmt.Errorf("upstream.*.failsafe.circuitBreaker.methodGroups.*.methods is required")
+		}
+		if _, err := WildcardMatch(g.Methods, "eth_call"); err != nil {
+			return fmt.Errorf("upstream.*.failsafe.circuitBreaker.methodGroups.*.methods is invalid: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -727,6 +1188,24 @@ func (n *NetworkConfig) Validate(c *Config) error {
 			return err
 		}
 	}
+	if n.UpstreamSelection != nil {
+		if err := n.UpstreamSelection.Validate(); err != nil {
+			return err
+		}
+	}
+	seenGroups := map[string]bool{}
+	for _, g := range n.UpstreamGroups {
+		if g == nil || g.Name == "" {
+			return fmt.Errorf("network.*.upstreamGroups.*.name is required")
+		}
+		if seenGroups[g.Name] {
+			return fmt.Errorf("network.*.upstreamGroups has duplicate group '%s'", g.Name)
+		}
+		seenGroups[g.Name] = true
+		if g.MinHealthyUpstreams < 0 {
+			return fmt.Errorf("network.*.upstreamGroups.*.minHealthyUpstreams must be greater than or equal to 0")
+		}
+	}
 	if n.RateLimitBudget != "" {
 		if !c.HasRateLimiterBudget(n.RateLimitBudget) {
 			return fmt.Errorf("network.*.rateLimitBudget '%s' does not exist in config.rateLimiters", n.RateLimitBudget)
@@ -735,6 +1214,40 @@ func (n *NetworkConfig) Validate(c *Config) error {
 	return nil
 }
 
+func (s *UpstreamSelectionConfig) Validate() error {
+	modes := []UpstreamSelectionMode{
+		UpstreamSelectionModeOrdered,
+		UpstreamSelectionModeWeightedRandom,
+		UpstreamSelectionModePowerOfTwoChoices,
+	}
+	if !slices.Contains(modes, s.Mode) {
+		return fmt.Errorf("network.*.upstreamSelection.mode '%s' is invalid must be one of: %v", s.Mode, modes)
+	}
+	if s.Sticky != nil {
+		if err := s.Sticky.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StickySessionConfig) Validate() error {
+	keys := []StickySessionKey{StickySessionKeyIdentity, StickySessionKeyHeader, StickySessionKeyIP}
+	if !slices.Contains(keys, s.KeyBy) {
+		return fmt.Errorf("network.*.upstreamSelection.sticky.keyBy '%s' is invalid must be one of: %v", s.KeyBy, keys)
+	}
+	if s.KeyBy == StickySessionKeyHeader && s.Header == "" {
+		return fmt.Errorf("network.*.upstreamSelection.sticky.header is required when keyBy is header")
+	}
+	if _, err := time.ParseDuration(s.Window); err != nil {
+		return fmt.Errorf("network.*.upstreamSelection.sticky.window is invalid: %w", err)
+	}
+	if s.MaxSessions < 0 {
+		return fmt.Errorf("network.*.upstreamSelection.sticky.maxSessions must be greater than or equal to 0")
+	}
+	return nil
+}
+
 func (e *EvmNetworkConfig) Validate() error {
 	return nil
 }
@@ -777,5 +1290,8 @@ func (p *ScoreMultiplierConfig) Validate() error {
 	if p.FinalizationLag < 0 {
 		return fmt.Errorf("priorityMultipliers.*.finalizationLag multiplier must be greater than or equal to 0")
 	}
+	if p.InFlight < 0 {
+		return fmt.Errorf("priorityMultipliers.*.inFlight multiplier must be greater than or equal to 0")
+	}
 	return nil
 }
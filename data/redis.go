@@ -67,6 +67,23 @@ func NewRedisConnector(
 }
 
 func (r *RedisConnector) connect(ctx context.Context, cfg *common.RedisConnectorConfig) error {
+	options, err := newRedisOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	r.client = redis.NewClient(options)
+
+	// Test the connection
+	_, err = r.client.Ping(ctx).Result()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return nil
+}
+
+func newRedisOptions(cfg *common.RedisConnectorConfig) (*redis.Options, error) {
 	options := &redis.Options{
 		Addr:     cfg.Addr,
 		Password: cfg.Password,
@@ -77,20 +94,12 @@ func (r *RedisConnector) connect(ctx context.Context, cfg *common.RedisConnector
 	if cfg.TLS != nil && cfg.TLS.Enabled {
 		tlsConfig, err := createTLSConfig(cfg.TLS)
 		if err != nil {
-			return fmt.Errorf("failed to create TLS config: %w", err)
+			return nil, fmt.Errorf("failed to create TLS config: %w", err)
 		}
 		options.TLSConfig = tlsConfig
 	}
 
-	r.client = redis.NewClient(options)
-
-	// Test the connection
-	_, err := r.client.Ping(ctx).Result()
-	if err != nil {
-		return fmt.Errorf("failed to connect to Redis: %w", err)
-	}
-
-	return nil
+	return options, nil
 }
 
 func createTLSConfig(tlsCfg *common.TLSConfig) (*tls.Config, error) {
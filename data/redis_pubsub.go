@@ -0,0 +1,122 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// RedisPubSub is a minimal broadcast transport on top of a Redis channel, used to gossip
+// state between eRPC replicas (e.g. shared upstream health signals). Its client is created from
+// a redis connector config and closed once the context is cancelled.
+type RedisPubSub struct {
+	logger  *zerolog.Logger
+	channel string
+	client  atomic.Pointer[redis.Client]
+}
+
+func NewRedisPubSub(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	cfg *common.ConnectorConfig,
+	channel string,
+) (*RedisPubSub, error) {
+	if cfg == nil || cfg.Driver != common.DriverRedis || cfg.Redis == nil {
+		return nil, fmt.Errorf("a redis connector is required for pub/sub")
+	}
+
+	lg := logger.With().Str("component", "redisPubSub").Str("connector", cfg.Id).Str("channel", channel).Logger()
+	ps := &RedisPubSub{
+		logger:  &lg,
+		channel: channel,
+	}
+
+	options, err := newRedisOptions(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attempt the actual connecting in background to avoid blocking the main thread.
+	go func() {
+		for i := 0; i < 600; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				client := redis.NewClient(options)
+				_, err := client.Ping(ctx).Result()
+				if err == nil {
+					ps.client.Store(client)
+					lg.Debug().Msg("connected to Redis for pub/sub")
+					<-ctx.Done()
+					if err := client.Close(); err != nil {
+						lg.Warn().Err(err).Msg("failed to close Redis pub/sub client")
+					}
+					return
+				}
+				_ = client.Close()
+				lg.Warn().Err(err).Msgf("failed to connect to Redis for pub/sub (attempt %d)", i+1)
+				time.Sleep(30 * time.Second)
+			}
+		}
+		lg.Error().Msg("failed to connect to Redis for pub/sub after maximum attempts")
+	}()
+
+	return ps, nil
+}
+
+func (p *RedisPubSub) Publish(ctx context.Context, payload []byte) error {
+	client := p.client.Load()
+	if client == nil {
+		return fmt.Errorf("redis pub/sub is not connected yet")
+	}
+	return client.Publish(ctx, p.channel, payload).Err()
+}
+
+// Subscribe returns a channel receiving every message published on the channel (including
+// the ones published by this replica). It is closed when the context is cancelled.
+func (p *RedisPubSub) Subscribe(ctx context.Context) <-chan []byte {
+	out := make(chan []byte, 64)
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		var client *redis.Client
+		for client == nil {
+			if client = p.client.Load(); client != nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+
+		sub := client.Subscribe(ctx, p.channel)
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				default:
+					p.logger.Warn().Msg("dropping pub/sub message because consumer is too slow")
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,271 @@
+package erpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+)
+
+const capturePartitionKeyPrefix = "erpc-capture"
+
+// CaptureRecord is what gets stored for each captured request.
+type CaptureRecord struct {
+	Id        string          `json:"id"`
+	Timestamp int64           `json:"timestamp"`
+	ProjectId string          `json:"projectId"`
+	NetworkId string          `json:"networkId"`
+	Method    string          `json:"method"`
+	Request   json.RawMessage `json:"request"`
+	// Redacted is true when params of the request are redacted, so it cannot be replayed.
+	Redacted  bool              `json:"redacted,omitempty"`
+	Attempts  []*CaptureAttempt `json:"attempts"`
+	Response  json.RawMessage   `json:"response,omitempty"`
+	ErrorCode string            `json:"errorCode,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// CaptureAttempt is the raw response (or error) of a single upstream attempt, including retries and hedges.
+type CaptureAttempt struct {
+	UpstreamId string          `json:"upstreamId"`
+	DurationMs int64           `json:"durationMs"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	ErrorCode  string          `json:"errorCode,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+type captureStore interface {
+	Save(ctx context.Context, rec *CaptureRecord, payload []byte) error
+}
+
+type Capturer struct {
+	logger    *zerolog.Logger
+	projectId string
+	cfg       *common.CaptureConfig
+	store     captureStore
+}
+
+func NewCapturer(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	projectId string,
+	cfg *common.CaptureConfig,
+) (*Capturer, error) {
+	lg := logger.With().Str("component", "capture").Logger()
+	c := &Capturer{
+		logger:    &lg,
+		projectId: projectId,
+		cfg:       cfg,
+	}
+
+	if cfg.Connector != nil {
+		ttl, err := time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid capture ttl: %v", err)
+		}
+		connector, err := data.NewConnector(ctx, &lg, cfg.Connector)
+		if err != nil {
+			return nil, err
+		}
+		c.store = &connectorCaptureStore{connector: connector, projectId: projectId, ttl: ttl}
+	} else {
+		c.store = &fileCaptureStore{path: cfg.File}
+	}
+
+	return c, nil
+}
+
+// shouldCapture returns true when the request has the capture directive (if allowed) or matches one of the sampling rules.
+func (c *Capturer) shouldCapture(nq *common.NormalizedRequest, networkId, method string) bool {
+	if c.cfg.AllowDirective {
+		if dr := nq.Directives(); dr != nil && dr.Capture {
+			return true
+		}
+	}
+	for _, rule := range c.cfg.Sampling {
+		if m, _ := common.WildcardMatch(rule.Network, networkId); !m {
+			continue
+		}
+		if m, _ := common.WildcardMatch(rule.Method, method); !m {
+			continue
+		}
+		return rule.Rate >= 1 || (rule.Rate > 0 && mathrand.Float64() < rule.Rate) // #nosec G404
+	}
+	return false
+}
+
+// Begin starts capturing the request when needed, and returns a context that carries the capture session
+// so that upstream attempts made within this context are recorded.
+func (c *Capturer) Begin(ctx context.Context, nq *common.NormalizedRequest, networkId, method string) (context.Context, *captureSession) {
+	if c == nil || !c.shouldCapture(nq, networkId, method) {
+		return ctx, nil
+	}
+
+	var rnd [6]byte
+	_, _ = rand.Read(rnd[:])
+	request, redacted := c.capturedRequest(nq, method)
+	s := &captureSession{
+		capturer: c,
+		record: &CaptureRecord{
+			Id:        fmt.Sprintf("%d-%s", time.Now().UnixMilli(), hex.EncodeToString(rnd[:])),
+			Timestamp: time.Now().UnixMilli(),
+			ProjectId: c.projectId,
+			NetworkId: networkId,
+			Method:    method,
+			Request:   request,
+			Redacted:  redacted,
+		},
+	}
+	return context.WithValue(ctx, captureSessionContextKey{}, s), s
+}
+
+// capturedRequest returns the request body to store, with params redacted when the method matches redactMethods.
+func (c *Capturer) capturedRequest(nq *common.NormalizedRequest, method string) (json.RawMessage, bool) {
+	redact := false
+	for _, pattern := range c.cfg.RedactMethods {
+		if m, _ := common.WildcardMatch(pattern, method); m {
+			redact = true
+			break
+		}
+	}
+	if !redact {
+		return json.RawMessage(nq.Body()), false
+	}
+
+	jrq, err := nq.JsonRpcRequest()
+	if err != nil {
+		return nil, true
+	}
+	jrq.RLock()
+	payload, err := common.SonicCfg.Marshal(map[string]interface{}{
+		"jsonrpc": jrq.JSONRPC,
+		"id":      jrq.ID,
+		"method":  jrq.Method,
+		"params":  util.RedactParams(jrq.Params),
+	})
+	jrq.RUnlock()
+	if err != nil {
+		return nil, true
+	}
+	return payload, true
+}
+
+type captureSessionContextKey struct{}
+
+type captureSession struct {
+	capturer *Capturer
+	mu       sync.Mutex
+	record   *CaptureRecord
+}
+
+func captureSessionFromContext(ctx context.Context) *captureSession {
+	if s, ok := ctx.Value(captureSessionContextKey{}).(*captureSession); ok {
+		return s
+	}
+	return nil
+}
+
+func (s *captureSession) recordAttempt(upstreamId string, resp *common.NormalizedResponse, err error, duration time.Duration) {
+	if s == nil {
+		return
+	}
+	attempt := &CaptureAttempt{
+		UpstreamId: upstreamId,
+		DurationMs: duration.Milliseconds(),
+	}
+	attempt.Response, attempt.ErrorCode, attempt.Error = captureResult(resp, err)
+
+	s.mu.Lock()
+	s.record.Attempts = append(s.record.Attempts, attempt)
+	s.mu.Unlock()
+}
+
+// finish adds the final response (or error) and stores the capture in background. The response is serialized
+// synchronously since it might be released right after being written to the client.
+func (s *captureSession) finish(resp *common.NormalizedResponse, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.record.Response, s.record.ErrorCode, s.record.Error = captureResult(resp, err)
+	payload, merr := common.SonicCfg.Marshal(s.record)
+	s.mu.Unlock()
+	if merr != nil {
+		s.capturer.logger.Warn().Err(merr).Str("captureId", s.record.Id).Msg("failed to serialize captured request")
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.capturer.store.Save(ctx, s.record, payload); err != nil {
+			s.capturer.logger.Warn().Err(err).Str("captureId", s.record.Id).Msg("failed to store captured request")
+		} else {
+			s.capturer.logger.Debug().Str("captureId", s.record.Id).Msg("stored captured request")
+		}
+	}()
+}
+
+func captureResult(resp *common.NormalizedResponse, err error) (json.RawMessage, string, string) {
+	if err != nil {
+		code := "ErrUnknown"
+		if se, ok := err.(common.StandardError); ok {
+			code = string(se.Base().Code)
+		}
+		return nil, code, err.Error()
+	}
+	if resp == nil {
+		return nil, "", ""
+	}
+	jrr, jerr := resp.JsonRpcResponse()
+	if jerr != nil || jrr == nil {
+		return nil, "", ""
+	}
+	var buf bytes.Buffer
+	if _, werr := jrr.WriteTo(&buf); werr != nil {
+		return nil, "", ""
+	}
+	return buf.Bytes(), "", ""
+}
+
+// connectorCaptureStore writes each capture to the connector keyed by "erpc-capture:<projectId>" and capture id.
+type connectorCaptureStore struct {
+	connector data.Connector
+	projectId string
+	ttl       time.Duration
+}
+
+func (s *connectorCaptureStore) Save(ctx context.Context, rec *CaptureRecord, payload []byte) error {
+	return s.connector.Set(ctx, fmt.Sprintf("%s:%s", capturePartitionKeyPrefix, s.projectId), rec.Id, string(payload), &s.ttl)
+}
+
+// fileCaptureStore appends each capture as a line of a JSONL file, which can be used by the replay command.
+type fileCaptureStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileCaptureStore) Save(ctx context.Context, rec *CaptureRecord, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(payload, '\n'))
+	return err
+}
@@ -0,0 +1,154 @@
+package erpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture(t *testing.T) {
+	logger := log.Logger
+
+	newCapturer := func(t *testing.T, sampling ...*common.CaptureSamplingConfig) (*Capturer, string) {
+		path := filepath.Join(t.TempDir(), "captures.jsonl")
+		cfg := &common.CaptureConfig{File: path, Sampling: sampling, AllowDirective: true}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		c, err := NewCapturer(context.Background(), &logger, "test", cfg)
+		require.NoError(t, err)
+		return c, path
+	}
+
+	t.Run("CapturesOnlyWithDirectiveOrMatchingSamplingRule", func(t *testing.T) {
+		c, _ := newCapturer(t, &common.CaptureSamplingConfig{Method: "eth_getLogs", Rate: 1})
+
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[]}`))
+		assert.False(t, c.shouldCapture(nq, "evm:1", "eth_call"))
+		assert.True(t, c.shouldCapture(nq, "evm:1", "eth_getLogs"))
+
+		nq.ApplyDirectivesFromHttp(http.Header{}, url.Values{"capture": []string{"true"}})
+		assert.True(t, c.shouldCapture(nq, "evm:1", "eth_call"))
+	})
+
+	t.Run("IgnoresDirectiveUnlessAllowed", func(t *testing.T) {
+		c, _ := newCapturer(t)
+		c.cfg.AllowDirective = false
+
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[]}`))
+		nq.ApplyDirectivesFromHttp(http.Header{"X-Erpc-Capture": []string{"true"}}, url.Values{})
+		assert.False(t, c.shouldCapture(nq, "evm:1", "eth_call"))
+	})
+
+	t.Run("RedactsParamsOfSensitiveMethods", func(t *testing.T) {
+		c, _ := newCapturer(t)
+
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0xdeadbeef"]}`))
+		nq.ApplyDirectivesFromHttp(http.Header{"X-Erpc-Capture": []string{"true"}}, url.Values{})
+		_, cs := c.Begin(context.Background(), nq, "evm:1", "eth_sendRawTransaction")
+		require.NotNil(t, cs)
+		assert.True(t, cs.record.Redacted)
+		assert.NotContains(t, string(cs.record.Request), "0xdeadbeef")
+		assert.Contains(t, string(cs.record.Request), "redacted=")
+
+		nq = common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":["0xdeadbeef"]}`))
+		nq.ApplyDirectivesFromHttp(http.Header{"X-Erpc-Capture": []string{"true"}}, url.Values{})
+		_, cs = c.Begin(context.Background(), nq, "evm:1", "eth_call")
+		require.NotNil(t, cs)
+		assert.False(t, cs.record.Redacted)
+		assert.Contains(t, string(cs.record.Request), "0xdeadbeef")
+	})
+
+	t.Run("StoresAttemptsAndFinalResponseInFile", func(t *testing.T) {
+		c, path := newCapturer(t)
+
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`))
+		nq.ApplyDirectivesFromHttp(http.Header{"X-Erpc-Capture": []string{"true"}}, url.Values{})
+
+		ctx, cs := c.Begin(context.Background(), nq, "evm:1", "eth_chainId")
+		require.NotNil(t, cs)
+
+		jrr, err := common.NewJsonRpcResponse(1, "0x1", nil)
+		require.NoError(t, err)
+		resp := common.NewNormalizedResponse().WithRequest(nq).WithJsonRpcResponse(jrr)
+
+		captureSessionFromContext(ctx).recordAttempt("rpc1", nil, common.NewErrEndpointServerSideException(errors.New("boom"), nil), time.Millisecond)
+		captureSessionFromContext(ctx).recordAttempt("rpc2", resp, nil, time.Millisecond)
+		cs.finish(resp, nil)
+
+		var content []byte
+		require.Eventually(t, func() bool {
+			content, _ = os.ReadFile(path)
+			return len(content) > 0
+		}, time.Second, 10*time.Millisecond)
+
+		rec := &CaptureRecord{}
+		require.NoError(t, common.SonicCfg.Unmarshal([]byte(strings.TrimSpace(string(content))), rec))
+		assert.Equal(t, "eth_chainId", rec.Method)
+		require.Len(t, rec.Attempts, 2)
+		assert.Equal(t, "rpc1", rec.Attempts[0].UpstreamId)
+		assert.Equal(t, string(common.ErrCodeEndpointServerSideException), rec.Attempts[0].ErrorCode)
+		assert.Equal(t, "rpc2", rec.Attempts[1].UpstreamId)
+		assert.Contains(t, string(rec.Attempts[1].Response), `"result":"0x1"`)
+		assert.Contains(t, string(rec.Response), `"result":"0x1"`)
+	})
+
+	t.Run("StoresCaptureInConnector", func(t *testing.T) {
+		cfg := &common.CaptureConfig{
+			Connector: &common.ConnectorConfig{
+				Id:     "captures",
+				Driver: common.DriverMemory,
+				Memory: &common.MemoryConnectorConfig{MaxItems: 100},
+			},
+			AllowDirective: true,
+		}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		c, err := NewCapturer(context.Background(), &logger, "test", cfg)
+		require.NoError(t, err)
+		store, ok := c.store.(*connectorCaptureStore)
+		require.True(t, ok)
+
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`))
+		nq.ApplyDirectivesFromHttp(http.Header{"X-Erpc-Capture": []string{"true"}}, url.Values{})
+		_, cs := c.Begin(context.Background(), nq, "evm:1", "eth_chainId")
+		require.NotNil(t, cs)
+
+		jrr, err := common.NewJsonRpcResponse(1, "0x1", nil)
+		require.NoError(t, err)
+		cs.finish(common.NewNormalizedResponse().WithRequest(nq).WithJsonRpcResponse(jrr), nil)
+
+		var content string
+		require.Eventually(t, func() bool {
+			content, err = store.connector.Get(context.Background(), data.ConnectorMainIndex, "erpc-capture:test", cs.record.Id)
+			return err == nil && content != ""
+		}, time.Second, 10*time.Millisecond)
+		assert.Contains(t, content, `"result":"0x1"`)
+	})
+
+	t.Run("ReplayResultIgnoresIds", func(t *testing.T) {
+		assert.True(t, sameReplayResult(
+			[]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`), "",
+			[]byte(`{"jsonrpc":"2.0","id":7,"result":"0x1"}`), "",
+		))
+		assert.False(t, sameReplayResult(
+			[]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`), "",
+			[]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2"}`), "",
+		))
+		assert.False(t, sameReplayResult(
+			[]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`), "",
+			nil, "ErrUpstreamsExhausted",
+		))
+	})
+}
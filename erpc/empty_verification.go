@@ -0,0 +1,85 @@
+package erpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
+	"github.com/rs/zerolog"
+)
+
+// emptyVerifiableMethods are methods for which an empty result might mean the upstream is missing data
+// (e.g. not synced yet or pruned) rather than the data actually being empty.
+var emptyVerifiableMethods = map[string]bool{
+	"eth_getLogs":               true,
+	"eth_getTransactionReceipt": true,
+	"eth_getBlockByNumber":      true,
+}
+
+// verifyEmptyResponse confirms an empty response against another upstream that is synced at least up to the
+// requested block (or up to the block of the upstream that served the empty response when the request has no block).
+// It returns the response to use and whether it is confirmed. When the other upstream has the data its response
+// is returned, and the upstream that returned empty is penalized as missing data.
+func (n *Network) verifyEmptyResponse(
+	ctx context.Context,
+	lg *zerolog.Logger,
+	req *common.NormalizedRequest,
+	method string,
+	resp *common.NormalizedResponse,
+	upsList []*upstream.Upstream,
+) (*common.NormalizedResponse, bool) {
+	servedId := resp.UpstreamId()
+
+	_, minBlock, _ := req.EvmBlockRefAndNumber()
+	if minBlock <= 0 {
+		if poller := n.evmStatePollers[servedId]; poller != nil && !poller.IsObjectNull() {
+			minBlock = poller.LatestBlock()
+		}
+	}
+
+	for _, u := range upsList {
+		upsId := u.Config().Id
+		if upsId == servedId {
+			continue
+		}
+		poller := n.evmStatePollers[upsId]
+		if poller == nil || poller.IsObjectNull() || poller.LatestBlock() < minBlock {
+			continue
+		}
+		ulg := lg.With().Str("upstreamId", upsId).Str("emptyFrom", servedId).Int64("minBlock", minBlock).Logger()
+		if err := n.acquireSelectionPolicyPermit(ctx, &ulg, u, req); err != nil {
+			continue
+		}
+
+		ulg.Debug().Msgf("verifying empty response against another upstream")
+		verifyStartedAt := time.Now()
+		vr, err := u.Forward(ctx, req, false)
+		captureSessionFromContext(ctx).recordAttempt(upsId, vr, err, time.Since(verifyStartedAt))
+		if err == nil {
+			err = n.normalizeResponse(req, vr)
+		}
+		if err != nil || vr == nil {
+			ulg.Debug().Err(err).Msgf("could not verify empty response against upstream")
+			continue
+		}
+
+		if vr.IsResultEmptyish() {
+			health.MetricNetworkEmptyResponseVerificationTotal.WithLabelValues(n.ProjectId, n.NetworkId, method, "confirmed").Inc()
+			return resp, true
+		}
+
+		ulg.Info().Msgf("upstream returned empty response while another upstream has the data")
+		health.MetricNetworkEmptyResponseVerificationTotal.WithLabelValues(n.ProjectId, n.NetworkId, method, "contradicted").Inc()
+		if servedId != "" && n.metricsTracker != nil {
+			n.metricsTracker.RecordUpstreamFailure(servedId, n.NetworkId, method, common.ErrCodeEndpointMissingData)
+			health.MetricUpstreamMissingDataErrorTotal.WithLabelValues(n.ProjectId, servedId, n.NetworkId, method).Inc()
+		}
+		vr.SetUpstream(u)
+		return vr, true
+	}
+
+	health.MetricNetworkEmptyResponseVerificationTotal.WithLabelValues(n.ProjectId, n.NetworkId, method, "unverified").Inc()
+	return resp, false
+}
@@ -15,6 +15,7 @@ type ERPC struct {
 	cfg               *common.Config
 	projectsRegistry  *ProjectsRegistry
 	adminAuthRegistry *auth.AuthRegistry
+	evmJsonRpcCache   *EvmJsonRpcCache
 }
 
 func NewERPC(
@@ -43,7 +44,7 @@ func NewERPC(
 
 	var adminAuthRegistry *auth.AuthRegistry
 	if cfg.Admin != nil && cfg.Admin.Auth != nil {
-		adminAuthRegistry, err = auth.NewAuthRegistry(logger, "admin", cfg.Admin.Auth, rateLimitersRegistry)
+		adminAuthRegistry, err = auth.NewAuthRegistry(ctx, logger, "admin", cfg.Admin.Auth, rateLimitersRegistry)
 		if err != nil {
 			return nil, err
 		}
@@ -53,15 +54,17 @@ func NewERPC(
 		cfg:               cfg,
 		projectsRegistry:  projectRegistry,
 		adminAuthRegistry: adminAuthRegistry,
+		evmJsonRpcCache:   evmJsonRpcCache,
 	}, nil
 }
 
 func (e *ERPC) AdminAuthenticate(ctx context.Context, nq *common.NormalizedRequest, ap *auth.AuthPayload) error {
 	if e.adminAuthRegistry != nil {
-		err := e.adminAuthRegistry.Authenticate(ctx, nq, ap)
+		user, err := e.adminAuthRegistry.Authenticate(ctx, nq, ap)
 		if err != nil {
 			return err
 		}
+		nq.SetUser(user)
 	}
 	return nil
 }
@@ -182,3 +185,11 @@ func (e *ERPC) GetProject(projectId string) (*PreparedProject, error) {
 func (e *ERPC) GetProjects() []*PreparedProject {
 	return e.projectsRegistry.GetAll()
 }
+
+// CheckCacheConnectors returns reachability of each cache connector keyed by connector id (nil error means reachable).
+func (e *ERPC) CheckCacheConnectors(ctx context.Context) map[string]error {
+	if e.evmJsonRpcCache == nil {
+		return map[string]error{}
+	}
+	return e.evmJsonRpcCache.CheckConnectors(ctx)
+}
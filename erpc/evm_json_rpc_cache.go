@@ -10,6 +10,7 @@ import (
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/data"
 	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/rs/zerolog"
 )
@@ -93,7 +94,17 @@ func (c *EvmJsonRpcCache) Get(ctx context.Context, req *common.NormalizedRequest
 	var jrr *common.JsonRpcResponse
 	for _, policy := range policies {
 		connector := policy.GetConnector()
-		jrr, err = c.doGet(ctx, connector, req, rpcReq)
+		gctx, span := telemetry.StartSpan(
+			ctx,
+			telemetry.SpanKindInternal,
+			"erpc.cache.get",
+			telemetry.String("erpc.cache.connector", connector.Id()),
+			telemetry.String("erpc.cache.policy", policy.String()),
+		)
+		jrr, err = c.doGet(gctx, connector, req, rpcReq)
+		span.SetAttributes(telemetry.Bool("erpc.cache.hit", jrr != nil))
+		span.RecordError(err)
+		span.End()
 		if jrr != nil {
 			health.MetricCacheGetSuccessHitTotal.WithLabelValues(
 				c.network.ProjectId,
@@ -259,7 +270,16 @@ func (c *EvmJsonRpcCache) Set(ctx context.Context, req *common.NormalizedRequest
 
 			ctx, cancel := context.WithTimeoutCause(ctx, 5*time.Second, errors.New("evm json-rpc cache driver timeout during set"))
 			defer cancel()
+			ctx, span := telemetry.StartSpan(
+				ctx,
+				telemetry.SpanKindInternal,
+				"erpc.cache.set",
+				telemetry.String("erpc.cache.connector", connector.Id()),
+				telemetry.String("erpc.cache.policy", policy.String()),
+			)
 			err = connector.Set(ctx, pk, rk, util.Mem2Str(rpcResp.Result), ttl)
+			span.RecordError(err)
+			span.End()
 			if err != nil {
 				errsMu.Lock()
 				errs = append(errs, err)
@@ -304,6 +324,24 @@ func (c *EvmJsonRpcCache) MethodConfig(method string) *common.CacheMethodConfig
 	return nil
 }
 
+// CheckConnectors reads a sentinel key from every connector used by cache policies, a not-found
+// result means the connector answered so it is considered reachable.
+func (c *EvmJsonRpcCache) CheckConnectors(ctx context.Context) map[string]error {
+	result := make(map[string]error)
+	for _, policy := range c.policies {
+		connector := policy.GetConnector()
+		if _, checked := result[connector.Id()]; checked {
+			continue
+		}
+		_, err := connector.Get(ctx, data.ConnectorMainIndex, "erpc-readiness", "probe")
+		if err != nil && common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			err = nil
+		}
+		result[connector.Id()] = err
+	}
+	return result
+}
+
 func (c *EvmJsonRpcCache) IsObjectNull() bool {
 	return c == nil || c.network == nil
 }
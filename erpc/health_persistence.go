@@ -0,0 +1,239 @@
+package erpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
+	"github.com/rs/zerolog"
+)
+
+const healthSnapshotPartitionKey = "erpc-health-snapshot"
+
+// restoredLatencySamples is how many samples of the persisted p90 latency are added to the latency window
+// when restoring a fresh snapshot, fewer samples are added as the snapshot decays.
+const restoredLatencySamples = 10
+
+// HealthSnapshot is what gets persisted periodically for a project, so that scoring metrics,
+// cordons and autotuned rate limit budgets survive restarts.
+type HealthSnapshot struct {
+	ProjectId string                         `json:"projectId"`
+	Timestamp int64                          `json:"timestamp"`
+	Tracker   *health.TrackerSnapshot        `json:"tracker"`
+	Budgets   []*upstream.BudgetRuleSnapshot `json:"budgets,omitempty"`
+}
+
+type healthSnapshotStore interface {
+	Load(ctx context.Context) ([]byte, error)
+	Save(ctx context.Context, payload []byte) error
+}
+
+type HealthPersister struct {
+	logger               *zerolog.Logger
+	projectId            string
+	tracker              *health.Tracker
+	rateLimitersRegistry *upstream.RateLimitersRegistry
+	budgets              map[string]bool
+	store                healthSnapshotStore
+
+	interval      time.Duration
+	maxAge        time.Duration
+	decayHalfLife time.Duration
+}
+
+func NewHealthPersister(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	projectId string,
+	tracker *health.Tracker,
+	rateLimitersRegistry *upstream.RateLimitersRegistry,
+	budgetIds []string,
+	cfg *common.HealthPersistenceConfig,
+) (*HealthPersister, error) {
+	lg := logger.With().Str("component", "healthPersister").Logger()
+	p := &HealthPersister{
+		logger:               &lg,
+		projectId:            projectId,
+		tracker:              tracker,
+		rateLimitersRegistry: rateLimitersRegistry,
+		budgets:              make(map[string]bool, len(budgetIds)),
+	}
+	// Rate limiters registry is shared by all projects, so only budgets used by upstreams of this project
+	// are persisted and restored, otherwise projects would overwrite each other's adjustments.
+	for _, id := range budgetIds {
+		p.budgets[id] = true
+	}
+
+	var err error
+	if p.interval, err = time.ParseDuration(cfg.Interval); err != nil {
+		return nil, fmt.Errorf("invalid health persistence interval: %v", err)
+	}
+	if p.maxAge, err = time.ParseDuration(cfg.MaxAge); err != nil {
+		return nil, fmt.Errorf("invalid health persistence maxAge: %v", err)
+	}
+	if p.decayHalfLife, err = time.ParseDuration(cfg.DecayHalfLife); err != nil {
+		return nil, fmt.Errorf("invalid health persistence decayHalfLife: %v", err)
+	}
+
+	if cfg.Connector != nil {
+		connector, err := data.NewConnector(ctx, &lg, cfg.Connector)
+		if err != nil {
+			return nil, err
+		}
+		p.store = &connectorHealthSnapshotStore{connector: connector, projectId: projectId, ttl: p.maxAge}
+	} else {
+		p.store = &fileHealthSnapshotStore{path: cfg.File}
+	}
+
+	return p, nil
+}
+
+// Bootstrap restores the last snapshot (in background, since connectors might still be connecting)
+// and then persists a new snapshot every interval and once more on shutdown.
+func (p *HealthPersister) Bootstrap(ctx context.Context) {
+	go func() {
+		for i := 0; i < 10; i++ {
+			err := p.Restore(ctx)
+			if err == nil {
+				break
+			}
+			p.logger.Warn().Err(err).Msgf("failed to restore health snapshot (attempt %d)", i+1)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(3 * time.Second):
+			}
+		}
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := p.Persist(sctx); err != nil {
+					p.logger.Warn().Err(err).Msg("failed to persist health snapshot on shutdown")
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				if err := p.Persist(ctx); err != nil {
+					p.logger.Warn().Err(err).Msg("failed to persist health snapshot")
+				}
+			}
+		}
+	}()
+}
+
+func (p *HealthPersister) Persist(ctx context.Context) error {
+	snapshot := &HealthSnapshot{
+		ProjectId: p.projectId,
+		Timestamp: time.Now().UnixMilli(),
+		Tracker:   p.tracker.Snapshot(),
+	}
+	if p.rateLimitersRegistry != nil {
+		snapshot.Budgets = p.ownBudgets(p.rateLimitersRegistry.SnapshotBudgets())
+	}
+	payload, err := common.SonicCfg.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return p.store.Save(ctx, payload)
+}
+
+func (p *HealthPersister) Restore(ctx context.Context) error {
+	payload, err := p.store.Load(ctx)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) || os.IsNotExist(err) {
+			p.logger.Debug().Msg("no previous health snapshot found")
+			return nil
+		}
+		return err
+	}
+
+	snapshot := &HealthSnapshot{}
+	if err := common.SonicCfg.Unmarshal(payload, snapshot); err != nil {
+		return fmt.Errorf("failed to parse health snapshot: %w", err)
+	}
+	if snapshot.ProjectId != p.projectId {
+		return nil
+	}
+
+	age := time.Since(time.UnixMilli(snapshot.Timestamp))
+	if age < 0 {
+		age = 0
+	}
+	if age > p.maxAge {
+		p.logger.Info().Dur("age", age).Msg("ignoring health snapshot older than maxAge")
+		return nil
+	}
+
+	decay := math.Pow(0.5, age.Seconds()/p.decayHalfLife.Seconds())
+	p.tracker.Restore(snapshot.Tracker, decay, time.Duration(float64(p.decayHalfLife)*decay), restoredLatencySamples)
+	if p.rateLimitersRegistry != nil {
+		p.rateLimitersRegistry.RestoreBudgets(p.ownBudgets(snapshot.Budgets), decay)
+	}
+	p.logger.Info().Dur("age", age).Float64("decay", decay).Msg("restored health snapshot")
+
+	return nil
+}
+
+func (p *HealthPersister) ownBudgets(rules []*upstream.BudgetRuleSnapshot) []*upstream.BudgetRuleSnapshot {
+	owned := make([]*upstream.BudgetRuleSnapshot, 0, len(rules))
+	for _, rs := range rules {
+		if p.budgets[rs.Budget] {
+			owned = append(owned, rs)
+		}
+	}
+	return owned
+}
+
+type connectorHealthSnapshotStore struct {
+	connector data.Connector
+	projectId string
+	ttl       time.Duration
+}
+
+func (s *connectorHealthSnapshotStore) Load(ctx context.Context) ([]byte, error) {
+	value, err := s.connector.Get(ctx, data.ConnectorMainIndex, healthSnapshotPartitionKey, s.projectId)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+func (s *connectorHealthSnapshotStore) Save(ctx context.Context, payload []byte) error {
+	return s.connector.Set(ctx, healthSnapshotPartitionKey, s.projectId, string(payload), &s.ttl)
+}
+
+type fileHealthSnapshotStore struct {
+	path string
+}
+
+func (s *fileHealthSnapshotStore) Load(ctx context.Context) ([]byte, error) {
+	return os.ReadFile(s.path)
+}
+
+// Save writes to a temporary file first so that a crash mid-write never leaves a corrupted snapshot.
+func (s *fileHealthSnapshotStore) Save(ctx context.Context, payload []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,150 @@
+package erpc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthPersister(t *testing.T) {
+	logger := log.Logger
+	networkId := "evm:123"
+
+	newPersister := func(t *testing.T, file string) (*HealthPersister, *health.Tracker, *upstream.RateLimitersRegistry) {
+		tracker := health.NewTracker("test", time.Minute)
+		rlr, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{
+			Budgets: []*common.RateLimitBudgetConfig{
+				{
+					Id: "budget1",
+					Rules: []*common.RateLimitRuleConfig{
+						{Method: "*", MaxCount: 100, Period: "1s"},
+					},
+				},
+				{
+					// Used by upstreams of another project
+					Id: "budget2",
+					Rules: []*common.RateLimitRuleConfig{
+						{Method: "*", MaxCount: 100, Period: "1s"},
+					},
+				},
+			},
+		}, &logger)
+		require.NoError(t, err)
+		cfg := &common.HealthPersistenceConfig{File: file}
+		cfg.SetDefaults()
+		p, err := NewHealthPersister(context.Background(), &logger, "test", tracker, rlr, []string{"budget1"}, cfg)
+		require.NoError(t, err)
+		return p, tracker, rlr
+	}
+
+	t.Run("RestoresMetricsCordonsAndBudgets", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "health.json")
+		ctx := context.Background()
+
+		before, tracker, rlr := newPersister(t, file)
+		for i := 0; i < 10; i++ {
+			tracker.RecordUpstreamRequest("rpc1", networkId, "eth_call")
+			if i < 8 {
+				tracker.RecordUpstreamFailure("rpc1", networkId, "eth_call", "test-error")
+			}
+		}
+		tracker.Cordon("rpc2", networkId, "*", "failing probes")
+		budget, err := rlr.GetBudget("budget1")
+		require.NoError(t, err)
+		require.NoError(t, budget.AdjustBudget(budget.Rules[0], 40))
+		require.NoError(t, before.Persist(ctx))
+
+		after, restoredTracker, restoredRlr := newPersister(t, file)
+		require.NoError(t, after.Restore(ctx))
+
+		metrics := restoredTracker.GetUpstreamMethodMetrics("rpc1", networkId, "eth_call")
+		assert.Equal(t, int64(10), metrics.RequestsTotal.Load())
+		assert.Equal(t, int64(8), metrics.ErrorsTotal.Load())
+		assert.True(t, restoredTracker.IsCordoned("rpc2", networkId, "eth_call"))
+
+		restoredBudget, err := restoredRlr.GetBudget("budget1")
+		require.NoError(t, err)
+		assert.Equal(t, uint(40), restoredBudget.Rules[0].Config.MaxCount)
+	})
+
+	t.Run("OnlyPersistsBudgetsOfProjectUpstreams", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "health.json")
+		ctx := context.Background()
+
+		before, _, rlr := newPersister(t, file)
+		for _, id := range []string{"budget1", "budget2"} {
+			budget, err := rlr.GetBudget(id)
+			require.NoError(t, err)
+			require.NoError(t, budget.AdjustBudget(budget.Rules[0], 40))
+		}
+		require.NoError(t, before.Persist(ctx))
+
+		payload, err := before.store.Load(ctx)
+		require.NoError(t, err)
+		snapshot := &HealthSnapshot{}
+		require.NoError(t, common.SonicCfg.Unmarshal(payload, snapshot))
+		require.Len(t, snapshot.Budgets, 1)
+		assert.Equal(t, "budget1", snapshot.Budgets[0].Budget)
+
+		// Adjustments of other projects' budgets found in a snapshot are not applied either
+		snapshot.Budgets = append(snapshot.Budgets, &upstream.BudgetRuleSnapshot{Budget: "budget2", Index: 0, Method: "*", MaxCount: 10})
+		payload, err = common.SonicCfg.Marshal(snapshot)
+		require.NoError(t, err)
+		require.NoError(t, before.store.Save(ctx, payload))
+
+		after, _, restoredRlr := newPersister(t, file)
+		require.NoError(t, after.Restore(ctx))
+		budget2, err := restoredRlr.GetBudget("budget2")
+		require.NoError(t, err)
+		assert.Equal(t, uint(100), budget2.Rules[0].Config.MaxCount)
+	})
+
+	t.Run("MissingSnapshotIsNotAnError", func(t *testing.T) {
+		p, _, _ := newPersister(t, filepath.Join(t.TempDir(), "missing.json"))
+		assert.NoError(t, p.Restore(context.Background()))
+	})
+
+	t.Run("OldSnapshotDecaysTowardsConfiguredValues", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "health.json")
+		p, tracker, _ := newPersister(t, file)
+
+		snapshot := &HealthSnapshot{
+			ProjectId: "test",
+			Timestamp: time.Now().Add(-p.decayHalfLife).UnixMilli(),
+			Tracker: &health.TrackerSnapshot{
+				Metrics: []*health.TrackedMetricsSnapshot{
+					{Upstream: "rpc1", Network: networkId, Method: "eth_call", Requests: 100, Errors: 50, LatencyP90: 2},
+				},
+			},
+			Budgets: []*upstream.BudgetRuleSnapshot{
+				{Budget: "budget1", Index: 0, Method: "*", MaxCount: 20},
+			},
+		}
+		payload, err := common.SonicCfg.Marshal(snapshot)
+		require.NoError(t, err)
+		require.NoError(t, p.store.Save(context.Background(), payload))
+		require.NoError(t, p.Restore(context.Background()))
+
+		metrics := tracker.GetUpstreamMethodMetrics("rpc1", networkId, "eth_call")
+		assert.InDelta(t, 50, metrics.RequestsTotal.Load(), 1)
+		assert.InDelta(t, 25, metrics.ErrorsTotal.Load(), 1)
+		// Half of the latency samples are restored, so adding as many new samples makes them the lower half
+		for i := 0; i < restoredLatencySamples/2; i++ {
+			metrics.LatencySecs.Add(0.1)
+		}
+		assert.Equal(t, 0.1, metrics.LatencySecs.Quantile(0.5))
+		assert.Equal(t, float64(2), metrics.LatencySecs.Quantile(0.6))
+
+		budget, err := p.rateLimitersRegistry.GetBudget("budget1")
+		require.NoError(t, err)
+		assert.InDelta(t, 60, budget.Rules[0].Config.MaxCount, 1)
+	})
+}
@@ -1,13 +1,20 @@
 package erpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"path"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
 )
 
 func (s *HttpServer) handleHealthCheck(w http.ResponseWriter, startedAt *time.Time, projectId string, encoder sonic.Encoder, writeFatalError func(statusCode int, body error)) {
@@ -70,3 +77,225 @@ func (s *HttpServer) handleHealthCheck(w http.ResponseWriter, startedAt *time.Ti
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("OK"))
 }
+
+// UpstreamHealthStatus describes whether an upstream is considered healthy for a network and why not.
+type UpstreamHealthStatus struct {
+	Id        string  `json:"id"`
+	Healthy   bool    `json:"healthy"`
+	Reason    string  `json:"reason,omitempty"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+type NetworkHealthStatus struct {
+	NetworkId           string                  `json:"networkId"`
+	Healthy             bool                    `json:"healthy"`
+	Reason              string                  `json:"reason,omitempty"`
+	HealthyUpstreams    int                     `json:"healthyUpstreams"`
+	MinHealthyUpstreams int                     `json:"minHealthyUpstreams"`
+	Upstreams           []*UpstreamHealthStatus `json:"upstreams"`
+}
+
+type ProjectHealthStatus struct {
+	ProjectId string                 `json:"projectId"`
+	Healthy   bool                   `json:"healthy"`
+	Networks  []*NetworkHealthStatus `json:"networks"`
+}
+
+type ReadinessStatus struct {
+	Ready           bool                   `json:"ready"`
+	Reason          string                 `json:"reason,omitempty"`
+	Projects        []*ProjectHealthStatus `json:"projects"`
+	CacheConnectors map[string]string      `json:"cacheConnectors"`
+}
+
+// parseHealthProbePath detects GET /livez, /readyz and /<project>/readyz
+// (or /readyz for the project selected via domain aliasing).
+func parseHealthProbePath(r *http.Request, preSelectedProjectId string) (isLiveness, isReadiness bool, projectId string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false, false, ""
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path.Clean(r.URL.Path), "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "livez":
+		return true, false, ""
+	case len(segments) == 1 && segments[0] == "readyz":
+		return false, true, preSelectedProjectId
+	case len(segments) == 2 && segments[1] == "readyz" && preSelectedProjectId == "":
+		return false, true, segments[0]
+	}
+
+	return false, false, ""
+}
+
+// handleLiveness only reflects that the process is able to serve http requests, so that orchestrators
+// do not restart eRPC when upstreams or cache connectors are degraded.
+func (s *HttpServer) handleLiveness(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"OK"}`))
+}
+
+// handleReadiness responds 200 only when config is loaded, every network has the minimum number of healthy
+// upstreams and all cache connectors are reachable, otherwise 503. The body always contains the detailed view.
+func (s *HttpServer) handleReadiness(w http.ResponseWriter, r *http.Request, startedAt *time.Time, projectId string, encoder sonic.Encoder, writeFatalError func(statusCode int, body error)) {
+	logger := s.logger.With().Str("handler", "readiness").Str("projectId", projectId).Logger()
+	w.Header().Set("Cache-Control", "no-store")
+
+	status := &ReadinessStatus{
+		Ready:           true,
+		Projects:        []*ProjectHealthStatus{},
+		CacheConnectors: map[string]string{},
+	}
+
+	if s.erpc == nil {
+		status.Ready = false
+		status.Reason = "eRPC is not initialized"
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = encoder.Encode(status)
+		return
+	}
+
+	var projects []*PreparedProject
+	if projectId == "" {
+		projects = s.erpc.GetProjects()
+	} else {
+		project, err := s.erpc.GetProject(projectId)
+		if err != nil {
+			handleErrorResponse(&logger, startedAt, nil, err, w, encoder, writeFatalError)
+			return
+		}
+		projects = []*PreparedProject{project}
+	}
+
+	reasons := []string{}
+	for _, project := range projects {
+		ph := project.GatherNetworksHealth()
+		status.Projects = append(status.Projects, ph)
+		if !ph.Healthy {
+			reasons = append(reasons, fmt.Sprintf("project %s has unhealthy networks", ph.ProjectId))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	for connectorId, err := range s.erpc.CheckCacheConnectors(ctx) {
+		if err != nil {
+			status.CacheConnectors[connectorId] = err.Error()
+			reasons = append(reasons, fmt.Sprintf("cache connector %s is not reachable", connectorId))
+		} else {
+			status.CacheConnectors[connectorId] = "OK"
+		}
+	}
+
+	if len(reasons) > 0 {
+		sort.Strings(reasons)
+		status.Ready = false
+		status.Reason = strings.Join(reasons, ", ")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if err := encoder.Encode(status); err != nil {
+		logger.Error().Err(err).Msg("failed to encode readiness response")
+	}
+}
+
+// GatherNetworksHealth evaluates every statically-defined or already initialized network of the project
+// against healthCheck.minHealthyUpstreams and healthCheck.maxErrorRate.
+func (p *PreparedProject) GatherNetworksHealth() *ProjectHealthStatus {
+	hcfg := p.Config.HealthCheck
+	minHealthy := 1
+	maxErrorRate := 0.99
+	if hcfg != nil {
+		if hcfg.MinHealthyUpstreams != nil {
+			minHealthy = *hcfg.MinHealthyUpstreams
+		}
+		if hcfg.MaxErrorRate != nil {
+			maxErrorRate = *hcfg.MaxErrorRate
+		}
+	}
+
+	networkIds := []string{}
+	for _, nwCfg := range p.Config.Networks {
+		networkIds = append(networkIds, nwCfg.NetworkId())
+	}
+	p.projectMu.RLock()
+	for nwId := range p.Networks {
+		if !slices.Contains(networkIds, nwId) {
+			networkIds = append(networkIds, nwId)
+		}
+	}
+	p.projectMu.RUnlock()
+	sort.Strings(networkIds)
+
+	metricsTracker := p.upstreamsRegistry.GetMetricsTracker()
+	ph := &ProjectHealthStatus{
+		ProjectId: p.Config.Id,
+		Healthy:   true,
+		Networks:  []*NetworkHealthStatus{},
+	}
+	for _, nwId := range networkIds {
+		nh := &NetworkHealthStatus{
+			NetworkId:           nwId,
+			MinHealthyUpstreams: minHealthy,
+			Upstreams:           []*UpstreamHealthStatus{},
+		}
+
+		p.projectMu.RLock()
+		_, initialized := p.Networks[nwId]
+		p.projectMu.RUnlock()
+
+		if !initialized {
+			nh.Reason = "network is not initialized yet"
+		} else {
+			for _, ups := range p.upstreamsRegistry.GetNetworkUpstreams(nwId) {
+				uh := evaluateUpstreamHealth(metricsTracker, ups, nwId, maxErrorRate)
+				if uh.Healthy {
+					nh.HealthyUpstreams++
+				}
+				nh.Upstreams = append(nh.Upstreams, uh)
+			}
+			if nh.HealthyUpstreams >= minHealthy {
+				nh.Healthy = true
+			} else {
+				nh.Reason = fmt.Sprintf("%d healthy upstreams is below minimum of %d", nh.HealthyUpstreams, minHealthy)
+			}
+		}
+
+		if !nh.Healthy {
+			ph.Healthy = false
+		}
+		ph.Networks = append(ph.Networks, nh)
+	}
+
+	return ph
+}
+
+func evaluateUpstreamHealth(metricsTracker *health.Tracker, ups *upstream.Upstream, networkId string, maxErrorRate float64) *UpstreamHealthStatus {
+	cfg := ups.Config()
+	mts := metricsTracker.GetUpstreamMethodMetrics(cfg.Id, networkId, "*")
+	uh := &UpstreamHealthStatus{
+		Id:        cfg.Id,
+		ErrorRate: mts.ErrorRate(),
+	}
+
+	if metricsTracker.IsCordoned(cfg.Id, networkId, "*") {
+		reason, _ := mts.CordonedReason.Load().(string)
+		uh.Reason = fmt.Sprintf("cordoned: %s", reason)
+		return uh
+	}
+	if ups.EvmSyncingState() == common.EvmSyncingStateSyncing {
+		uh.Reason = "upstream is syncing"
+		return uh
+	}
+	// With maxErrorRate of 0 any error makes the upstream unhealthy, but no errors at all does not
+	if mts.RequestsTotal.Load() > 0 && uh.ErrorRate > 0 && uh.ErrorRate >= maxErrorRate {
+		uh.Reason = fmt.Sprintf("error rate %.2f is above threshold %.2f", uh.ErrorRate, maxErrorRate)
+		return uh
+	}
+
+	uh.Healthy = true
+	return uh
+}
@@ -0,0 +1,99 @@
+package erpc
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+)
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// writeAccessLog writes the access log record of a single json-rpc request given its final response (or error).
+// For batch requests responseBytes is the size of the item within the batch response.
+func (s *HttpServer) writeAccessLog(
+	startedAt time.Time,
+	projectId string,
+	networkId string,
+	nq *common.NormalizedRequest,
+	res interface{},
+	responseBytes int64,
+	isBatch bool,
+) {
+	rec := &telemetry.AccessLogRecord{
+		ProjectId:     projectId,
+		NetworkId:     networkId,
+		Status:        http.StatusOK,
+		Duration:      time.Since(startedAt),
+		ResponseBytes: responseBytes,
+		Batch:         isBatch,
+	}
+
+	if nq != nil {
+		rec.Method, _ = nq.Method()
+		rec.RequestBytes = len(nq.Body())
+		rec.Upstreams = nq.UpstreamsTried()
+		if user := nq.User(); user != nil {
+			rec.ConsumerId = user.Id
+		}
+		if s.accessLog.IncludeParams() {
+			if jrr, _ := nq.JsonRpcRequest(); jrr != nil {
+				jrr.RLock()
+				rec.Params = jrr.Params
+				jrr.RUnlock()
+			}
+		}
+	}
+
+	var cause error
+	switch v := res.(type) {
+	case *common.NormalizedResponse:
+		rec.Attempts = v.Attempts()
+		rec.Retries = v.Retries()
+		rec.Hedges = v.Hedges()
+		rec.CacheHit = v.FromCache()
+	case *HttpJsonRpcErrorResponse:
+		cause = v.Cause
+	case error:
+		cause = v
+	}
+
+	if cause != nil {
+		rec.Status = decideErrorStatusCode(cause)
+		rec.ErrorCode = accessLogErrorCode(cause)
+		var ue *common.ErrUpstreamsExhausted
+		if errors.As(cause, &ue) {
+			rec.Attempts = ue.Attempts()
+			rec.Retries = ue.Retries()
+			rec.Hedges = ue.Hedges()
+		}
+	}
+
+	s.accessLog.Log(rec)
+}
+
+// accessLogErrorCode returns the code of the error that caused the failure, skipping the json-rpc exception wrapper.
+func accessLogErrorCode(err error) string {
+	se, ok := err.(common.StandardError)
+	if !ok {
+		return "ErrUnknown"
+	}
+	if se.Base().Code == common.ErrCodeJsonRpcExceptionInternal {
+		if inner, ok := se.GetCause().(common.StandardError); ok {
+			return string(inner.Base().Code)
+		}
+	}
+	return string(se.Base().Code)
+}
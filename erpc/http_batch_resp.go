@@ -1,6 +1,7 @@
 package erpc
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 
@@ -9,7 +10,9 @@ import (
 
 // BatchResponseWriter efficiently writes multiple responses without buffering
 type BatchResponseWriter struct {
-	responses []interface{}
+	responses  []interface{}
+	debugInfos []*ResponseDebugInfo
+	itemSizes  []int64
 }
 
 func NewBatchResponseWriter(responses []interface{}) *BatchResponseWriter {
@@ -18,7 +21,23 @@ func NewBatchResponseWriter(responses []interface{}) *BatchResponseWriter {
 	}
 }
 
+// WithDebugInfos adds a "debug" field to each item that has a non-nil debug info at the same index.
+func (b *BatchResponseWriter) WithDebugInfos(debugInfos []*ResponseDebugInfo) *BatchResponseWriter {
+	b.debugInfos = debugInfos
+	return b
+}
+
+// ItemSize returns the number of bytes written for the item at the given index, excluding separators.
+func (b *BatchResponseWriter) ItemSize(i int) int64 {
+	if i < len(b.itemSizes) {
+		return b.itemSizes[i]
+	}
+	return 0
+}
+
 func (b *BatchResponseWriter) WriteTo(w io.Writer) (n int64, err error) {
+	b.itemSizes = make([]int64, len(b.responses))
+
 	// Write opening bracket
 	nn, err := w.Write([]byte{'['})
 	if err != nil {
@@ -37,6 +56,15 @@ func (b *BatchResponseWriter) WriteTo(w io.Writer) (n int64, err error) {
 		}
 
 		var written int64
+		if i < len(b.debugInfos) && b.debugInfos[i] != nil {
+			written, err = writeWithDebugInfo(w, resp, b.debugInfos[i])
+			b.itemSizes[i] = written
+			if err != nil {
+				return n + written, err
+			}
+			n += written
+			continue
+		}
 		switch v := resp.(type) {
 		case *common.NormalizedResponse:
 			written, err = v.WriteTo(w)
@@ -64,6 +92,7 @@ func (b *BatchResponseWriter) WriteTo(w io.Writer) (n int64, err error) {
 			wn, err = w.Write(buf)
 			written = int64(wn)
 		}
+		b.itemSizes[i] = written
 		if err != nil {
 			return n + written, err
 		}
@@ -128,3 +157,27 @@ func writeJsonRpcError(w io.Writer, resp *HttpJsonRpcErrorResponse) (n int64, er
 	nn, err = w.Write([]byte{'}'})
 	return n + int64(nn), err
 }
+
+// writeWithDebugInfo buffers a single item of the batch to append the debug field before its closing brace.
+func writeWithDebugInfo(w io.Writer, resp interface{}, info *ResponseDebugInfo) (int64, error) {
+	var buf bytes.Buffer
+	if _, err := NewBatchResponseWriter([]interface{}{resp}).WriteTo(&buf); err != nil {
+		return 0, err
+	}
+	// Strip the surrounding brackets of the single-item batch and the closing brace of the item
+	item := bytes.TrimSpace(buf.Bytes())
+	item = bytes.TrimSpace(item[1 : len(item)-1])
+	if len(item) == 0 || item[len(item)-1] != '}' {
+		nn, err := w.Write(item)
+		return int64(nn), err
+	}
+	debug, err := common.SonicCfg.Marshal(info)
+	if err != nil {
+		return 0, err
+	}
+	item = append(item[:len(item)-1], []byte(`,"debug":`)...)
+	item = append(item, debug...)
+	item = append(item, '}')
+	nn, err := w.Write(item)
+	return int64(nn), err
+}
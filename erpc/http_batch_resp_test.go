@@ -0,0 +1,72 @@
+package erpc
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseDebugInfo(t *testing.T) {
+	newResponse := func(t *testing.T, id int) *common.NormalizedResponse {
+		jrr, err := common.NewJsonRpcResponse(id, "0x1", nil)
+		require.NoError(t, err)
+		return common.NewNormalizedResponse().
+			WithJsonRpcResponse(jrr).
+			WithFromCache(true).
+			SetAttempts(2).
+			SetRetries(1)
+	}
+
+	t.Run("SetsDebugHeadersForSingleResponse", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setResponseHeaders(newResponse(t, 1), w, 1500*time.Millisecond)
+
+		assert.Equal(t, "HIT", w.Header().Get("X-ERPC-Cache"))
+		assert.Equal(t, "2", w.Header().Get("X-ERPC-Attempts"))
+		assert.Equal(t, "1", w.Header().Get("X-ERPC-Retries"))
+		assert.Equal(t, "0", w.Header().Get("X-ERPC-Hedges"))
+		assert.Equal(t, "1500", w.Header().Get("X-ERPC-Duration"))
+	})
+
+	t.Run("AddsDebugFieldOnlyToRequestedBatchItems", func(t *testing.T) {
+		r1 := newResponse(t, 1)
+		r2 := newResponse(t, 2)
+		var buf bytes.Buffer
+		_, err := NewBatchResponseWriter([]interface{}{r1, r2}).
+			WithDebugInfos([]*ResponseDebugInfo{newResponseDebugInfo(r1, 20*time.Millisecond), nil}).
+			WriteTo(&buf)
+		require.NoError(t, err)
+
+		var items []map[string]interface{}
+		require.NoError(t, common.SonicCfg.Unmarshal(buf.Bytes(), &items))
+		require.Len(t, items, 2)
+		assert.Equal(t, map[string]interface{}{
+			"cache":      "HIT",
+			"attempts":   float64(2),
+			"retries":    float64(1),
+			"hedges":     float64(0),
+			"durationMs": float64(20),
+		}, items[0]["debug"])
+		assert.Equal(t, "0x1", items[0]["result"])
+		assert.NotContains(t, items[1], "debug")
+	})
+	t.Run("ReportsSizeOfEachBatchItem", func(t *testing.T) {
+		bw := NewBatchResponseWriter([]interface{}{newResponse(t, 1), newResponse(t, 2)})
+		var buf bytes.Buffer
+		n, err := bw.WriteTo(&buf)
+		require.NoError(t, err)
+
+		var items []interface{}
+		require.NoError(t, common.SonicCfg.Unmarshal(buf.Bytes(), &items))
+		assert.Greater(t, bw.ItemSize(0), int64(0))
+		assert.Greater(t, bw.ItemSize(1), int64(0))
+		// Items plus brackets and the comma separator make up the whole batch
+		assert.Equal(t, n, bw.ItemSize(0)+bw.ItemSize(1)+3)
+		assert.Zero(t, bw.ItemSize(2))
+	})
+}
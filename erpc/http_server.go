@@ -22,6 +22,7 @@ import (
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -34,6 +35,8 @@ type HttpServer struct {
 	server *http.Server
 	erpc   *ERPC
 	logger *zerolog.Logger
+
+	accessLog *telemetry.AccessLogger
 }
 
 func NewHttpServer(ctx context.Context, logger *zerolog.Logger, cfg *common.ServerConfig, admin *common.AdminConfig, erpc *ERPC) *HttpServer {
@@ -67,6 +70,13 @@ func NewHttpServer(ctx context.Context, logger *zerolog.Logger, cfg *common.Serv
 		logger: logger,
 	}
 
+	if cfg.AccessLog != nil && cfg.AccessLog.Enabled {
+		srv.accessLog, err = telemetry.NewAccessLogger(cfg.AccessLog)
+		if err != nil {
+			logger.Error().Err(err).Msgf("failed to initialize access log, requests will not be logged")
+		}
+	}
+
 	h := srv.createRequestHandler()
 	if cfg.EnableGzip != nil && *cfg.EnableGzip {
 		h = gzipHandler(h)
@@ -87,14 +97,32 @@ func NewHttpServer(ctx context.Context, logger *zerolog.Logger, cfg *common.Serv
 		} else {
 			logger.Info().Msg("http server stopped")
 		}
+		if srv.accessLog != nil {
+			if err := srv.accessLog.Close(); err != nil {
+				logger.Warn().Err(err).Msg("failed to close access log")
+			}
+		}
 	}()
 
 	return srv
 }
 
+func (s *HttpServer) debugHeadersEnabled() bool {
+	return s.config.DebugHeaders == nil || *s.config.DebugHeaders
+}
+
 func (s *HttpServer) createRequestHandler() http.Handler {
 	handleRequest := func(r *http.Request, w http.ResponseWriter, writeFatalError func(statusCode int, body error)) {
 		startedAt := time.Now()
+		traceCtx, span := telemetry.StartSpan(
+			telemetry.Extract(r.Context(), r.Header),
+			telemetry.SpanKindServer,
+			"erpc.http.request",
+			telemetry.String("http.method", r.Method),
+			telemetry.String("url.path", r.URL.Path),
+		)
+		defer span.End()
+		r = r.WithContext(traceCtx)
 		encoder := common.SonicCfg.NewEncoder(w)
 		encoder.SetEscapeHTML(false)
 
@@ -127,6 +155,19 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 
 		w.Header().Set("Content-Type", "application/json")
 
+		if siweProjectId, ok := parseSiweNoncePath(r, projectId); ok {
+			s.handleSiweNonce(w, r, &startedAt, siweProjectId, encoder, writeFatalError)
+			return
+		}
+
+		if isLiveness, isReadiness, probeProjectId := parseHealthProbePath(r, projectId); isLiveness {
+			s.handleLiveness(w)
+			return
+		} else if isReadiness {
+			s.handleReadiness(w, r, &startedAt, probeProjectId, encoder, writeFatalError)
+			return
+		}
+
 		projectId, architecture, chainId, isAdmin, isHealthCheck, err = s.parseUrlPath(r, projectId, architecture, chainId)
 		if err != nil {
 			handleErrorResponse(s.logger, &startedAt, nil, err, w, encoder, writeFatalError)
@@ -212,10 +253,14 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 		}
 
 		responses := make([]interface{}, len(requests))
+		normalizedRequests := make([]*common.NormalizedRequest, len(requests))
+		durations := make([]time.Duration, len(requests))
+		networkIds := make([]string, len(requests))
 		var wg sync.WaitGroup
 
 		headers := r.Header
 		queryArgs := r.URL.Query()
+		clientIP := clientIPFromRequest(r)
 
 		for i, reqBody := range requests {
 			wg.Add(1)
@@ -229,11 +274,20 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 				}()
 
 				defer wg.Done()
+				defer func() {
+					durations[index] = time.Since(startedAt)
+				}()
 
-				requestCtx := r.Context()
+				requestCtx, reqSpan := telemetry.StartSpan(r.Context(), telemetry.SpanKindInternal, "erpc.request")
+				defer reqSpan.End()
 
 				nq := common.NewNormalizedRequest(rawReq)
+				normalizedRequests[index] = nq
 				nq.ApplyDirectivesFromHttp(headers, queryArgs)
+				nq.SetClientInfo(&common.ClientInfo{
+					IP:      clientIP,
+					Headers: headers,
+				})
 
 				if err := nq.Validate(); err != nil {
 					responses[index] = processErrorBody(&lg, &startedAt, nq, err)
@@ -242,32 +296,65 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 
 				m, _ := nq.Method()
 				rlg := lg.With().Str("method", m).Logger()
+				reqSpan.SetAttributes(telemetry.String("rpc.method", m))
 
 				rlg.Trace().Interface("directives", nq.Directives()).Msgf("applied request directives")
 
+				var networkId string
+
+				// Network is resolved before authentication so that consumer-specific network restrictions can be enforced
+				if !isAdmin {
+					if architecture == "" || chainId == "" {
+						var req map[string]interface{}
+						if err := common.SonicCfg.Unmarshal(rawReq, &req); err != nil {
+							responses[index] = processErrorBody(&rlg, &startedAt, nq, common.NewErrInvalidRequest(err))
+							return
+						}
+						if networkIdFromBody, ok := req["networkId"].(string); ok {
+							networkId = networkIdFromBody
+							parts := strings.Split(networkId, ":")
+							if len(parts) == 2 {
+								architecture = parts[0]
+								chainId = parts[1]
+							}
+						}
+					} else {
+						networkId = fmt.Sprintf("%s:%s", architecture, chainId)
+					}
+
+					if architecture == "" || chainId == "" {
+						responses[index] = processErrorBody(&rlg, &startedAt, nq, common.NewErrInvalidRequest(fmt.Errorf(
+							"architecture and chain must be provided in URL (for example /<project>/evm/42161) or in request body (for example \"networkId\":\"evm:42161\") or configureed via domain aliasing",
+						)))
+						return
+					}
+				}
+
 				var ap *auth.AuthPayload
 				var err error
 
 				if project != nil {
-					ap, err = auth.NewPayloadFromHttp(project.Config.Id, nq, headers, queryArgs)
+					ap, err = auth.NewPayloadFromHttp(project.Config.Id, networkId, nq, headers, queryArgs, r.TLS)
 				} else if isAdmin {
-					ap, err = auth.NewPayloadFromHttp("admin", nq, headers, queryArgs)
+					ap, err = auth.NewPayloadFromHttp("admin", "", nq, headers, queryArgs, r.TLS)
 				}
 				if err != nil {
 					responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
 					return
 				}
 
+				authCtx, authSpan := telemetry.StartSpan(requestCtx, telemetry.SpanKindInternal, "erpc.auth")
 				if isAdmin {
-					if err := s.erpc.AdminAuthenticate(requestCtx, nq, ap); err != nil {
-						responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
-						return
-					}
+					err = s.erpc.AdminAuthenticate(authCtx, nq, ap)
 				} else {
-					if err := project.AuthenticateConsumer(requestCtx, nq, ap); err != nil {
-						responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
-						return
-					}
+					err = project.AuthenticateConsumer(authCtx, nq, ap)
+				}
+				authSpan.RecordError(err)
+				authSpan.End()
+				if err != nil {
+					reqSpan.RecordError(err)
+					responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
+					return
 				}
 
 				if isAdmin {
@@ -293,42 +380,18 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 					}
 				}
 
-				var networkId string
-
-				if architecture == "" || chainId == "" {
-					var req map[string]interface{}
-					if err := common.SonicCfg.Unmarshal(rawReq, &req); err != nil {
-						responses[index] = processErrorBody(&rlg, &startedAt, nq, common.NewErrInvalidRequest(err))
-						return
-					}
-					if networkIdFromBody, ok := req["networkId"].(string); ok {
-						networkId = networkIdFromBody
-						parts := strings.Split(networkId, ":")
-						if len(parts) == 2 {
-							architecture = parts[0]
-							chainId = parts[1]
-						}
-					}
-				} else {
-					networkId = fmt.Sprintf("%s:%s", architecture, chainId)
-				}
-
-				if architecture == "" || chainId == "" {
-					responses[index] = processErrorBody(&rlg, &startedAt, nq, common.NewErrInvalidRequest(fmt.Errorf(
-						"architecture and chain must be provided in URL (for example /<project>/evm/42161) or in request body (for example \"networkId\":\"evm:42161\") or configureed via domain aliasing",
-					)))
-					return
-				}
-
 				nw, err := project.GetNetwork(s.appCtx, networkId)
 				if err != nil {
 					responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
 					return
 				}
 				nq.SetNetwork(nw)
+				networkIds[index] = networkId
 
+				reqSpan.SetAttributes(telemetry.String("erpc.project", project.Config.Id), telemetry.String("erpc.network", networkId))
 				resp, err := project.Forward(requestCtx, networkId, nq)
 				if err != nil {
+					reqSpan.RecordError(err)
 					responses[index] = processErrorBody(&rlg, &startedAt, nq, err)
 					return
 				}
@@ -355,8 +418,29 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 		if isBatch {
 			w.WriteHeader(http.StatusOK)
 			bw := NewBatchResponseWriter(responses)
+			if s.debugHeadersEnabled() {
+				debugInfos := make([]*ResponseDebugInfo, len(responses))
+				for i, resp := range responses {
+					if nq := normalizedRequests[i]; nq != nil && nq.Directives() != nil && nq.Directives().Debug {
+						debugInfos[i] = newResponseDebugInfo(resp, durations[i])
+					}
+				}
+				bw.WithDebugInfos(debugInfos)
+			}
 			_, err = bw.WriteTo(w)
 
+			if !isAdmin {
+				for i, resp := range responses {
+					if s.accessLog != nil {
+						s.writeAccessLog(startedAt, projectId, networkIds[i], normalizedRequests[i], resp, bw.ItemSize(i), true)
+					}
+					// Network id is only set for requests that were forwarded to the project
+					if networkIds[i] != "" {
+						project.usageMeter.Record(normalizedRequests[i], resp, bw.ItemSize(i))
+					}
+				}
+			}
+
 			for _, resp := range responses {
 				if r, ok := resp.(*common.NormalizedResponse); ok {
 					r.Release()
@@ -370,17 +454,29 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 			}
 		} else {
 			res := responses[0]
-			setResponseHeaders(res, w)
+			if s.debugHeadersEnabled() {
+				setResponseHeaders(res, w, time.Since(startedAt))
+			}
 			setResponseStatusCode(res, w)
 
+			cw := &countingWriter{w: w}
 			switch v := res.(type) {
 			case *common.NormalizedResponse:
-				_, err = v.WriteTo(w)
-				v.Release()
+				_, err = v.WriteTo(cw)
 			case *HttpJsonRpcErrorResponse:
-				_, err = writeJsonRpcError(w, v)
+				_, err = writeJsonRpcError(cw, v)
 			default:
-				err = common.SonicCfg.NewEncoder(w).Encode(res)
+				err = common.SonicCfg.NewEncoder(cw).Encode(res)
+			}
+
+			if s.accessLog != nil && !isAdmin {
+				s.writeAccessLog(startedAt, projectId, networkIds[0], normalizedRequests[0], res, cw.n, false)
+			}
+			if !isAdmin && networkIds[0] != "" {
+				project.usageMeter.Record(normalizedRequests[0], res, cw.n)
+			}
+			if v, ok := res.(*common.NormalizedResponse); ok {
+				v.Release()
 			}
 
 			if err != nil {
@@ -572,6 +668,21 @@ func (s *HttpServer) parseUrlPath(
 	return projectId, architecture, chainId, isAdmin, isHealthCheck, nil
 }
 
+// clientIPFromRequest returns the original client IP, based on the first X-Forwarded-For entry when behind a proxy.
+func clientIPFromRequest(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		first := strings.TrimSpace(strings.Split(xff, ",")[0])
+		if ip := net.ParseIP(first); ip != nil {
+			return ip.String()
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func (s *HttpServer) handleCORS(w http.ResponseWriter, r *http.Request, corsConfig *common.CORSConfig) bool {
 	origin := r.Header.Get("Origin")
 	if origin == "" {
@@ -633,40 +744,75 @@ func (s *HttpServer) handleCORS(w http.ResponseWriter, r *http.Request, corsConf
 	return true
 }
 
-func setResponseHeaders(res interface{}, w http.ResponseWriter) {
-	var rm common.ResponseMetadata
-	var ok bool
-	rm, ok = res.(common.ResponseMetadata)
-	if !ok {
-		if jrsp, ok := res.(map[string]interface{}); ok {
-			if err, ok := jrsp["cause"].(error); ok {
-				if uer, ok := err.(*common.ErrUpstreamsExhausted); ok {
-					rm = uer
-				} else if uer, ok := err.(*common.ErrUpstreamRequest); ok {
-					rm = uer
-				}
-			}
-		} else if hjrsp, ok := res.(*HttpJsonRpcErrorResponse); ok {
-			if err := hjrsp.Cause; err != nil {
-				if uer, ok := err.(*common.ErrUpstreamRequest); ok {
-					rm = uer
-				}
-			}
+// responseMetadataOf returns routing metadata (upstream, cache, attempts, etc.) of a response or error, if any.
+func responseMetadataOf(res interface{}) common.ResponseMetadata {
+	var cause error
+	switch v := res.(type) {
+	case *common.NormalizedResponse:
+		if v.IsObjectNull() {
+			return nil
 		}
+		return v
+	case map[string]interface{}:
+		cause, _ = v["cause"].(error)
+	case *HttpJsonRpcErrorResponse:
+		cause = v.Cause
+	case error:
+		cause = v
 	}
-	if ok && rm != nil && !rm.IsObjectNull() {
+	if cause == nil {
+		return nil
+	}
+	var uee *common.ErrUpstreamsExhausted
+	if errors.As(cause, &uee) {
+		return uee
+	}
+	var ure *common.ErrUpstreamRequest
+	if errors.As(cause, &ure) {
+		return ure
+	}
+	return nil
+}
+
+// ResponseDebugInfo is added as "debug" field of each item of a batch response when requested via the debug directive,
+// carrying the same information as debug headers of single responses.
+type ResponseDebugInfo struct {
+	Upstream   string `json:"upstream,omitempty"`
+	Cache      string `json:"cache,omitempty"`
+	Attempts   int    `json:"attempts"`
+	Retries    int    `json:"retries"`
+	Hedges     int    `json:"hedges"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+func newResponseDebugInfo(res interface{}, duration time.Duration) *ResponseDebugInfo {
+	info := &ResponseDebugInfo{DurationMs: duration.Milliseconds()}
+	if rm := responseMetadataOf(res); rm != nil {
 		if rm.FromCache() {
-			w.Header().Set("X-ERPC-Cache", "HIT")
+			info.Cache = "HIT"
 		} else {
-			w.Header().Set("X-ERPC-Cache", "MISS")
+			info.Cache = "MISS"
 		}
-		if rm.UpstreamId() != "" {
-			w.Header().Set("X-ERPC-Upstream", rm.UpstreamId())
+		info.Upstream = rm.UpstreamId()
+		info.Attempts = rm.Attempts()
+		info.Retries = rm.Retries()
+		info.Hedges = rm.Hedges()
+	}
+	return info
+}
+
+func setResponseHeaders(res interface{}, w http.ResponseWriter, duration time.Duration) {
+	info := newResponseDebugInfo(res, duration)
+	if info.Cache != "" {
+		w.Header().Set("X-ERPC-Cache", info.Cache)
+		if info.Upstream != "" {
+			w.Header().Set("X-ERPC-Upstream", info.Upstream)
 		}
-		w.Header().Set("X-ERPC-Attempts", fmt.Sprintf("%d", rm.Attempts()))
-		w.Header().Set("X-ERPC-Retries", fmt.Sprintf("%d", rm.Retries()))
-		w.Header().Set("X-ERPC-Hedges", fmt.Sprintf("%d", rm.Hedges()))
+		w.Header().Set("X-ERPC-Attempts", fmt.Sprintf("%d", info.Attempts))
+		w.Header().Set("X-ERPC-Retries", fmt.Sprintf("%d", info.Retries))
+		w.Header().Set("X-ERPC-Hedges", fmt.Sprintf("%d", info.Hedges))
 	}
+	w.Header().Set("X-ERPC-Duration", fmt.Sprintf("%d", info.DurationMs))
 }
 
 func setResponseStatusCode(respOrErr interface{}, w http.ResponseWriter) {
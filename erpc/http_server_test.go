@@ -3325,6 +3325,109 @@ func TestHttpServer_HandleHealthCheck(t *testing.T) {
 	}
 }
 
+func TestHttpServer_HandleReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		project    *PreparedProject
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Ready without networks",
+			project: &PreparedProject{
+				Config:            &common.ProjectConfig{Id: "test"},
+				Networks:          map[string]*Network{},
+				projectMu:         &sync.RWMutex{},
+				upstreamsRegistry: upstream.NewUpstreamsRegistry(context.TODO(), &zerolog.Logger{}, "", nil, nil, nil, nil, 0*time.Second),
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"ready":true`,
+		},
+		{
+			name: "Ready when bootstrapped static network allows zero healthy upstreams",
+			project: &PreparedProject{
+				Config: &common.ProjectConfig{
+					Id: "test",
+					Networks: []*common.NetworkConfig{
+						{Architecture: common.ArchitectureEvm, Evm: &common.EvmNetworkConfig{ChainId: 1}},
+					},
+					HealthCheck: &common.HealthCheckConfig{MinHealthyUpstreams: util.IntPtr(0)},
+				},
+				// Bootstrap registers static networks via GetNetwork
+				Networks:          map[string]*Network{"evm:1": {}},
+				projectMu:         &sync.RWMutex{},
+				upstreamsRegistry: upstream.NewUpstreamsRegistry(context.TODO(), &zerolog.Logger{}, "", nil, nil, nil, nil, 0*time.Second),
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"ready":true`,
+		},
+		{
+			name: "Not ready when static network is not initialized",
+			project: &PreparedProject{
+				Config: &common.ProjectConfig{
+					Id: "test",
+					Networks: []*common.NetworkConfig{
+						{Architecture: common.ArchitectureEvm, Evm: &common.EvmNetworkConfig{ChainId: 1}},
+					},
+				},
+				Networks:          map[string]*Network{},
+				projectMu:         &sync.RWMutex{},
+				upstreamsRegistry: upstream.NewUpstreamsRegistry(context.TODO(), &zerolog.Logger{}, "", nil, nil, nil, nil, 0*time.Second),
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `network is not initialized yet`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &HttpServer{
+				logger: &zerolog.Logger{},
+				erpc: &ERPC{
+					projectsRegistry: &ProjectsRegistry{
+						preparedProjects: map[string]*PreparedProject{"test": tt.project},
+					},
+				},
+			}
+			w := httptest.NewRecorder()
+			startTime := time.Now()
+
+			encoder := common.SonicCfg.NewEncoder(w)
+			r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			s.handleReadiness(w, r, &startTime, "test", encoder, func(code int, err error) {
+				w.WriteHeader(code)
+				encoder.Encode(map[string]string{"error": err.Error()})
+			})
+
+			resp := w.Result()
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Contains(t, string(body), tt.wantBody)
+		})
+	}
+
+	t.Run("ParseHealthProbePath", func(t *testing.T) {
+		isLive, isReady, prj := parseHealthProbePath(httptest.NewRequest(http.MethodGet, "/livez", nil), "")
+		assert.True(t, isLive)
+		assert.False(t, isReady)
+		assert.Empty(t, prj)
+
+		isLive, isReady, prj = parseHealthProbePath(httptest.NewRequest(http.MethodGet, "/main/readyz", nil), "")
+		assert.False(t, isLive)
+		assert.True(t, isReady)
+		assert.Equal(t, "main", prj)
+
+		_, isReady, prj = parseHealthProbePath(httptest.NewRequest(http.MethodGet, "/readyz", nil), "aliased")
+		assert.True(t, isReady)
+		assert.Equal(t, "aliased", prj)
+
+		isLive, isReady, _ = parseHealthProbePath(httptest.NewRequest(http.MethodPost, "/readyz", nil), "")
+		assert.False(t, isLive)
+		assert.False(t, isReady)
+	})
+}
+
 func createServerTestFixtures(cfg *common.Config, t *testing.T) (
 	func(body string, headers map[string]string, queryParams map[string]string) (int, string),
 	func(host string) (int, map[string]string, string),
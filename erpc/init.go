@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
@@ -25,29 +26,12 @@ func Init(
 	//
 	logger.Info().Msg("loading eRPC configuration")
 	configPath := ""
-	possibleConfigs := []string{"./erpc.js", "./erpc.ts", "./erpc.yaml", "./erpc.yml"}
-
 	if len(args) > 1 {
 		configPath = args[1]
-	} else {
-		// Check for erpc.ts or erpc.yaml
-		for _, path := range possibleConfigs {
-			if _, err := fs.Stat(path); err == nil {
-				configPath = path
-				break
-			}
-		}
 	}
-
-	if configPath == "" {
-		return fmt.Errorf("no valid configuration file found in %v", possibleConfigs)
-	}
-
-	logger.Info().Msgf("resolved configuration file to: %s", configPath)
-	cfg, err := common.LoadConfig(fs, configPath)
-
+	cfg, err := loadConfig(&logger, fs, configPath)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration from %s: %v", configPath, err)
+		return err
 	}
 	level, err := zerolog.ParseLevel(cfg.LogLevel)
 	if err != nil {
@@ -66,6 +50,15 @@ func Init(
 		}
 	}
 
+	if cfg.Tracing != nil && cfg.Tracing.Enabled {
+		logger.Info().Str("endpoint", cfg.Tracing.Endpoint).Msg("initializing tracing exporter")
+		tracer, err := telemetry.NewTracer(appCtx, &logger, cfg.Tracing)
+		if err != nil {
+			return fmt.Errorf("failed to initialize tracing: %v", err)
+		}
+		telemetry.SetTracer(tracer)
+	}
+
 	//
 	// 2) Initialize eRPC
 	//
@@ -134,3 +127,32 @@ func Init(
 
 	return nil
 }
+
+// loadConfig loads the given configuration file, or the first of erpc.js, erpc.ts, erpc.yaml and erpc.yml
+// found in the working directory when no path is given.
+func loadConfig(logger *zerolog.Logger, fs afero.Fs, configPath string) (*common.Config, error) {
+	possibleConfigs := []string{"./erpc.js", "./erpc.ts", "./erpc.yaml", "./erpc.yml"}
+
+	if configPath == "" {
+		// Check for erpc.ts or erpc.yaml
+		for _, path := range possibleConfigs {
+			if _, err := fs.Stat(path); err == nil {
+				configPath = path
+				break
+			}
+		}
+	}
+
+	if configPath == "" {
+		return nil, fmt.Errorf("no valid configuration file found in %v", possibleConfigs)
+	}
+
+	logger.Info().Msgf("resolved configuration file to: %s", configPath)
+	cfg, err := common.LoadConfig(fs, configPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration from %s: %v", configPath, err)
+	}
+
+	return cfg, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/upstream"
 	"github.com/failsafe-go/failsafe-go"
 	"github.com/rs/zerolog"
@@ -25,13 +26,14 @@ type Network struct {
 	cfg                      *common.NetworkConfig
 	inFlightRequests         *sync.Map
 	evmStatePollers          map[string]*upstream.EvmStatePoller
-	timeoutDuration          *time.Duration
-	failsafeExecutor         failsafe.Executor[*common.NormalizedResponse]
+	healthProbers            map[string]*upstream.HealthProber
+	failsafeExecutors        []*upstream.FailsafeExecutor
 	rateLimitersRegistry     *upstream.RateLimitersRegistry
 	cacheDal                 common.CacheDAL
 	metricsTracker           *health.Tracker
 	upstreamsRegistry        *upstream.UpstreamsRegistry
 	selectionPolicyEvaluator *PolicyEvaluator
+	stickySessions           *StickySessions
 }
 
 func (n *Network) Bootstrap(ctx context.Context) error {
@@ -82,6 +84,19 @@ func (n *Network) Bootstrap(ctx context.Context) error {
 			return
 		}
 
+		// Initialize active health probers for upstreams that define probes
+		n.healthProbers = make(map[string]*upstream.HealthProber)
+		for _, u := range n.upstreamsRegistry.GetNetworkUpstreams(n.NetworkId) {
+			prober, e := upstream.NewHealthProber(ctx, n.Logger, n, u, n.metricsTracker)
+			if e != nil {
+				err = e
+				return
+			}
+			if prober.Enabled {
+				n.healthProbers[u.Config().Id] = prober
+			}
+		}
+
 		// Initialize policy evaluator if configured
 		if n.cfg.SelectionPolicy != nil {
 			evaluator, e := NewPolicyEvaluator(n.NetworkId, n.Logger, n.cfg.SelectionPolicy, n.upstreamsRegistry, n.metricsTracker)
@@ -164,6 +179,22 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 		}
 		return nil, err
 	}
+	if n.cfg.UpstreamSelection != nil {
+		upsList = n.upstreamsRegistry.SelectUpstreams(n.NetworkId, method, upsList, n.cfg.UpstreamSelection.Mode)
+	}
+
+	// Session affinity does not apply when client explicitly asks for specific upstream(s)
+	var stickyKey string
+	if n.stickySessions != nil && req.Directives().UseUpstream == "" {
+		stickyKey = n.stickySessions.SessionKey(req)
+		if stickyKey != "" {
+			upsList = n.stickySessions.Apply(stickyKey, upsList)
+		}
+	}
+
+	// Fallback upstream groups are only used once previous groups are exhausted or unhealthy
+	var upsTiers map[string]int
+	upsList, upsTiers = n.arrangeByUpstreamGroups(method, upsList)
 
 	// 3) Check if we should handle this method on this network
 	if err := n.shouldHandleMethod(method, upsList); err != nil {
@@ -189,11 +220,13 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 	) (resp *common.NormalizedResponse, err error) {
 		lg.Debug().Msgf("trying to forward request to upstream")
 
-		if err := n.acquireSelectionPolicyPermit(lg, u, req); err != nil {
+		if err := n.acquireSelectionPolicyPermit(ctx, lg, u, req); err != nil {
 			return nil, err
 		}
 
+		attemptStartedAt := time.Now()
 		resp, err = u.Forward(ctx, req, false)
+		captureSessionFromContext(ctx).recordAttempt(u.Config().Id, resp, err, time.Since(attemptStartedAt))
 
 		if !common.IsNull(err) {
 			// If upstream complains that the method is not supported let's dynamically add it ignoreMethods config
@@ -217,13 +250,19 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 	// 5) Actual forwarding logic
 	var execution failsafe.Execution[*common.NormalizedResponse]
 	errorsByUpstream := &sync.Map{}
+	// Upstreams that were already tried (including in-flight ones) by any execution of this request
+	attemptedUpstreams := &sync.Map{}
 
 	ectx := context.WithValue(ctx, common.RequestContextKey, req)
 
 	i := 0
-	resp, execErr := n.failsafeExecutor.
+	fe := upstream.PickFailsafeExecutor(n.failsafeExecutors, method)
+	if fe.RetryBudget != nil {
+		fe.RetryBudget.Deposit()
+	}
+	resp, execErr := fe.Executor.
 		WithContext(ectx).
-		GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+		GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (_ *common.NormalizedResponse, attemptErr error) {
 			req.Lock()
 			execution = exec
 			req.Unlock()
@@ -237,21 +276,35 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 					return nil, ctxErr
 				}
 			}
-			if n.timeoutDuration != nil {
+			if timeout := fe.TimeoutFor(n.latencyQuantile(method, upsList)); timeout != nil {
 				var cancelFn context.CancelFunc
 				ictx, cancelFn = context.WithTimeoutCause(
 					ectx,
-					*n.timeoutDuration,
+					*timeout,
 					// TODO 5ms is a workaround to ensure context carries the timeout deadline (used when calling upstreams),
 					//      but allow the failsafe execution to fail with timeout first for proper error handling.
 					//      Is there a way to do this cleanly? e.g. if failsafe lib works via context rather than Ticker?
-					common.NewErrNetworkRequestTimeout(*n.timeoutDuration+5*time.Millisecond),
+					common.NewErrNetworkRequestTimeout(*timeout+5*time.Millisecond),
 				)
 
 				defer cancelFn()
 			}
 
 			var err error
+			var attemptSpan *telemetry.Span
+			ictx, attemptSpan = telemetry.StartSpan(
+				ictx,
+				telemetry.SpanKindInternal,
+				"erpc.network.attempt",
+				telemetry.Int("erpc.attempts", exec.Attempts()),
+				telemetry.Int("erpc.retries", exec.Retries()),
+				telemetry.Int("erpc.hedges", exec.Hedges()),
+			)
+			defer func() {
+				// Record the error actually returned by this attempt (e.g. upstreams exhausted or hedge cancelled)
+				attemptSpan.RecordError(attemptErr)
+				attemptSpan.End()
+			}()
 
 			// We should try all upstreams at least once, but using "i" we make sure
 			// across different executions of the failsafe we pick up next upstream vs retrying the same upstream.
@@ -277,6 +330,11 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 				if i >= ln {
 					i = 0
 				}
+				if hasPendingLowerTier(u, upsList, upsTiers, attemptedUpstreams) {
+					// Do not use upstreams of a fallback group while upstreams of previous groups are still available.
+					req.Unlock()
+					continue
+				}
 				if prevErr, exists := errorsByUpstream.Load(upsId); exists {
 					pe := prevErr.(error)
 					if !common.IsRetryableTowardsUpstream(pe) || common.IsCapacityIssue(pe) {
@@ -287,6 +345,7 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 						continue
 					}
 				}
+				attemptedUpstreams.Store(upsId, true)
 				req.Unlock()
 
 				var r *common.NormalizedResponse
@@ -312,6 +371,9 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 					if r != nil {
 						r.SetUpstream(u)
 					}
+					if err == nil {
+						n.recordUpstreamGroupServed(method, u, upsTiers)
+					}
 					return r, err
 				} else if err != nil {
 					continue
@@ -387,6 +449,14 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 		}
 	}
 
+	skipCacheWrite := false
+	if resp != nil && req.Directives().VerifyEmpty && emptyVerifiableMethods[method] && resp.IsResultEmptyish() {
+		var confirmed bool
+		resp, confirmed = n.verifyEmptyResponse(ctx, &lg, req, method, resp, upsList)
+		// Empty responses that could not be confirmed might be due to a lagging upstream, so they must not be cached
+		skipCacheWrite = !confirmed
+	}
+
 	if resp != nil {
 		if execution != nil {
 			resp.SetAttempts(execution.Attempts())
@@ -394,11 +464,11 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 			resp.SetHedges(execution.Hedges())
 		}
 
-		if n.cacheDal != nil {
+		if n.cacheDal != nil && !skipCacheWrite {
 			resp.RLock()
 			go (func(resp *common.NormalizedResponse) {
 				defer resp.RUnlock()
-				c, cancel := context.WithTimeoutCause(n.appCtx, 10*time.Second, errors.New("cache driver timeout during set"))
+				c, cancel := context.WithTimeoutCause(telemetry.WithTraceFrom(n.appCtx, ctx), 10*time.Second, errors.New("cache driver timeout during set"))
 				defer cancel()
 				err := n.cacheDal.Set(c, req, resp)
 				if err != nil {
@@ -410,6 +480,11 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 
 	if execErr == nil && resp != nil && !resp.IsObjectNull() {
 		n.enrichStatePoller(method, req, resp)
+		if stickyKey != "" {
+			if upsId := resp.UpstreamId(); upsId != "" {
+				n.stickySessions.Pin(stickyKey, upsId)
+			}
+		}
 	}
 	if mlx != nil {
 		mlx.Close(resp, nil)
@@ -450,11 +525,17 @@ func (n *Network) EvmChainId() (int64, error) {
 	return n.cfg.Evm.ChainId, nil
 }
 
-func (n *Network) acquireSelectionPolicyPermit(lg *zerolog.Logger, ups *upstream.Upstream, req *common.NormalizedRequest) error {
+func (n *Network) acquireSelectionPolicyPermit(ctx context.Context, lg *zerolog.Logger, ups *upstream.Upstream, req *common.NormalizedRequest) (err error) {
 	if n.cfg.SelectionPolicy == nil {
 		return nil
 	}
 
+	_, span := telemetry.StartSpan(ctx, telemetry.SpanKindInternal, "erpc.selection_policy.permit", telemetry.String("erpc.upstream", ups.Config().Id))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	method, err := req.Method()
 	if err != nil {
 		return err
@@ -485,7 +566,10 @@ func (n *Network) handleMultiplexing(ctx context.Context, lg *zerolog.Logger, re
 
 		lg.Debug().Str("hash", mlxHash).Msgf("found identical request initiating multiplexer")
 
+		_, waitSpan := telemetry.StartSpan(ctx, telemetry.SpanKindInternal, "erpc.multiplexer.wait")
 		resp, err := n.waitForMultiplexResult(ctx, inf, req, startTime)
+		waitSpan.RecordError(err)
+		waitSpan.End()
 
 		lg.Trace().Str("hash", mlxHash).Object("response", resp).Err(err).Msgf("multiplexed request result")
 
@@ -538,6 +622,24 @@ func (n *Network) cleanupMultiplexer(mlx *Multiplexer) {
 	n.inFlightRequests.Delete(mlx.hash)
 }
 
+// latencyQuantile returns a function computing the highest observed latency (in seconds) at a given quantile
+// among the upstreams that might serve the method, so that adaptive timeout leaves room for the slowest candidate.
+func (n *Network) latencyQuantile(method string, upsList []*upstream.Upstream) func(q float64) float64 {
+	if n.metricsTracker == nil {
+		return nil
+	}
+	return func(q float64) float64 {
+		var latency float64
+		for _, u := range upsList {
+			l := n.metricsTracker.GetUpstreamMethodMetrics(u.Config().Id, n.NetworkId, method).LatencySecs.Quantile(q)
+			if l > latency {
+				latency = l
+			}
+		}
+		return latency
+	}
+}
+
 func (n *Network) shouldHandleMethod(method string, upsList []*upstream.Upstream) error {
 	if method == "eth_newFilter" ||
 		method == "eth_newBlockFilter" ||
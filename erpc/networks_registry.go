@@ -4,12 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/health"
 	"github.com/erpc/erpc/upstream"
-	"github.com/failsafe-go/failsafe-go"
 	"github.com/rs/zerolog"
 )
 
@@ -47,23 +45,11 @@ func NewNetwork(
 ) (*Network, error) {
 	lg := logger.With().Str("component", "proxy").Str("networkId", nwCfg.NetworkId()).Logger()
 
-	var policyArray []failsafe.Policy[*common.NormalizedResponse]
 	key := fmt.Sprintf("%s/%s", prjId, nwCfg.NetworkId())
-	pls, err := upstream.CreateFailSafePolicies(&lg, common.ScopeNetwork, key, nwCfg.Failsafe)
+	failsafeExecutors, err := upstream.CreateFailSafeExecutors(&lg, common.ScopeNetwork, key, nwCfg.Failsafe, metricsTracker)
 	if err != nil {
 		return nil, err
 	}
-	for _, policy := range pls {
-		policyArray = append(policyArray, policy)
-	}
-	var timeoutDuration *time.Duration
-	if nwCfg.Failsafe != nil && nwCfg.Failsafe.Timeout != nil {
-		d, err := time.ParseDuration(nwCfg.Failsafe.Timeout.Duration)
-		timeoutDuration = &d
-		if err != nil {
-			return nil, err
-		}
-	}
 
 	network := &Network{
 		ProjectId: prjId,
@@ -76,10 +62,16 @@ func NewNetwork(
 		metricsTracker:       metricsTracker,
 		rateLimitersRegistry: rateLimitersRegistry,
 
-		bootstrapOnce:    sync.Once{},
-		inFlightRequests: &sync.Map{},
-		timeoutDuration:  timeoutDuration,
-		failsafeExecutor: failsafe.NewExecutor(policyArray...),
+		bootstrapOnce:     sync.Once{},
+		inFlightRequests:  &sync.Map{},
+		failsafeExecutors: failsafeExecutors,
+	}
+
+	if nwCfg.UpstreamSelection != nil && nwCfg.UpstreamSelection.Sticky != nil {
+		network.stickySessions, err = NewStickySessions(nwCfg.UpstreamSelection.Sticky)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if nwCfg.Architecture == "" {
@@ -24,6 +24,7 @@ import (
 	"github.com/h2non/gock"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -4756,6 +4757,299 @@ func TestNetwork_Forward(t *testing.T) {
 		}
 	})
 
+	t.Run("VerifyEmptyResponseAgainstAnotherUpstream", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+		defer util.AssertNoPendingMocks(t, 0)
+
+		var requestBytes = []byte(`{
+			"jsonrpc": "2.0",
+			"method": "eth_getLogs",
+			"params": [{
+				"address": "0x1234567890abcdef1234567890abcdef12345678",
+				"fromBlock": "0x4",
+				"toBlock": "0x7"
+			}],
+			"id": 1
+		}`)
+
+		// First upstream is missing the data
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Filter(func(request *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(request), "eth_getLogs")
+			}).
+			Reply(200).
+			JSON([]byte(`{"result":[]}`))
+
+		gock.New("http://rpc2.localhost").
+			Post("").
+			Filter(func(request *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(request), "eth_getLogs")
+			}).
+			Reply(200).
+			JSON([]byte(`{"result":[{"logIndex":444,"fromHost":"rpc2"}]}`))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		clr := upstream.NewClientRegistry(&log.Logger)
+		rlr, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{
+			Budgets: []*common.RateLimitBudgetConfig{},
+		}, &log.Logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vndr := vendors.NewVendorsRegistry()
+		mt := health.NewTracker("prjA", 2*time.Second)
+		up1 := &common.UpstreamConfig{
+			Type:     common.UpstreamTypeEvm,
+			Id:       "rpc1",
+			Endpoint: "http://rpc1.localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		}
+		up2 := &common.UpstreamConfig{
+			Type:     common.UpstreamTypeEvm,
+			Id:       "rpc2",
+			Endpoint: "http://rpc2.localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		}
+		upr := upstream.NewUpstreamsRegistry(
+			ctx,
+			&log.Logger,
+			"prjA",
+			[]*common.UpstreamConfig{up1, up2},
+			rlr,
+			vndr, mt, 1*time.Second,
+		)
+		err = upr.Bootstrap(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = upr.PrepareUpstreamsForNetwork(ctx, util.EvmNetworkId(123))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pup1, err := upr.NewUpstream("prjA", up1, &log.Logger, mt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cl1, err := clr.GetOrCreateClient(ctx, pup1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pup1.Client = cl1
+		pup2, err := upr.NewUpstream("prjA", up2, &log.Logger, mt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cl2, err := clr.GetOrCreateClient(ctx, pup2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pup2.Client = cl2
+
+		ntw, err := NewNetwork(
+			&log.Logger,
+			"prjA",
+			&common.NetworkConfig{
+				Architecture: common.ArchitectureEvm,
+				Evm: &common.EvmNetworkConfig{
+					ChainId: 123,
+				},
+				Failsafe: &common.FailsafeConfig{
+					Retry: &common.RetryPolicyConfig{
+						MaxAttempts: 1,
+					},
+				},
+			},
+			rlr,
+			upr,
+			mt,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ntw.Bootstrap(ctx)
+		time.Sleep(100 * time.Millisecond)
+
+		fakeReq := common.NewNormalizedRequest(requestBytes)
+		headers := http.Header{}
+		headers.Set("X-ERPC-Retry-Empty", "false")
+		headers.Set("X-ERPC-Verify-Empty", "true")
+		fakeReq.ApplyDirectivesFromHttp(headers, url.Values{})
+		resp, err := ntw.Forward(ctx, fakeReq)
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+
+		jrr, err := resp.JsonRpcResponse()
+		if err != nil {
+			t.Fatalf("Failed to get JSON-RPC response: %v", err)
+		}
+		fromHost, err := jrr.PeekStringByPath(0, "fromHost")
+		if err != nil || fromHost != "rpc2" {
+			t.Errorf("Expected fromHost to be %q, got %q", "rpc2", fromHost)
+		}
+
+		// Upstream that returned empty while the other one had data is penalized
+		assert.Equal(t, int64(1), mt.GetUpstreamMethodMetrics("rpc1", util.EvmNetworkId(123), "eth_getLogs").ErrorsTotal.Load())
+	})
+
+	t.Run("ForwardUsesFallbackGroupOnceTriedUpstreamsDidNotServe", func(t *testing.T) {
+		var requestBytes = []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x64d340d2470d2ed0ec979b72d79af9cd09fc4eb2b89ae98728d5fb07fd89baf9"]}`)
+		isTxRequest := func(request *http.Request) bool {
+			return strings.Contains(util.SafeReadBody(request), "eth_getTransactionByHash")
+		}
+
+		newGroupedNetwork := func(t *testing.T, ctx context.Context, fsCfg *common.FailsafeConfig) *Network {
+			clr := upstream.NewClientRegistry(&log.Logger)
+			rlr, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{
+				Budgets: []*common.RateLimitBudgetConfig{},
+			}, &log.Logger)
+			require.NoError(t, err)
+			vndr := vendors.NewVendorsRegistry()
+			mt := health.NewTracker("prjA", 2*time.Second)
+			up1 := &common.UpstreamConfig{
+				Type:     common.UpstreamTypeEvm,
+				Id:       "rpc1",
+				Group:    "primary",
+				Endpoint: "http://rpc1.localhost",
+				Evm: &common.EvmUpstreamConfig{
+					ChainId: 123,
+				},
+			}
+			up2 := &common.UpstreamConfig{
+				Type:     common.UpstreamTypeEvm,
+				Id:       "rpc2",
+				Group:    "fallback",
+				Endpoint: "http://rpc2.localhost",
+				Evm: &common.EvmUpstreamConfig{
+					ChainId: 123,
+				},
+			}
+			upr := upstream.NewUpstreamsRegistry(
+				ctx,
+				&log.Logger,
+				"prjA",
+				[]*common.UpstreamConfig{up1, up2},
+				rlr,
+				vndr, mt, 1*time.Second,
+			)
+			require.NoError(t, upr.Bootstrap(ctx))
+			require.NoError(t, upr.PrepareUpstreamsForNetwork(ctx, util.EvmNetworkId(123)))
+			for _, cfg := range []*common.UpstreamConfig{up1, up2} {
+				pup, err := upr.NewUpstream("prjA", cfg, &log.Logger, mt)
+				require.NoError(t, err)
+				cl, err := clr.GetOrCreateClient(ctx, pup)
+				require.NoError(t, err)
+				pup.Client = cl
+			}
+
+			ntw, err := NewNetwork(
+				&log.Logger,
+				"prjA",
+				&common.NetworkConfig{
+					Architecture: common.ArchitectureEvm,
+					Evm: &common.EvmNetworkConfig{
+						ChainId: 123,
+					},
+					Failsafe: fsCfg,
+					UpstreamGroups: []*common.UpstreamGroupConfig{
+						{Name: "primary"},
+						{Name: "fallback"},
+					},
+				},
+				rlr,
+				upr,
+				mt,
+			)
+			require.NoError(t, err)
+			return ntw
+		}
+
+		fromHost := func(t *testing.T, resp *common.NormalizedResponse) string {
+			jrr, err := resp.JsonRpcResponse()
+			require.NoError(t, err)
+			host, err := jrr.PeekStringByPath("fromHost")
+			require.NoError(t, err)
+			return host
+		}
+
+		t.Run("PrimaryReturnsEmpty", func(t *testing.T) {
+			util.ResetGock()
+			defer util.ResetGock()
+			util.SetupMocksForEvmStatePoller()
+			defer util.AssertNoPendingMocks(t, 0)
+
+			gock.New("http://rpc1.localhost").
+				Post("").
+				Filter(isTxRequest).
+				Reply(200).
+				JSON([]byte(`{"result":null}`))
+			gock.New("http://rpc2.localhost").
+				Post("").
+				Filter(isTxRequest).
+				Reply(200).
+				JSON([]byte(`{"result":{"hash":"0x64d340d2470d2ed0ec979b72d79af9cd09fc4eb2b89ae98728d5fb07fd89baf9","fromHost":"rpc2"}}`))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ntw := newGroupedNetwork(t, ctx, &common.FailsafeConfig{
+				Retry: &common.RetryPolicyConfig{
+					MaxAttempts: 2,
+				},
+			})
+
+			fakeReq := common.NewNormalizedRequest(requestBytes)
+			headers := http.Header{}
+			headers.Set("X-ERPC-Retry-Empty", "true")
+			fakeReq.ApplyDirectivesFromHttp(headers, url.Values{})
+			resp, err := ntw.Forward(ctx, fakeReq)
+			require.NoError(t, err)
+			assert.Equal(t, "rpc2", fromHost(t, resp), "retry must go to the fallback group instead of the same primary upstream")
+		})
+
+		t.Run("PrimaryHangs", func(t *testing.T) {
+			util.ResetGock()
+			defer util.ResetGock()
+			util.SetupMocksForEvmStatePoller()
+			defer util.AssertNoPendingMocks(t, 0)
+
+			gock.New("http://rpc1.localhost").
+				Post("").
+				Filter(isTxRequest).
+				Reply(200).
+				JSON([]byte(`{"result":{"hash":"0x64d340d2470d2ed0ec979b72d79af9cd09fc4eb2b89ae98728d5fb07fd89baf9","fromHost":"rpc1"}}`)).
+				Delay(2 * time.Second)
+			gock.New("http://rpc2.localhost").
+				Post("").
+				Filter(isTxRequest).
+				Reply(200).
+				JSON([]byte(`{"result":{"hash":"0x64d340d2470d2ed0ec979b72d79af9cd09fc4eb2b89ae98728d5fb07fd89baf9","fromHost":"rpc2"}}`))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ntw := newGroupedNetwork(t, ctx, &common.FailsafeConfig{
+				Hedge: &common.HedgePolicyConfig{
+					Delay:    "100ms",
+					MaxCount: 1,
+				},
+			})
+
+			startedAt := time.Now()
+			resp, err := ntw.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+			require.NoError(t, err)
+			assert.Equal(t, "rpc2", fromHost(t, resp), "hedge must go to the fallback group while the primary upstream is in-flight")
+			assert.Less(t, time.Since(startedAt), time.Second)
+		})
+	})
+
 	t.Run("ForwardQuicknodeEndpointRateLimitResponse", func(t *testing.T) {
 		util.ResetGock()
 		defer util.ResetGock()
@@ -143,13 +143,14 @@ func (p *PolicyEvaluator) evaluateMethod(method string, upsList []*upstream.Upst
 			"id":     upsId,
 			"config": ups.Config(),
 			"metrics": map[string]interface{}{
-				"errorRate":       metrics.ErrorRate(),
-				"errorsTotal":     metrics.ErrorsTotal.Load(),
-				"requestsTotal":   metrics.RequestsTotal.Load(),
-				"throttledRate":   metrics.ThrottledRate(),
-				"p90LatencySecs":  metrics.LatencySecs.P90(),
-				"blockHeadLag":    metrics.BlockHeadLag.Load(),
-				"finalizationLag": metrics.FinalizationLag.Load(),
+				"errorRate":        metrics.ErrorRate(),
+				"errorsTotal":      metrics.ErrorsTotal.Load(),
+				"requestsTotal":    metrics.RequestsTotal.Load(),
+				"throttledRate":    metrics.ThrottledRate(),
+				"p90LatencySecs":   metrics.LatencySecs.P90(),
+				"blockHeadLag":     metrics.BlockHeadLag.Load(),
+				"finalizationLag":  metrics.FinalizationLag.Load(),
+				"inFlightRequests": metrics.InFlightRequests.Load(),
 			},
 		}
 	}
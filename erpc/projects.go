@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/common"
@@ -28,6 +29,8 @@ type PreparedProject struct {
 	rateLimitersRegistry *upstream.RateLimitersRegistry
 	upstreamsRegistry    *upstream.UpstreamsRegistry
 	evmJsonRpcCache      *EvmJsonRpcCache
+	capturer             *Capturer
+	usageMeter           *UsageMeter
 }
 
 type initOnce struct {
@@ -40,7 +43,7 @@ func (p *PreparedProject) Bootstrap(ctx context.Context) error {
 
 	go func() {
 		for _, nwCfg := range p.Config.Networks {
-			_, err := p.initializeNetwork(ctx, nwCfg.NetworkId())
+			_, err := p.GetNetwork(ctx, nwCfg.NetworkId())
 			if err != nil {
 				p.Logger.Error().Err(err).Msgf("failed to initialize network %s", nwCfg.NetworkId())
 			}
@@ -89,17 +92,29 @@ func (p *PreparedProject) GatherHealthInfo() (*upstream.UpstreamsHealth, error)
 	return p.upstreamsRegistry.GetUpstreamsHealth()
 }
 
+// AuthenticateConsumer verifies the request against project's auth strategies, which also enforces
+// consumer-specific restrictions (allowed networks/methods and rate limit budget, e.g. mapped from JWT claims),
+// then attaches the resolved consumer identity to the request.
 func (p *PreparedProject) AuthenticateConsumer(ctx context.Context, nq *common.NormalizedRequest, ap *auth.AuthPayload) error {
 	if p.consumerAuthRegistry != nil {
-		err := p.consumerAuthRegistry.Authenticate(ctx, nq, ap)
+		user, err := p.consumerAuthRegistry.Authenticate(ctx, nq, ap)
 		if err != nil {
 			return err
 		}
+		nq.SetUser(user)
 	}
 
 	return nil
 }
 
+// IssueSiweNonce issues a nonce for clients that sign in with a SIWE message.
+func (p *PreparedProject) IssueSiweNonce(ctx context.Context) (string, time.Time, error) {
+	if p.consumerAuthRegistry == nil {
+		return "", time.Time{}, common.NewErrInvalidRequest(fmt.Errorf("auth is not configured for project %s", p.Config.Id))
+	}
+	return p.consumerAuthRegistry.IssueSiweNonce(ctx)
+}
+
 func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *common.NormalizedRequest) (*common.NormalizedResponse, error) {
 	// We use app context here so that network lazy loading runs within app context not request, as we want it to continue even if current request is cancelled/timed out
 	network, err := p.GetNetwork(p.appCtx, networkId)
@@ -136,7 +151,9 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 	} else {
 		lg.Debug().Msgf("forwarding request for network")
 	}
+	ctx, cs := p.capturer.Begin(ctx, nq, network.NetworkId, method)
 	resp, err := network.Forward(ctx, nq)
+	cs.finish(resp, err)
 
 	if err == nil || common.HasErrorCode(err, common.ErrCodeEndpointClientSideException) {
 		if err != nil {
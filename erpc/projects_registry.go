@@ -7,6 +7,7 @@ import (
 
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
 	"github.com/erpc/erpc/health"
 	"github.com/erpc/erpc/upstream"
 	"github.com/erpc/erpc/vendors"
@@ -84,6 +85,39 @@ func (r *ProjectsRegistry) RegisterProject(prjCfg *common.ProjectConfig) (*Prepa
 		return nil, err
 	}
 	metricsTracker := health.NewTracker(prjCfg.Id, wsDuration)
+	if prjCfg.HealthCheck != nil && prjCfg.HealthCheck.SharedState != nil {
+		if err := r.startSharedState(&lg, metricsTracker, prjCfg.HealthCheck.SharedState); err != nil {
+			return nil, err
+		}
+	}
+	if prjCfg.HealthCheck != nil && prjCfg.HealthCheck.Persistence != nil {
+		var budgetIds []string
+		for _, ups := range prjCfg.Upstreams {
+			if ups.RateLimitBudget != "" {
+				budgetIds = append(budgetIds, ups.RateLimitBudget)
+			}
+		}
+		persister, err := NewHealthPersister(r.appCtx, &lg, prjCfg.Id, metricsTracker, r.rateLimitersRegistry, budgetIds, prjCfg.HealthCheck.Persistence)
+		if err != nil {
+			return nil, err
+		}
+		persister.Bootstrap(r.appCtx)
+	}
+	var usageMeter *UsageMeter
+	if prjCfg.Metering != nil {
+		usageMeter, err = NewUsageMeter(r.appCtx, &lg, prjCfg.Id, prjCfg.Metering)
+		if err != nil {
+			return nil, err
+		}
+		usageMeter.Bootstrap(r.appCtx)
+	}
+	var capturer *Capturer
+	if prjCfg.Capture != nil {
+		capturer, err = NewCapturer(r.appCtx, &lg, prjCfg.Id, prjCfg.Capture)
+		if err != nil {
+			return nil, err
+		}
+	}
 	upstreamsRegistry := upstream.NewUpstreamsRegistry(
 		r.appCtx,
 		&lg,
@@ -107,7 +141,7 @@ func (r *ProjectsRegistry) RegisterProject(prjCfg *common.ProjectConfig) (*Prepa
 
 	var consumerAuthRegistry *auth.AuthRegistry
 	if prjCfg.Auth != nil {
-		consumerAuthRegistry, err = auth.NewAuthRegistry(&lg, prjCfg.Id, prjCfg.Auth, r.rateLimitersRegistry)
+		consumerAuthRegistry, err = auth.NewAuthRegistry(r.appCtx, &lg, prjCfg.Id, prjCfg.Auth, r.rateLimitersRegistry)
 		if err != nil {
 			return nil, err
 		}
@@ -125,6 +159,8 @@ func (r *ProjectsRegistry) RegisterProject(prjCfg *common.ProjectConfig) (*Prepa
 		upstreamsRegistry:    upstreamsRegistry,
 		rateLimitersRegistry: r.rateLimitersRegistry,
 		evmJsonRpcCache:      r.evmJsonRpcCache,
+		capturer:             capturer,
+		usageMeter:           usageMeter,
 	}
 	pp.Networks = make(map[string]*Network)
 
@@ -154,3 +190,18 @@ func (r *ProjectsRegistry) loadProject(projectId string) (*PreparedProject, erro
 
 	return nil, common.NewErrProjectNotFound(projectId)
 }
+
+func (r *ProjectsRegistry) startSharedState(logger *zerolog.Logger, tracker *health.Tracker, cfg *common.SharedStateConfig) error {
+	transport, err := data.NewRedisPubSub(r.appCtx, logger, cfg.Connector, cfg.Channel)
+	if err != nil {
+		return err
+	}
+
+	sharedState, err := health.NewSharedState(logger, tracker, transport, cfg)
+	if err != nil {
+		return err
+	}
+	sharedState.Start(r.appCtx)
+	logger.Info().Str("connector", cfg.Connector.Id).Str("channel", cfg.Channel).Msg("sharing upstream health state across replicas")
+	return nil
+}
@@ -0,0 +1,144 @@
+package erpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/spf13/afero"
+)
+
+// ReplaySummary counts how many captured requests returned the same result when replayed.
+type ReplaySummary struct {
+	Total    int
+	Matched  int
+	Mismatch int
+	Skipped  int
+}
+
+// Replay re-runs requests captured in a JSONL file (see project capture config) against the given configuration,
+// and writes a diff of the final result for each request whose result differs from the captured one.
+// Args are the path of the capture file and optionally the configuration path.
+func Replay(
+	ctx context.Context,
+	logger zerolog.Logger,
+	fs afero.Fs,
+	out io.Writer,
+	args []string,
+) (*ReplaySummary, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("usage: erpc replay <captures.jsonl> [config]")
+	}
+	configPath := ""
+	if len(args) > 1 {
+		configPath = args[1]
+	}
+	cfg, err := loadConfig(&logger, fs, configPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, prj := range cfg.Projects {
+		// Replayed requests must not be captured again nor metered as consumer usage, and must not affect
+		// (or be affected by) health state shared with or persisted by running instances
+		prj.Capture = nil
+		prj.Metering = nil
+		if prj.HealthCheck != nil {
+			prj.HealthCheck.SharedState = nil
+			prj.HealthCheck.Persistence = nil
+		}
+	}
+
+	// Cache is not used so that results always come from upstreams
+	erpcInstance, err := NewERPC(ctx, &logger, nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := fs.Open(args[0])
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	summary := &ReplaySummary{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		summary.Total++
+
+		rec := &CaptureRecord{}
+		if err := common.SonicCfg.Unmarshal(line, rec); err != nil {
+			summary.Skipped++
+			fmt.Fprintf(out, "SKIP line %d: invalid capture record: %v\n", summary.Total, err)
+			continue
+		}
+		if rec.Redacted {
+			summary.Skipped++
+			fmt.Fprintf(out, "SKIP %s: params of %s are redacted\n", rec.Id, rec.Method)
+			continue
+		}
+		project, err := erpcInstance.GetProject(rec.ProjectId)
+		if err != nil || project == nil {
+			summary.Skipped++
+			fmt.Fprintf(out, "SKIP %s: project %s not found in config\n", rec.Id, rec.ProjectId)
+			continue
+		}
+
+		nq := common.NewNormalizedRequest(rec.Request)
+		resp, err := project.Forward(ctx, rec.NetworkId, nq)
+		response, errorCode, errorMessage := captureResult(resp, err)
+		if resp != nil {
+			resp.Release()
+		}
+
+		if sameReplayResult(rec.Response, rec.ErrorCode, response, errorCode) {
+			summary.Matched++
+			logger.Debug().Str("captureId", rec.Id).Str("method", rec.Method).Msg("replayed request matches capture")
+			continue
+		}
+
+		summary.Mismatch++
+		fmt.Fprintf(out, "DIFF %s %s %s\n", rec.Id, rec.NetworkId, rec.Method)
+		fmt.Fprintf(out, "  - captured: %s\n", replayResultString(rec.Response, rec.ErrorCode, rec.Error))
+		fmt.Fprintf(out, "  + replayed: %s\n", replayResultString(response, errorCode, errorMessage))
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+
+	fmt.Fprintf(out, "replayed %d requests: %d matched, %d differed, %d skipped\n", summary.Total, summary.Matched, summary.Mismatch, summary.Skipped)
+
+	return summary, nil
+}
+
+// sameReplayResult compares two json-rpc responses ignoring their ids, or the error codes when either has failed.
+func sameReplayResult(capturedResponse []byte, capturedErrorCode string, replayedResponse []byte, replayedErrorCode string) bool {
+	if capturedErrorCode != "" || replayedErrorCode != "" {
+		return capturedErrorCode == replayedErrorCode
+	}
+	var a, b map[string]interface{}
+	if err := common.SonicCfg.Unmarshal(capturedResponse, &a); err != nil {
+		return false
+	}
+	if err := common.SonicCfg.Unmarshal(replayedResponse, &b); err != nil {
+		return false
+	}
+	delete(a, "id")
+	delete(b, "id")
+	return reflect.DeepEqual(a, b)
+}
+
+func replayResultString(response []byte, errorCode, errorMessage string) string {
+	if errorCode != "" {
+		return fmt.Sprintf("%s: %s", errorCode, errorMessage)
+	}
+	return string(response)
+}
@@ -0,0 +1,61 @@
+package erpc
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// parseSiweNoncePath detects nonce issuance requests on GET /<project>/siwe/nonce
+// (or GET /siwe/nonce when project is selected via domain aliasing).
+func parseSiweNoncePath(r *http.Request, preSelectedProjectId string) (projectId string, ok bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodOptions {
+		return "", false
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path.Clean(r.URL.Path), "/"), "/")
+	switch {
+	case len(segments) == 3 && segments[1] == "siwe" && segments[2] == "nonce" && preSelectedProjectId == "":
+		return segments[0], true
+	case len(segments) == 2 && segments[0] == "siwe" && segments[1] == "nonce" && preSelectedProjectId != "":
+		return preSelectedProjectId, true
+	}
+
+	return "", false
+}
+
+func (s *HttpServer) handleSiweNonce(w http.ResponseWriter, r *http.Request, startedAt *time.Time, projectId string, encoder sonic.Encoder, writeFatalError func(statusCode int, body error)) {
+	logger := s.logger.With().Str("handler", "siwe_nonce").Str("projectId", projectId).Logger()
+
+	project, err := s.erpc.GetProject(projectId)
+	if err != nil {
+		handleErrorResponse(&logger, startedAt, nil, err, w, encoder, writeFatalError)
+		return
+	}
+
+	// Nonces are requested by browser dapps before signing in, so project CORS rules apply
+	if project.Config.CORS != nil {
+		if !s.handleCORS(w, r, project.Config.CORS) || r.Method == http.MethodOptions {
+			return
+		}
+	}
+
+	nonce, expiresAt, err := project.IssueSiweNonce(r.Context())
+	if err != nil {
+		handleErrorResponse(&logger, startedAt, nil, err, w, encoder, writeFatalError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	err = encoder.Encode(map[string]interface{}{
+		"nonce":     nonce,
+		"expiresAt": expiresAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to encode siwe nonce response")
+	}
+}
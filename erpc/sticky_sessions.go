@@ -0,0 +1,86 @@
+package erpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/upstream"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// StickySessions pins requests of the same client session to the upstream that last served it,
+// so that clients get read-your-writes consistency (e.g. a receipt right after sending a tx).
+type StickySessions struct {
+	cfg *common.StickySessionConfig
+	// session key -> upstream id
+	pins *expirable.LRU[string, string]
+}
+
+func NewStickySessions(cfg *common.StickySessionConfig) (*StickySessions, error) {
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sticky session window: %w", err)
+	}
+
+	return &StickySessions{
+		cfg:  cfg,
+		pins: expirable.NewLRU[string, string](cfg.MaxSessions, nil, window),
+	}, nil
+}
+
+// SessionKey returns the key that identifies the client session of the request, or empty if it cannot be determined.
+func (s *StickySessions) SessionKey(req *common.NormalizedRequest) string {
+	switch s.cfg.KeyBy {
+	case common.StickySessionKeyIdentity:
+		if user := req.User(); user != nil && user.Id != "" {
+			return "identity:" + user.Id
+		}
+	case common.StickySessionKeyHeader:
+		if ci := req.ClientInfo(); ci != nil && ci.Headers != nil {
+			if v := ci.Headers.Get(s.cfg.Header); v != "" {
+				return "header:" + v
+			}
+		}
+	case common.StickySessionKeyIP:
+		if ci := req.ClientInfo(); ci != nil && ci.IP != "" {
+			return "ip:" + ci.IP
+		}
+	}
+
+	return ""
+}
+
+// Apply moves the pinned upstream of the session to the front of the list. When the pinned upstream is
+// not in the list anymore (e.g. cordoned due to being unhealthy) the original order is kept as fallback.
+func (s *StickySessions) Apply(key string, upsList []*upstream.Upstream) []*upstream.Upstream {
+	upsId, ok := s.pins.Get(key)
+	if !ok {
+		return upsList
+	}
+
+	for i, ups := range upsList {
+		if ups.Config().Id != upsId {
+			continue
+		}
+		if i == 0 {
+			return upsList
+		}
+		ordered := make([]*upstream.Upstream, 0, len(upsList))
+		ordered = append(ordered, ups)
+		ordered = append(ordered, upsList[:i]...)
+		ordered = append(ordered, upsList[i+1:]...)
+		return ordered
+	}
+
+	return upsList
+}
+
+// Pin records the upstream that served the session. The window starts when the session is pinned
+// (or re-pinned to another upstream after a fallback) and is not extended by subsequent requests.
+func (s *StickySessions) Pin(key, upsId string) {
+	if current, ok := s.pins.Get(key); ok && current == upsId {
+		return
+	}
+	s.pins.Add(key, upsId)
+}
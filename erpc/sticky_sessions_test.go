@@ -0,0 +1,91 @@
+package erpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/upstream"
+	"github.com/erpc/erpc/vendors"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStickySessions(t *testing.T) {
+	logger := log.Logger
+	clr := upstream.NewClientRegistry(&logger)
+	vnr := vendors.NewVendorsRegistry()
+
+	var upsList []*upstream.Upstream
+	for _, id := range []string{"rpc1", "rpc2", "rpc3"} {
+		ups, err := upstream.NewUpstream(context.Background(), "test", &common.UpstreamConfig{
+			Id:       id,
+			Endpoint: "http://" + id + ".localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		}, clr, nil, vnr, &logger, nil)
+		require.NoError(t, err)
+		upsList = append(upsList, ups)
+	}
+
+	ids := func(list []*upstream.Upstream) []string {
+		res := make([]string, len(list))
+		for i, u := range list {
+			res[i] = u.Config().Id
+		}
+		return res
+	}
+
+	t.Run("PinsSessionToServingUpstream", func(t *testing.T) {
+		cfg := &common.StickySessionConfig{KeyBy: common.StickySessionKeyIdentity}
+		cfg.SetDefaults()
+		s, err := NewStickySessions(cfg)
+		require.NoError(t, err)
+
+		req := common.NewNormalizedRequest([]byte(`{"method":"eth_getTransactionReceipt"}`))
+		req.SetUser(&common.User{Id: "wallet-1"})
+		key := s.SessionKey(req)
+		assert.Equal(t, "identity:wallet-1", key)
+
+		assert.Equal(t, []string{"rpc1", "rpc2", "rpc3"}, ids(s.Apply(key, upsList)))
+		s.Pin(key, "rpc3")
+		assert.Equal(t, []string{"rpc3", "rpc1", "rpc2"}, ids(s.Apply(key, upsList)))
+
+		// Falls back to original order when pinned upstream is excluded (e.g. cordoned)
+		assert.Equal(t, []string{"rpc1", "rpc2"}, ids(s.Apply(key, upsList[:2])))
+	})
+
+	t.Run("SessionExpiresAfterWindow", func(t *testing.T) {
+		cfg := &common.StickySessionConfig{KeyBy: common.StickySessionKeyIP, Window: "50ms"}
+		cfg.SetDefaults()
+		s, err := NewStickySessions(cfg)
+		require.NoError(t, err)
+
+		req := common.NewNormalizedRequest([]byte(`{"method":"eth_getTransactionCount"}`))
+		req.SetClientInfo(&common.ClientInfo{IP: "10.0.0.1"})
+		key := s.SessionKey(req)
+
+		s.Pin(key, "rpc2")
+		assert.Equal(t, "rpc2", s.Apply(key, upsList)[0].Config().Id)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, "rpc1", s.Apply(key, upsList)[0].Config().Id)
+	})
+
+	t.Run("NoSessionKeyWithoutHeader", func(t *testing.T) {
+		cfg := &common.StickySessionConfig{Header: "X-Session-Id"}
+		cfg.SetDefaults()
+		s, err := NewStickySessions(cfg)
+		require.NoError(t, err)
+
+		req := common.NewNormalizedRequest([]byte(`{"method":"eth_call"}`))
+		req.SetClientInfo(&common.ClientInfo{IP: "10.0.0.1", Headers: http.Header{}})
+		assert.Equal(t, "", s.SessionKey(req))
+
+		req.SetClientInfo(&common.ClientInfo{Headers: http.Header{"X-Session-Id": []string{"abc"}}})
+		assert.Equal(t, "header:abc", s.SessionKey(req))
+	})
+}
@@ -0,0 +1,127 @@
+package erpc
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
+)
+
+// arrangeByUpstreamGroups reorders upstreams (already sorted by score) into tiers following the order of
+// network upstream groups, and returns the tier of each upstream. A group with fewer healthy upstreams than
+// its minHealthyUpstreams is merged into the same tier as the next group, in which case the score order
+// is kept across both groups. Upstreams of groups that are not configured form the last tier.
+func (n *Network) arrangeByUpstreamGroups(method string, upsList []*upstream.Upstream) ([]*upstream.Upstream, map[string]int) {
+	groups := n.cfg.UpstreamGroups
+	if len(groups) == 0 || len(upsList) == 0 {
+		return upsList, nil
+	}
+
+	rank := make(map[string]int, len(groups))
+	for i, g := range groups {
+		rank[g.Name] = i
+	}
+	position := make(map[string]int, len(upsList))
+	buckets := make([][]*upstream.Upstream, len(groups)+1)
+	for i, u := range upsList {
+		r, ok := rank[u.Config().Group]
+		if !ok {
+			r = len(groups)
+		}
+		buckets[r] = append(buckets[r], u)
+		position[u.Config().Id] = i
+	}
+
+	arranged := make([]*upstream.Upstream, 0, len(upsList))
+	tiers := make(map[string]int, len(upsList))
+	tier := 0
+	var current []*upstream.Upstream
+	healthy := 0
+	flush := func() {
+		sort.SliceStable(current, func(i, j int) bool {
+			return position[current[i].Config().Id] < position[current[j].Config().Id]
+		})
+		for _, u := range current {
+			tiers[u.Config().Id] = tier
+		}
+		arranged = append(arranged, current...)
+		current = nil
+		healthy = 0
+		tier++
+	}
+
+	for r, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+		current = append(current, bucket...)
+		for _, u := range bucket {
+			if n.isUpstreamHealthy(u, method) {
+				healthy++
+			}
+		}
+		if r < len(groups) && healthy < groups[r].MinHealthyUpstreams {
+			continue
+		}
+		flush()
+	}
+	if len(current) > 0 {
+		flush()
+	}
+
+	return arranged, tiers
+}
+
+// isUpstreamHealthy returns false when upstream is cordoned or excluded by selection policy for the method.
+func (n *Network) isUpstreamHealthy(u *upstream.Upstream, method string) bool {
+	if n.metricsTracker != nil && n.metricsTracker.IsCordoned(u.Config().Id, n.NetworkId, method) {
+		return false
+	}
+	if n.selectionPolicyEvaluator != nil && !n.selectionPolicyEvaluator.checkPermitForMethod(u.Config().Id, method) {
+		return false
+	}
+	return true
+}
+
+// hasPendingLowerTier returns true when an upstream of a lower tier than the given upstream is not tried yet,
+// in which case upstreams of higher tiers must not be used yet. An upstream that was already tried counts as
+// exhausted: another execution (i.e. a retry or hedge) only happens when it failed, returned an empty result
+// or is still in-flight, and using the next group is preferred over sending the same request to it again.
+func hasPendingLowerTier(ups *upstream.Upstream, upsList []*upstream.Upstream, tiers map[string]int, attemptedUpstreams *sync.Map) bool {
+	if tiers == nil {
+		return false
+	}
+	tier := tiers[ups.Config().Id]
+	if tier == 0 {
+		return false
+	}
+	for _, u := range upsList {
+		if tiers[u.Config().Id] >= tier {
+			continue
+		}
+		if _, attempted := attemptedUpstreams.Load(u.Config().Id); !attempted {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUpstreamGroupServed counts the request as served by the group of the upstream, and as a fallback when
+// that is not the first group: either because previous tiers were exhausted, or because the first group did not
+// have enough healthy upstreams and was merged with the next ones.
+func (n *Network) recordUpstreamGroupServed(method string, ups *upstream.Upstream, tiers map[string]int) {
+	if tiers == nil {
+		return
+	}
+	group := ups.Config().Group
+	health.MetricNetworkUpstreamGroupServedTotal.WithLabelValues(n.ProjectId, n.NetworkId, method, group).Inc()
+	if group == n.cfg.UpstreamGroups[0].Name {
+		return
+	}
+	reason := "unhealthy"
+	if tiers[ups.Config().Id] > 0 {
+		reason = "exhausted"
+	}
+	health.MetricNetworkUpstreamGroupFallbackTotal.WithLabelValues(n.ProjectId, n.NetworkId, method, group, reason).Inc()
+}
@@ -0,0 +1,114 @@
+package erpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/upstream"
+	"github.com/erpc/erpc/vendors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamGroups(t *testing.T) {
+	logger := log.Logger
+	clr := upstream.NewClientRegistry(&logger)
+	vnr := vendors.NewVendorsRegistry()
+
+	var upsList []*upstream.Upstream
+	for _, c := range []struct{ id, group string }{
+		{"paid1", "paid"},
+		{"self1", "self-hosted"},
+		{"other1", "other"},
+		{"self2", "self-hosted"},
+	} {
+		ups, err := upstream.NewUpstream(context.Background(), "test", &common.UpstreamConfig{
+			Id:       c.id,
+			Group:    c.group,
+			Endpoint: "http://" + c.id + ".localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		}, clr, nil, vnr, &logger, nil)
+		require.NoError(t, err)
+		upsList = append(upsList, ups)
+	}
+
+	newNetwork := func(groups ...*common.UpstreamGroupConfig) *Network {
+		return &Network{
+			ProjectId:      "test",
+			NetworkId:      "evm:123",
+			Logger:         &logger,
+			cfg:            &common.NetworkConfig{UpstreamGroups: groups},
+			metricsTracker: health.NewTracker("test", time.Minute),
+		}
+	}
+	ids := func(list []*upstream.Upstream) []string {
+		res := make([]string, len(list))
+		for i, u := range list {
+			res[i] = u.Config().Id
+		}
+		return res
+	}
+
+	t.Run("OrdersUpstreamsByGroup", func(t *testing.T) {
+		n := newNetwork(&common.UpstreamGroupConfig{Name: "self-hosted"}, &common.UpstreamGroupConfig{Name: "paid"})
+		arranged, tiers := n.arrangeByUpstreamGroups("eth_call", upsList)
+		assert.Equal(t, []string{"self1", "self2", "paid1", "other1"}, ids(arranged))
+		assert.Equal(t, map[string]int{"self1": 0, "self2": 0, "paid1": 1, "other1": 2}, tiers)
+	})
+
+	t.Run("MergesNextGroupWhenNotEnoughHealthyUpstreams", func(t *testing.T) {
+		n := newNetwork(&common.UpstreamGroupConfig{Name: "self-hosted", MinHealthyUpstreams: 2}, &common.UpstreamGroupConfig{Name: "paid"})
+		n.metricsTracker.Cordon("self2", "evm:123", "*", "test")
+		arranged, tiers := n.arrangeByUpstreamGroups("eth_call", upsList)
+		// Score order is kept within the merged tier
+		assert.Equal(t, []string{"paid1", "self1", "self2", "other1"}, ids(arranged))
+		assert.Equal(t, map[string]int{"paid1": 0, "self1": 0, "self2": 0, "other1": 1}, tiers)
+	})
+
+	t.Run("FallbackGroupWaitsForPreviousGroups", func(t *testing.T) {
+		n := newNetwork(&common.UpstreamGroupConfig{Name: "self-hosted"}, &common.UpstreamGroupConfig{Name: "paid"})
+		arranged, tiers := n.arrangeByUpstreamGroups("eth_call", upsList)
+		attempted := &sync.Map{}
+		paid := arranged[2]
+
+		assert.True(t, hasPendingLowerTier(paid, arranged, tiers, attempted))
+		attempted.Store("self1", true)
+		assert.True(t, hasPendingLowerTier(paid, arranged, tiers, attempted))
+		attempted.Store("self2", true)
+		assert.False(t, hasPendingLowerTier(paid, arranged, tiers, attempted))
+	})
+
+	t.Run("CountsFallbackOnlyWhenServedByLaterGroup", func(t *testing.T) {
+		fallbacks := func(group, reason string) float64 {
+			return testutil.ToFloat64(health.MetricNetworkUpstreamGroupFallbackTotal.WithLabelValues("test", "evm:123", "eth_getLogs", group, reason))
+		}
+
+		n := newNetwork(&common.UpstreamGroupConfig{Name: "self-hosted", MinHealthyUpstreams: 2}, &common.UpstreamGroupConfig{Name: "paid"})
+		n.metricsTracker.Cordon("self2", "evm:123", "*", "test")
+		arranged, tiers := n.arrangeByUpstreamGroups("eth_getLogs", upsList)
+		assert.Zero(t, fallbacks("paid", "unhealthy"), "arranging alone must not count a fallback")
+
+		n.recordUpstreamGroupServed("eth_getLogs", arranged[1], tiers)
+		assert.Zero(t, fallbacks("paid", "unhealthy"), "served by first group")
+
+		n.recordUpstreamGroupServed("eth_getLogs", arranged[0], tiers)
+		assert.Equal(t, float64(1), fallbacks("paid", "unhealthy"))
+
+		n.recordUpstreamGroupServed("eth_getLogs", arranged[3], tiers)
+		assert.Equal(t, float64(1), fallbacks("other", "exhausted"))
+	})
+
+	t.Run("NoGroupsKeepsOrder", func(t *testing.T) {
+		arranged, tiers := newNetwork().arrangeByUpstreamGroups("eth_call", upsList)
+		assert.Equal(t, ids(upsList), ids(arranged))
+		assert.Nil(t, tiers)
+	})
+}
@@ -0,0 +1,374 @@
+package erpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const anonymousConsumerId = "anonymous"
+
+// UsageRecord is the usage of a single consumer during one minute, as flushed by the usage meter.
+type UsageRecord struct {
+	ProjectId     string           `json:"projectId"`
+	ConsumerId    string           `json:"consumerId"`
+	Instance      string           `json:"instance"`
+	Timestamp     int64            `json:"timestamp"`
+	Requests      int64            `json:"requests"`
+	Errors        int64            `json:"errors"`
+	CacheHits     int64            `json:"cacheHits"`
+	ComputeUnits  int64            `json:"computeUnits"`
+	RequestBytes  int64            `json:"requestBytes"`
+	ResponseBytes int64            `json:"responseBytes"`
+	Methods       map[string]int64 `json:"methods"`
+}
+
+func (r *UsageRecord) merge(other *UsageRecord) {
+	r.Requests += other.Requests
+	r.Errors += other.Errors
+	r.CacheHits += other.CacheHits
+	r.ComputeUnits += other.ComputeUnits
+	r.RequestBytes += other.RequestBytes
+	r.ResponseBytes += other.ResponseBytes
+	for m, c := range other.Methods {
+		r.Methods[m] += c
+	}
+}
+
+type usageStore interface {
+	Save(ctx context.Context, records []*UsageRecord) error
+}
+
+type usageKey struct {
+	consumerId string
+	minute     int64
+}
+
+// UsageMeter aggregates usage per consumer per minute in memory, since consumer ids are too high-cardinality
+// to be used as prometheus labels, and periodically flushes completed minutes to a store.
+type UsageMeter struct {
+	logger        *zerolog.Logger
+	projectId     string
+	instance      string
+	cfg           *common.MeteringConfig
+	store         usageStore
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	buckets map[usageKey]*UsageRecord
+}
+
+func NewUsageMeter(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	projectId string,
+	cfg *common.MeteringConfig,
+) (*UsageMeter, error) {
+	lg := logger.With().Str("component", "usageMeter").Logger()
+	m := &UsageMeter{
+		logger:    &lg,
+		projectId: projectId,
+		cfg:       cfg,
+		buckets:   make(map[usageKey]*UsageRecord),
+	}
+
+	var err error
+	if m.flushInterval, err = time.ParseDuration(cfg.FlushInterval); err != nil {
+		return nil, fmt.Errorf("invalid metering flushInterval: %v", err)
+	}
+	// Records of multiple replicas for the same consumer and minute are stored separately
+	if m.instance, err = os.Hostname(); err != nil {
+		m.instance = "unknown"
+	}
+
+	if cfg.Connector != nil {
+		ttl, err := time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metering ttl: %v", err)
+		}
+		m.store = &postgresUsageStore{cfg: cfg.Connector.PostgreSQL, projectId: projectId, ttl: ttl}
+	} else {
+		m.store = &fileUsageStore{path: cfg.File}
+	}
+
+	return m, nil
+}
+
+// Bootstrap flushes completed minutes every flush interval, and all pending usage once more on shutdown.
+func (m *UsageMeter) Bootstrap(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := m.Flush(sctx, true); err != nil {
+					m.logger.Warn().Err(err).Msg("failed to flush usage on shutdown")
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				if err := m.Flush(ctx, false); err != nil {
+					m.logger.Warn().Err(err).Msg("failed to flush usage")
+				}
+			}
+		}
+	}()
+}
+
+func (m *UsageMeter) computeUnits(method string) int {
+	for _, cu := range m.cfg.ComputeUnits {
+		if match, _ := common.WildcardMatch(cu.Method, method); match {
+			return cu.Units
+		}
+	}
+	return m.cfg.DefaultComputeUnits
+}
+
+// Record adds a served request to the usage of its consumer in the current minute. The res is what was returned
+// to the client (a response or an error body) and responseBytes is how many bytes were written for it.
+func (m *UsageMeter) Record(nq *common.NormalizedRequest, res interface{}, responseBytes int64) {
+	if m == nil || nq == nil {
+		return
+	}
+
+	consumerId := anonymousConsumerId
+	if user := nq.User(); user != nil && user.Id != "" {
+		consumerId = user.Id
+	}
+	method, _ := nq.Method()
+
+	resp, ok := res.(*common.NormalizedResponse)
+	failed := !ok
+	fromCache := ok && resp.FromCache()
+
+	key := usageKey{consumerId: consumerId, minute: time.Now().Truncate(time.Minute).Unix()}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.buckets[key]
+	if !ok {
+		rec = &UsageRecord{
+			ProjectId:  m.projectId,
+			ConsumerId: consumerId,
+			Instance:   m.instance,
+			Timestamp:  key.minute,
+			Methods:    make(map[string]int64),
+		}
+		m.buckets[key] = rec
+	}
+	rec.Requests++
+	if failed {
+		rec.Errors++
+	}
+	if fromCache {
+		rec.CacheHits++
+	}
+	rec.ComputeUnits += int64(m.computeUnits(method))
+	rec.RequestBytes += int64(len(nq.Body()))
+	rec.ResponseBytes += responseBytes
+	rec.Methods[method]++
+}
+
+// Flush stores usage of completed minutes (or all usage when all is true). Records that fail to be stored
+// are kept in memory and retried on the next flush.
+func (m *UsageMeter) Flush(ctx context.Context, all bool) error {
+	currentMinute := time.Now().Truncate(time.Minute).Unix()
+
+	m.mu.Lock()
+	var records []*UsageRecord
+	for key, rec := range m.buckets {
+		if all || key.minute < currentMinute {
+			records = append(records, rec)
+			delete(m.buckets, key)
+		}
+	}
+	m.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := m.store.Save(ctx, records); err != nil {
+		m.mu.Lock()
+		for _, rec := range records {
+			key := usageKey{consumerId: rec.ConsumerId, minute: rec.Timestamp}
+			if existing, ok := m.buckets[key]; ok {
+				existing.merge(rec)
+			} else {
+				m.buckets[key] = rec
+			}
+		}
+		m.mu.Unlock()
+		return err
+	}
+
+	m.logger.Debug().Int("records", len(records)).Msg("flushed usage records")
+	return nil
+}
+
+// postgresUsageStore writes records to a table with a column per counter (and methods as JSONB) so that usage
+// can be queried directly, e.g. for billing. Rows older than ttl are deleted on each flush. Connecting is done
+// lazily so that records are kept in memory and retried on next flush while the database is unavailable.
+type postgresUsageStore struct {
+	cfg       *common.PostgreSQLConnectorConfig
+	projectId string
+	ttl       time.Duration
+
+	mu   sync.Mutex
+	conn *pgxpool.Pool
+}
+
+func (s *postgresUsageStore) connect(ctx context.Context) (*pgxpool.Pool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn, nil
+	}
+
+	config, err := pgxpool.ParseConfig(s.cfg.ConnectionUri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection URI: %w", err)
+	}
+	config.MinConns = s.cfg.MinConns
+	config.MaxConns = s.cfg.MaxConns
+	conn, err := pgxpool.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			project_id TEXT NOT NULL,
+			consumer_id TEXT NOT NULL,
+			instance TEXT NOT NULL,
+			minute TIMESTAMP WITH TIME ZONE NOT NULL,
+			requests BIGINT NOT NULL DEFAULT 0,
+			errors BIGINT NOT NULL DEFAULT 0,
+			cache_hits BIGINT NOT NULL DEFAULT 0,
+			compute_units BIGINT NOT NULL DEFAULT 0,
+			request_bytes BIGINT NOT NULL DEFAULT 0,
+			response_bytes BIGINT NOT NULL DEFAULT 0,
+			methods JSONB NOT NULL DEFAULT '{}',
+			PRIMARY KEY (project_id, consumer_id, minute, instance)
+		)
+	`, s.cfg.Table))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create usage table: %w", err)
+	}
+	_, err = conn.Exec(ctx, fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS idx_erpc_usage_minute ON %s (minute)
+	`, s.cfg.Table))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create usage minute index: %w", err)
+	}
+
+	s.conn = conn
+	return conn, nil
+}
+
+func (s *postgresUsageStore) Save(ctx context.Context, records []*UsageRecord) error {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Counters are added up when the same consumer, minute and instance is flushed again (e.g. after a restart)
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s AS u (project_id, consumer_id, instance, minute, requests, errors, cache_hits, compute_units, request_bytes, response_bytes, methods)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (project_id, consumer_id, minute, instance) DO UPDATE SET
+			requests = u.requests + EXCLUDED.requests,
+			errors = u.errors + EXCLUDED.errors,
+			cache_hits = u.cache_hits + EXCLUDED.cache_hits,
+			compute_units = u.compute_units + EXCLUDED.compute_units,
+			request_bytes = u.request_bytes + EXCLUDED.request_bytes,
+			response_bytes = u.response_bytes + EXCLUDED.response_bytes,
+			methods = (
+				SELECT COALESCE(jsonb_object_agg(key, total), '{}'::jsonb) FROM (
+					SELECT key, SUM(value::bigint) AS total FROM (
+						SELECT * FROM jsonb_each_text(u.methods)
+						UNION ALL
+						SELECT * FROM jsonb_each_text(EXCLUDED.methods)
+					) m GROUP BY key
+				) t
+			)
+	`, s.cfg.Table)
+
+	batch := &pgx.Batch{}
+	for _, rec := range records {
+		methods, err := common.SonicCfg.Marshal(rec.Methods)
+		if err != nil {
+			return err
+		}
+		batch.Queue(query,
+			rec.ProjectId,
+			rec.ConsumerId,
+			rec.Instance,
+			time.Unix(rec.Timestamp, 0).UTC(),
+			rec.Requests,
+			rec.Errors,
+			rec.CacheHits,
+			rec.ComputeUnits,
+			rec.RequestBytes,
+			rec.ResponseBytes,
+			string(methods),
+		)
+	}
+	batch.Queue(
+		fmt.Sprintf(`DELETE FROM %s WHERE project_id = $1 AND minute < $2`, s.cfg.Table),
+		s.projectId,
+		time.Now().Add(-s.ttl).UTC(),
+	)
+
+	// All records are written in a single transaction, so a failed flush can be retried without double counting
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// fileUsageStore appends each record as a line of a newline-delimited JSON file.
+type fileUsageStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileUsageStore) Save(ctx context.Context, records []*UsageRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf []byte
+	for _, rec := range records {
+		payload, err := common.SonicCfg.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, payload...)
+		buf = append(buf, '\n')
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(buf)
+	return err
+}
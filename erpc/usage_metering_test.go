@@ -0,0 +1,137 @@
+package erpc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingUsageStore struct {
+	err error
+}
+
+func (s *failingUsageStore) Save(ctx context.Context, records []*UsageRecord) error {
+	return s.err
+}
+
+func TestUsageMeter(t *testing.T) {
+	logger := log.Logger
+
+	newMeter := func(t *testing.T) (*UsageMeter, string) {
+		path := filepath.Join(t.TempDir(), "usage.ndjson")
+		cfg := &common.MeteringConfig{
+			File: path,
+			ComputeUnits: []*common.MethodComputeUnitsConfig{
+				{Method: "eth_getLogs", Units: 75},
+				{Method: "trace_*", Units: 200},
+			},
+		}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		m, err := NewUsageMeter(context.Background(), &logger, "test", cfg)
+		require.NoError(t, err)
+		return m, path
+	}
+	newRequest := func(consumerId string, method string) *common.NormalizedRequest {
+		nq := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`))
+		if consumerId != "" {
+			nq.SetUser(&common.User{Id: consumerId})
+		}
+		return nq
+	}
+	newResponse := func(t *testing.T, nq *common.NormalizedRequest, fromCache bool) *common.NormalizedResponse {
+		jrr, err := common.NewJsonRpcResponse(1, "0x1234", nil)
+		require.NoError(t, err)
+		return common.NewNormalizedResponse().WithRequest(nq).WithJsonRpcResponse(jrr).WithFromCache(fromCache)
+	}
+
+	t.Run("AggregatesPerConsumerAndFlushesToFile", func(t *testing.T) {
+		m, path := newMeter(t)
+
+		nq1 := newRequest("key-a", "eth_getLogs")
+		m.Record(nq1, newResponse(t, nq1, false), 40)
+		nq2 := newRequest("key-a", "eth_call")
+		m.Record(nq2, newResponse(t, nq2, true), 40)
+		m.Record(newRequest("key-a", "trace_block"), &HttpJsonRpcErrorResponse{}, 90)
+		nq3 := newRequest("", "eth_call")
+		m.Record(nq3, newResponse(t, nq3, false), 40)
+
+		require.NoError(t, m.Flush(context.Background(), true))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 2)
+
+		byConsumer := map[string]*UsageRecord{}
+		for _, line := range lines {
+			rec := &UsageRecord{}
+			require.NoError(t, common.SonicCfg.Unmarshal([]byte(line), rec))
+			byConsumer[rec.ConsumerId] = rec
+		}
+
+		a := byConsumer["key-a"]
+		require.NotNil(t, a)
+		assert.Equal(t, int64(3), a.Requests)
+		assert.Equal(t, int64(1), a.Errors)
+		assert.Equal(t, int64(1), a.CacheHits)
+		assert.Equal(t, int64(75+1+200), a.ComputeUnits)
+		assert.Equal(t, int64(40+40+90), a.ResponseBytes, "must count bytes written to the client including error bodies")
+		assert.Equal(t, map[string]int64{"eth_getLogs": 1, "eth_call": 1, "trace_block": 1}, a.Methods)
+
+		anon := byConsumer[anonymousConsumerId]
+		require.NotNil(t, anon)
+		assert.Equal(t, int64(1), anon.Requests)
+	})
+
+	t.Run("KeepsRecordsWhenStoreFails", func(t *testing.T) {
+		m, _ := newMeter(t)
+		m.store = &failingUsageStore{err: errors.New("store unavailable")}
+
+		m.Record(newRequest("key-b", "eth_call"), nil, 0)
+		require.Error(t, m.Flush(context.Background(), true))
+		m.Record(newRequest("key-b", "eth_call"), nil, 0)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		total := int64(0)
+		for _, rec := range m.buckets {
+			total += rec.Requests
+		}
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("ConnectorMustBePostgreSQLTable", func(t *testing.T) {
+		cfg := &common.MeteringConfig{
+			Connector: &common.ConnectorConfig{
+				Id:     "usage",
+				Driver: common.DriverMemory,
+				Memory: &common.MemoryConnectorConfig{MaxItems: 100},
+			},
+		}
+		cfg.SetDefaults()
+		assert.Error(t, cfg.Validate())
+
+		cfg = &common.MeteringConfig{
+			Connector: &common.ConnectorConfig{
+				Id:         "usage",
+				PostgreSQL: &common.PostgreSQLConnectorConfig{ConnectionUri: "postgres://localhost:5432/erpc"},
+			},
+		}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		assert.Equal(t, "erpc_usage", cfg.Connector.PostgreSQL.Table)
+
+		m, err := NewUsageMeter(context.Background(), &logger, "test", cfg)
+		require.NoError(t, err)
+		assert.IsType(t, &postgresUsageStore{}, m.store)
+	})
+}
@@ -46,6 +46,12 @@ var (
 		Help:      "Total number of self-imposed rate limited requests before sending to upstreams.",
 	}, []string{"project", "network", "upstream", "category"})
 
+	MetricUpstreamMaxConcurrencyReachedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "upstream_request_max_concurrency_reached_total",
+		Help:      "Total number of requests skipped because the upstream reached its max concurrent in-flight requests.",
+	}, []string{"project", "network", "upstream", "category"})
+
 	MetricUpstreamRemoteRateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "upstream_request_remote_rate_limited_total",
@@ -106,6 +112,36 @@ var (
 		Help:      "Whether upstream is un/cordoned (excluded from routing by selection policy).",
 	}, []string{"project", "network", "upstream", "category"})
 
+	MetricUpstreamHealthProbeTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "upstream_health_probe_total",
+		Help:      "Total number of active health probes sent to upstreams by outcome (success, failure, mismatch, skipped).",
+	}, []string{"project", "network", "upstream", "category", "outcome"})
+
+	MetricUpstreamHealthProbeHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "erpc",
+		Name:      "upstream_health_probe_healthy",
+		Help:      "Whether the latest active health probes of upstream are passing (1) or failing beyond the threshold (0).",
+	}, []string{"project", "network", "upstream", "category"})
+
+	MetricUpstreamCircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "erpc",
+		Name:      "upstream_circuit_breaker_state",
+		Help:      "Current state of upstream circuit breakers per scope key (0=closed, 1=half-open, 2=open).",
+	}, []string{"project", "upstream", "scope_key"})
+
+	MetricUpstreamCircuitBreakerTransitionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "upstream_circuit_breaker_transitions_total",
+		Help:      "Total number of upstream circuit breaker state transitions per scope key.",
+	}, []string{"project", "upstream", "scope_key", "from", "to"})
+
+	MetricFailsafeRetryBudgetExhaustedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "failsafe_retry_budget_exhausted_total",
+		Help:      "Total number of retries or hedges suppressed because the retry budget was exhausted.",
+	}, []string{"scope", "entity", "policy"})
+
 	MetricNetworkRequestSelfRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "network_request_self_rate_limited_total",
@@ -136,6 +172,24 @@ var (
 		Help:      "Total number of successful requests for a network.",
 	}, []string{"project", "network", "category"})
 
+	MetricNetworkUpstreamGroupServedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "network_upstream_group_served_total",
+		Help:      "Total number of requests served by upstreams of each group for a network.",
+	}, []string{"project", "network", "category", "group"})
+
+	MetricNetworkUpstreamGroupFallbackTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "network_upstream_group_fallback_total",
+		Help:      "Total number of times a fallback upstream group was used, because previous groups were exhausted or had too few healthy upstreams.",
+	}, []string{"project", "network", "category", "group", "reason"})
+
+	MetricNetworkEmptyResponseVerificationTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "network_empty_response_verification_total",
+		Help:      "Total number of empty responses verified against another upstream (outcome: confirmed, contradicted or unverified).",
+	}, []string{"project", "network", "category", "outcome"})
+
 	MetricNetworkCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "network_cache_hits_total",
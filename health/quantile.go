@@ -47,6 +47,11 @@ func (p *QuantileTracker) Add(value float64) {
 
 // P90 calculates the 90th percentile of the current values in the time window
 func (p *QuantileTracker) P90() float64 {
+	return p.Quantile(0.9)
+}
+
+// Quantile calculates the given quantile (between 0 and 1) of the current values in the time window
+func (p *QuantileTracker) Quantile(q float64) float64 {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -60,7 +65,7 @@ func (p *QuantileTracker) P90() float64 {
 	}
 	sort.Float64s(sortedValues)
 
-	index := int(float64(len(sortedValues)-1) * 0.9)
+	index := int(float64(len(sortedValues)-1) * q)
 	return sortedValues[index]
 }
 
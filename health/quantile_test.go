@@ -128,3 +128,21 @@ func TestP90WithSmallDifferences(t *testing.T) {
 		t.Errorf("Expected P90 with small differences to be %f, got %f", expectedP90, p90)
 	}
 }
+
+func TestQuantile(t *testing.T) {
+	qt := NewQuantileTracker(5 * time.Minute)
+
+	for i := 1; i <= 100; i++ {
+		qt.Add(float64(i))
+	}
+
+	if p99 := qt.Quantile(0.99); p99 != 99.0 {
+		t.Errorf("Expected P99 to be 99.0, got %f", p99)
+	}
+	if p50 := qt.Quantile(0.5); p50 != 50.0 {
+		t.Errorf("Expected P50 to be 50.0, got %f", p50)
+	}
+	if qt.Quantile(0.9) != qt.P90() {
+		t.Errorf("Expected Quantile(0.9) to equal P90")
+	}
+}
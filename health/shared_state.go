@@ -0,0 +1,260 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+)
+
+// SharedStateTransport broadcasts snapshots between replicas (e.g. a Redis pub/sub channel).
+type SharedStateTransport interface {
+	Publish(ctx context.Context, payload []byte) error
+	Subscribe(ctx context.Context) <-chan []byte
+}
+
+// SharedState periodically publishes the local health signals of a tracker (error rates, rate-limit hits,
+// cordons and latest blocks) and merges signals received from other replicas into the same tracker,
+// so that the whole fleet reacts together when an upstream starts failing.
+//
+// Only locally observed values are published, and remote values are kept separately from local counters
+// and ignored once older than staleAfter, which means local data takes precedence when the shared signal is stale.
+type SharedState struct {
+	logger       *zerolog.Logger
+	tracker      *Tracker
+	transport    SharedStateTransport
+	replicaId    string
+	syncInterval time.Duration
+	staleAfter   time.Duration
+
+	mu       sync.Mutex
+	replicas map[string]*sharedSnapshot
+}
+
+type sharedSnapshot struct {
+	ReplicaId string                `json:"replicaId"`
+	ProjectId string                `json:"projectId"`
+	Timestamp int64                 `json:"timestamp"`
+	Metrics   []*sharedMetricsEntry `json:"metrics,omitempty"`
+	Blocks    []*sharedBlocksEntry  `json:"blocks,omitempty"`
+}
+
+type sharedMetricsEntry struct {
+	Upstream       string `json:"upstream"`
+	Network        string `json:"network"`
+	Method         string `json:"method"`
+	Requests       int64  `json:"requests,omitempty"`
+	Errors         int64  `json:"errors,omitempty"`
+	Throttled      int64  `json:"throttled,omitempty"`
+	Cordoned       bool   `json:"cordoned,omitempty"`
+	CordonedUntil  int64  `json:"cordonedUntil,omitempty"`
+	CordonedReason string `json:"cordonedReason,omitempty"`
+}
+
+type sharedBlocksEntry struct {
+	Upstream       string `json:"upstream"`
+	Network        string `json:"network"`
+	LatestBlock    int64  `json:"latestBlock,omitempty"`
+	FinalizedBlock int64  `json:"finalizedBlock,omitempty"`
+}
+
+func NewSharedState(
+	logger *zerolog.Logger,
+	tracker *Tracker,
+	transport SharedStateTransport,
+	cfg *common.SharedStateConfig,
+) (*SharedState, error) {
+	syncInterval, err := time.ParseDuration(cfg.SyncInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shared state syncInterval: %v", err)
+	}
+	staleAfter, err := time.ParseDuration(cfg.StaleAfter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shared state staleAfter: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	replicaId := fmt.Sprintf("%s-%d", hostname, util.RandomID())
+	lg := logger.With().Str("component", "sharedState").Str("replicaId", replicaId).Logger()
+
+	return &SharedState{
+		logger:       &lg,
+		tracker:      tracker,
+		transport:    transport,
+		replicaId:    replicaId,
+		syncInterval: syncInterval,
+		staleAfter:   staleAfter,
+		replicas:     make(map[string]*sharedSnapshot),
+	}, nil
+}
+
+func (s *SharedState) Start(ctx context.Context) {
+	go s.publishLoop(ctx)
+	go s.subscribeLoop(ctx)
+}
+
+func (s *SharedState) publishLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			payload, err := common.SonicCfg.Marshal(s.takeSnapshot(time.Now()))
+			if err != nil {
+				s.logger.Warn().Err(err).Msg("failed to serialize shared health snapshot")
+				continue
+			}
+			if err := s.transport.Publish(ctx, payload); err != nil {
+				s.logger.Debug().Err(err).Msg("failed to publish shared health snapshot")
+			}
+		}
+	}
+}
+
+func (s *SharedState) subscribeLoop(ctx context.Context) {
+	for payload := range s.transport.Subscribe(ctx) {
+		snapshot := &sharedSnapshot{}
+		if err := common.SonicCfg.Unmarshal(payload, snapshot); err != nil {
+			s.logger.Warn().Err(err).Msg("failed to parse shared health snapshot")
+			continue
+		}
+		s.apply(snapshot, time.Now())
+	}
+}
+
+func (s *SharedState) takeSnapshot(now time.Time) *sharedSnapshot {
+	snapshot := &sharedSnapshot{
+		ReplicaId: s.replicaId,
+		ProjectId: s.tracker.projectId,
+		Timestamp: now.UnixNano(),
+	}
+
+	s.tracker.metrics.Range(func(k, v interface{}) bool {
+		parts := strings.SplitN(k.(string), common.KeySeparator, 3)
+		if len(parts) != 3 || parts[0] == "*" {
+			return true
+		}
+		mt := v.(*TrackedMetrics)
+		entry := &sharedMetricsEntry{
+			Upstream:  parts[0],
+			Network:   parts[1],
+			Method:    parts[2],
+			Requests:  mt.RequestsTotal.Load(),
+			Errors:    mt.ErrorsTotal.Load(),
+			Throttled: mt.RemoteRateLimitedTotal.Load() + mt.SelfRateLimitedTotal.Load(),
+			Cordoned:  mt.Cordoned.Load() || mt.ProbeCordoned.Load(),
+		}
+		if until := mt.CordonedUntil.Load(); until > now.UnixNano() {
+			entry.CordonedUntil = until
+		}
+		if entry.Cordoned || entry.CordonedUntil > 0 {
+			if reason, ok := mt.CordonedReason.Load().(string); ok {
+				entry.CordonedReason = reason
+			}
+		}
+		if entry.Requests > 0 || entry.Cordoned || entry.CordonedUntil > 0 {
+			snapshot.Metrics = append(snapshot.Metrics, entry)
+		}
+		return true
+	})
+
+	s.tracker.metadata.Range(func(k, v interface{}) bool {
+		parts := strings.SplitN(k.(string), common.KeySeparator, 3)
+		if len(parts) != 3 || parts[0] == "*" {
+			return true
+		}
+		md := v.(*NetworkMetadata)
+		entry := &sharedBlocksEntry{
+			Upstream:       parts[0],
+			Network:        parts[1],
+			LatestBlock:    md.evmLatestBlockNumber.Load(),
+			FinalizedBlock: md.evmFinalizedBlockNumber.Load(),
+		}
+		if entry.LatestBlock > 0 || entry.FinalizedBlock > 0 {
+			snapshot.Blocks = append(snapshot.Blocks, entry)
+		}
+		return true
+	})
+
+	return snapshot
+}
+
+func (s *SharedState) apply(snapshot *sharedSnapshot, now time.Time) {
+	if snapshot.ProjectId != s.tracker.projectId || snapshot.ReplicaId == s.replicaId {
+		return
+	}
+	expiresAt := snapshot.Timestamp + s.staleAfter.Nanoseconds()
+	if expiresAt <= now.UnixNano() {
+		s.logger.Debug().Str("fromReplica", snapshot.ReplicaId).Msg("ignoring stale shared health snapshot")
+		return
+	}
+
+	for _, e := range snapshot.Metrics {
+		if e.Cordoned || e.CordonedUntil > 0 {
+			// Remote cordons are never permanent locally, they expire unless the other replica keeps reporting them,
+			// and they are kept apart from local cordons so that replicas do not keep re-publishing each other's.
+			until := expiresAt
+			if e.CordonedUntil > 0 && e.CordonedUntil < until {
+				until = e.CordonedUntil
+			}
+			reason := fmt.Sprintf("cordoned by replica %s: %s", snapshot.ReplicaId, e.CordonedReason)
+			s.tracker.CordonUntilRemote(e.Upstream, e.Network, e.Method, time.Unix(0, until), reason)
+		}
+	}
+	for _, e := range snapshot.Blocks {
+		if e.LatestBlock > 0 {
+			s.tracker.SetLatestBlockNumber(e.Upstream, e.Network, e.LatestBlock)
+		}
+		if e.FinalizedBlock > 0 {
+			s.tracker.SetFinalizedBlockNumber(e.Upstream, e.Network, e.FinalizedBlock)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replicas[snapshot.ReplicaId] = snapshot
+
+	// Re-aggregate counters of all fresh replicas, so each key reflects the sum across the fleet.
+	aggregated := make(map[string]*remoteMetrics)
+	for id, rs := range s.replicas {
+		rsExpiresAt := rs.Timestamp + s.staleAfter.Nanoseconds()
+		if rsExpiresAt <= now.UnixNano() {
+			delete(s.replicas, id)
+			continue
+		}
+		for _, e := range rs.Metrics {
+			key := s.tracker.getKey(e.Upstream, e.Network, e.Method)
+			rm, ok := aggregated[key]
+			if !ok {
+				rm = &remoteMetrics{}
+				aggregated[key] = rm
+			}
+			rm.requests += e.Requests
+			rm.errors += e.Errors
+			rm.throttled += e.Throttled
+			if rsExpiresAt > rm.expiresAt {
+				rm.expiresAt = rsExpiresAt
+			}
+		}
+	}
+
+	s.tracker.metrics.Range(func(k, v interface{}) bool {
+		if _, ok := aggregated[k.(string)]; !ok {
+			v.(*TrackedMetrics).remote.Store(nil)
+		}
+		return true
+	})
+	for key, rm := range aggregated {
+		s.tracker.getMetrics(key).remote.Store(rm)
+	}
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSharedStateTransport struct {
+	published chan []byte
+}
+
+func (f *fakeSharedStateTransport) Publish(ctx context.Context, payload []byte) error {
+	f.published <- payload
+	return nil
+}
+
+func (f *fakeSharedStateTransport) Subscribe(ctx context.Context) <-chan []byte {
+	return f.published
+}
+
+func TestSharedState(t *testing.T) {
+	projectID := "test-project"
+	networkID := "evm:123"
+	cfg := &common.SharedStateConfig{SyncInterval: "1s", StaleAfter: "10s"}
+
+	newReplica := func(t *testing.T) (*Tracker, *SharedState) {
+		tracker := NewTracker(projectID, time.Minute)
+		ss, err := NewSharedState(&log.Logger, tracker, &fakeSharedStateTransport{published: make(chan []byte, 1)}, cfg)
+		assert.NoError(t, err)
+		return tracker, ss
+	}
+
+	t.Run("RemoteErrorsAffectErrorRateUntilStale", func(t *testing.T) {
+		trackerA, ssA := newReplica(t)
+		trackerB, ssB := newReplica(t)
+
+		simulateRequestMetrics(trackerA, networkID, "ups1", "method1", 100, 90)
+		simulateRequestMetrics(trackerB, networkID, "ups1", "method1", 100, 0)
+
+		now := time.Now()
+		ssB.apply(ssA.takeSnapshot(now), now)
+
+		metrics := trackerB.GetUpstreamMethodMetrics("ups1", networkID, "method1")
+		assert.Equal(t, int64(100), metrics.RequestsTotal.Load(), "local counters must not include remote values")
+		assert.InDelta(t, 0.45, metrics.ErrorRate(), 0.001)
+
+		// Once the remote signal is stale only local data is considered
+		metrics.remote.Load().expiresAt = now.Add(-time.Second).UnixNano()
+		assert.Equal(t, float64(0), metrics.ErrorRate())
+	})
+
+	t.Run("RemoteCordonIsTemporary", func(t *testing.T) {
+		trackerA, ssA := newReplica(t)
+		trackerB, ssB := newReplica(t)
+
+		trackerA.Cordon("ups1", networkID, "*", "too many errors")
+
+		now := time.Now()
+		ssB.apply(ssA.takeSnapshot(now), now)
+		assert.True(t, trackerB.IsCordoned("ups1", networkID, "method1"))
+
+		// Remote cordons are not published again, otherwise replicas would keep each other cordoned
+		snapshotB := ssB.takeSnapshot(now)
+		for _, e := range snapshotB.Metrics {
+			assert.False(t, e.Cordoned || e.CordonedUntil > 0, "remote cordon must not be republished")
+		}
+		trackerA.Uncordon("ups1", networkID, "*")
+		ssA.apply(snapshotB, now)
+		assert.False(t, trackerA.IsCordoned("ups1", networkID, "method1"))
+
+		past := now.Add(-20 * time.Second)
+		trackerC, ssC := newReplica(t)
+		ssC.apply(ssA.takeSnapshot(past), now)
+		assert.False(t, trackerC.IsCordoned("ups1", networkID, "method1"), "stale cordons must be ignored")
+	})
+
+	t.Run("IgnoresOwnAndOtherProjectSnapshots", func(t *testing.T) {
+		tracker, ss := newReplica(t)
+		simulateRequestMetrics(tracker, networkID, "ups1", "method1", 10, 10)
+
+		now := time.Now()
+		snapshot := ss.takeSnapshot(now)
+		ss.apply(snapshot, now)
+		assert.Nil(t, tracker.GetUpstreamMethodMetrics("ups1", networkID, "method1").remote.Load())
+
+		otherTracker, otherSs := newReplica(t)
+		snapshot.ReplicaId = "other"
+		snapshot.ProjectId = "other-project"
+		otherSs.apply(snapshot, now)
+		assert.Nil(t, otherTracker.GetUpstreamMethodMetrics("ups1", networkID, "method1").remote.Load())
+	})
+
+	t.Run("SharesLatestBlockNumbers", func(t *testing.T) {
+		trackerA, ssA := newReplica(t)
+		trackerB, ssB := newReplica(t)
+
+		trackerA.SetLatestBlockNumber("ups1", networkID, 1000)
+		trackerB.SetLatestBlockNumber("ups1", networkID, 900)
+
+		now := time.Now()
+		ssB.apply(ssA.takeSnapshot(now), now)
+		assert.Equal(t, int64(1000), trackerB.getMetadata(trackerB.getKey("ups1", networkID, "*")).evmLatestBlockNumber.Load())
+	})
+}
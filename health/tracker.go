@@ -39,20 +39,57 @@ type TrackedMetrics struct {
 	FinalizationLag        atomic.Int64     `json:"finalizationLag"`
 	Cordoned               atomic.Bool      `json:"cordoned"`
 	CordonedReason         atomic.Value     `json:"cordonedReason"`
+	CordonedUntil          atomic.Int64     `json:"cordonedUntil"`
+	ProbeCordoned          atomic.Bool      `json:"probeCordoned"`
+	InFlightRequests       atomic.Int64     `json:"inFlightRequests"`
+
+	// remote holds counters reported by other replicas (when shared state is enabled),
+	// they are only taken into account until they become stale.
+	remote atomic.Pointer[remoteMetrics]
+	// remoteCordonedUntil is a cordon reported by another replica, kept apart from local cordons
+	// so that it is never published again by this replica.
+	remoteCordonedUntil  atomic.Int64
+	remoteCordonedReason atomic.Value
+}
+
+type remoteMetrics struct {
+	requests  int64
+	errors    int64
+	throttled int64
+	expiresAt int64
+}
+
+func (m *TrackedMetrics) freshRemote() *remoteMetrics {
+	rm := m.remote.Load()
+	if rm == nil || time.Now().UnixNano() >= rm.expiresAt {
+		return nil
+	}
+	return rm
 }
 
 func (m *TrackedMetrics) ErrorRate() float64 {
-	if m.RequestsTotal.Load() == 0 {
+	requests, errors := m.RequestsTotal.Load(), m.ErrorsTotal.Load()
+	if rm := m.freshRemote(); rm != nil {
+		requests += rm.requests
+		errors += rm.errors
+	}
+	if requests == 0 {
 		return 0
 	}
-	return float64(m.ErrorsTotal.Load()) / float64(m.RequestsTotal.Load())
+	return float64(errors) / float64(requests)
 }
 
 func (m *TrackedMetrics) ThrottledRate() float64 {
-	if m.RequestsTotal.Load() == 0 {
+	requests := m.RequestsTotal.Load()
+	throttled := m.RemoteRateLimitedTotal.Load() + m.SelfRateLimitedTotal.Load()
+	if rm := m.freshRemote(); rm != nil {
+		requests += rm.requests
+		throttled += rm.throttled
+	}
+	if requests == 0 {
 		return 0
 	}
-	return (float64(m.RemoteRateLimitedTotal.Load()) + float64(m.SelfRateLimitedTotal.Load())) / float64(m.RequestsTotal.Load())
+	return float64(throttled) / float64(requests)
 }
 
 func (m *TrackedMetrics) MarshalJSON() ([]byte, error) {
@@ -66,6 +103,11 @@ func (m *TrackedMetrics) MarshalJSON() ([]byte, error) {
 		"finalizationLag":        m.FinalizationLag.Load(),
 		"cordoned":               m.Cordoned.Load(),
 		"cordonedReason":         m.CordonedReason.Load(),
+		"cordonedUntil":          m.CordonedUntil.Load(),
+		"probeCordoned":          m.ProbeCordoned.Load(),
+		"remoteCordonedUntil":    m.remoteCordonedUntil.Load(),
+		"remoteCordonedReason":   m.remoteCordonedReason.Load(),
+		"inFlightRequests":       m.InFlightRequests.Load(),
 	})
 }
 
@@ -156,9 +198,80 @@ func (t *Tracker) Cordon(ups, network, method, reason string) {
 func (t *Tracker) Uncordon(ups, network, method string) {
 	metrics := t.getMetrics(t.getKey(ups, network, method))
 	metrics.Cordoned.Store(false)
+	t.resetCordonedGauge(metrics, ups, network, method)
+}
+
+// CordonByProbe disables routing to an upstream that is failing its health probes. It is kept apart from
+// Cordon so that the prober and the selection policy do not lift each other's cordons.
+func (t *Tracker) CordonByProbe(ups, network, method, reason string) {
+	log.Debug().Str("upstream", ups).Str("network", network).Str("method", method).Str("reason", reason).Msg("cordoning upstream due to failed health probes")
+
+	metrics := t.getMetrics(t.getKey(ups, network, method))
+	metrics.ProbeCordoned.Store(true)
+	metrics.CordonedReason.Store(reason)
+	MetricUpstreamCordoned.WithLabelValues(t.projectId, network, ups, method).Set(1)
+}
+
+// UncordonProbe lifts only the cordon applied by CordonByProbe, other cordons stay in place.
+func (t *Tracker) UncordonProbe(ups, network, method string) {
+	metrics := t.getMetrics(t.getKey(ups, network, method))
+	metrics.ProbeCordoned.Store(false)
+	t.resetCordonedGauge(metrics, ups, network, method)
+}
+
+// resetCordonedGauge clears the cordoned gauge unless the upstream is still cordoned for another reason.
+// Temporary cordons that have already expired (but not yet cleared by isTemporarilyCordoned) do not count.
+func (t *Tracker) resetCordonedGauge(metrics *TrackedMetrics, ups, network, method string) {
+	if metrics.Cordoned.Load() || metrics.ProbeCordoned.Load() {
+		return
+	}
+	now := time.Now().UnixNano()
+	if metrics.CordonedUntil.Load() > now || metrics.remoteCordonedUntil.Load() > now {
+		return
+	}
 	MetricUpstreamCordoned.WithLabelValues(t.projectId, network, ups, method).Set(0)
 }
 
+// CordonUntil temporarily disables routing to an upstream (for a network/method) until the given time,
+// for example when the upstream tells us via Retry-After when its rate limit resets.
+// It is independent of Cordon/Uncordon so that it does not interfere with selection policy decisions.
+func (t *Tracker) CordonUntil(ups, network, method string, until time.Time, reason string) {
+	log.Debug().Str("upstream", ups).Str("network", network).Str("method", method).Str("reason", reason).Time("until", until).Msg("temporarily cordoning upstream to disable routing")
+
+	metrics := t.getMetrics(t.getKey(ups, network, method))
+	for {
+		current := metrics.CordonedUntil.Load()
+		if current >= until.UnixNano() {
+			return
+		}
+		if metrics.CordonedUntil.CompareAndSwap(current, until.UnixNano()) {
+			break
+		}
+	}
+	// Reason of a manual cordon (e.g. by selection policy or health probe) is kept since it outlives this one
+	if !metrics.Cordoned.Load() && !metrics.ProbeCordoned.Load() {
+		metrics.CordonedReason.Store(reason)
+	}
+	MetricUpstreamCordoned.WithLabelValues(t.projectId, network, ups, method).Set(1)
+}
+
+// CordonUntilRemote applies a cordon reported by another replica until the given time. Unlike CordonUntil
+// it is only used for routing locally and is not part of the signals this replica shares.
+func (t *Tracker) CordonUntilRemote(ups, network, method string, until time.Time, reason string) {
+	metrics := t.getMetrics(t.getKey(ups, network, method))
+	for {
+		current := metrics.remoteCordonedUntil.Load()
+		if current >= until.UnixNano() {
+			return
+		}
+		if metrics.remoteCordonedUntil.CompareAndSwap(current, until.UnixNano()) {
+			break
+		}
+	}
+	metrics.remoteCordonedReason.Store(reason)
+	MetricUpstreamCordoned.WithLabelValues(t.projectId, network, ups, method).Set(1)
+}
+
 func (t *Tracker) RecordUpstreamRequest(ups, network, method string) {
 	metricsList := make([]*TrackedMetrics, 0)
 	for _, key := range t.getKeys(ups, network, method) {
@@ -172,6 +285,24 @@ func (t *Tracker) RecordUpstreamRequest(ups, network, method string) {
 	MetricUpstreamRequestTotal.WithLabelValues(t.projectId, network, ups, method).Inc()
 }
 
+// RecordUpstreamInFlight marks a request as in-flight towards the upstream until the returned function is called.
+func (t *Tracker) RecordUpstreamInFlight(ups, network, method string) func() {
+	metricsList := make([]*TrackedMetrics, 0)
+	for _, key := range t.getKeys(ups, network, method) {
+		metricsList = append(metricsList, t.getMetrics(key))
+	}
+
+	for _, metrics := range metricsList {
+		metrics.InFlightRequests.Add(1)
+	}
+
+	return func() {
+		for _, metrics := range metricsList {
+			metrics.InFlightRequests.Add(-1)
+		}
+	}
+}
+
 func (t *Tracker) RecordUpstreamDurationStart(ups, network, method string) *Timer {
 	return &Timer{
 		start:   time.Now(),
@@ -377,10 +508,50 @@ func (t *Tracker) GetUpstreamMetrics(upsId string) map[string]*TrackedMetrics {
 
 func (t *Tracker) IsCordoned(ups, network, method string) bool {
 	if method != "*" {
-		if t.getMetrics(t.getKey(ups, network, "*")).Cordoned.Load() {
+		if t.isCordoned(ups, network, "*") {
+			return true
+		}
+	}
+
+	return t.isCordoned(ups, network, method)
+}
+
+func (t *Tracker) isCordoned(ups, network, method string) bool {
+	metrics := t.getMetrics(t.getKey(ups, network, method))
+	if metrics.Cordoned.Load() || metrics.ProbeCordoned.Load() {
+		return true
+	}
+
+	return t.isTemporarilyCordoned(metrics, ups, network, method)
+}
+
+// IsTemporarilyCordoned only checks cordons with an expiry (e.g. until a rate limit resets, or reported by
+// another replica) so they can be enforced when forwarding, without waiting for the next scores refresh.
+func (t *Tracker) IsTemporarilyCordoned(ups, network, method string) bool {
+	if method != "*" {
+		if t.isTemporarilyCordoned(t.getMetrics(t.getKey(ups, network, "*")), ups, network, "*") {
 			return true
 		}
 	}
 
-	return t.getMetrics(t.getKey(ups, network, method)).Cordoned.Load()
+	return t.isTemporarilyCordoned(t.getMetrics(t.getKey(ups, network, method)), ups, network, method)
+}
+
+func (t *Tracker) isTemporarilyCordoned(metrics *TrackedMetrics, ups, network, method string) bool {
+	now := time.Now().UnixNano()
+	until, remoteUntil := metrics.CordonedUntil.Load(), metrics.remoteCordonedUntil.Load()
+	if until == 0 && remoteUntil == 0 {
+		return false
+	}
+	if now < until || now < remoteUntil {
+		return true
+	}
+
+	// Temporary cordons have expired, so the upstream is automatically re-included.
+	expired := metrics.CordonedUntil.CompareAndSwap(until, 0)
+	expired = metrics.remoteCordonedUntil.CompareAndSwap(remoteUntil, 0) && expired
+	if expired {
+		t.resetCordonedGauge(metrics, ups, network, method)
+	}
+	return false
 }
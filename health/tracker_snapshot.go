@@ -0,0 +1,100 @@
+package health
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/erpc/erpc/common"
+)
+
+// TrackerSnapshot is a serializable copy of tracked metrics and cordons, used to restore
+// scoring state after a restart instead of starting with empty windows.
+type TrackerSnapshot struct {
+	Metrics []*TrackedMetricsSnapshot `json:"metrics"`
+}
+
+type TrackedMetricsSnapshot struct {
+	Upstream          string  `json:"upstream"`
+	Network           string  `json:"network"`
+	Method            string  `json:"method"`
+	Requests          int64   `json:"requests,omitempty"`
+	Errors            int64   `json:"errors,omitempty"`
+	SelfRateLimited   int64   `json:"selfRateLimited,omitempty"`
+	RemoteRateLimited int64   `json:"remoteRateLimited,omitempty"`
+	LatencyP90        float64 `json:"latencyP90,omitempty"`
+	Cordoned          bool    `json:"cordoned,omitempty"`
+	CordonedUntil     int64   `json:"cordonedUntil,omitempty"`
+	CordonedReason    string  `json:"cordonedReason,omitempty"`
+}
+
+func (t *Tracker) Snapshot() *TrackerSnapshot {
+	now := time.Now().UnixNano()
+	snapshot := &TrackerSnapshot{}
+
+	t.metrics.Range(func(k, v interface{}) bool {
+		parts := strings.SplitN(k.(string), common.KeySeparator, 3)
+		if len(parts) != 3 || parts[0] == "*" {
+			return true
+		}
+		mt := v.(*TrackedMetrics)
+		entry := &TrackedMetricsSnapshot{
+			Upstream:          parts[0],
+			Network:           parts[1],
+			Method:            parts[2],
+			Requests:          mt.RequestsTotal.Load(),
+			Errors:            mt.ErrorsTotal.Load(),
+			SelfRateLimited:   mt.SelfRateLimitedTotal.Load(),
+			RemoteRateLimited: mt.RemoteRateLimitedTotal.Load(),
+			LatencyP90:        mt.LatencySecs.P90(),
+			Cordoned:          mt.Cordoned.Load() || mt.ProbeCordoned.Load(),
+		}
+		if until := mt.CordonedUntil.Load(); until > now {
+			entry.CordonedUntil = until
+		}
+		if entry.Cordoned || entry.CordonedUntil > 0 {
+			if reason, ok := mt.CordonedReason.Load().(string); ok {
+				entry.CordonedReason = reason
+			}
+		}
+		if entry.Requests > 0 || entry.Cordoned || entry.CordonedUntil > 0 {
+			snapshot.Metrics = append(snapshot.Metrics, entry)
+		}
+		return true
+	})
+
+	return snapshot
+}
+
+// Restore adds the counters of a previously taken snapshot on top of current metrics, multiplied by
+// the decay factor (between 0 and 1) so that older snapshots have less influence on scoring.
+// Persisted p90 latency is added as up to latencySamples samples, also scaled by the decay factor.
+// Cordons that were not time-bound are restored as temporary cordons for cordonFor duration,
+// so that the components that normally uncordon (e.g. selection policy) get a chance to re-evaluate.
+func (t *Tracker) Restore(snapshot *TrackerSnapshot, decay float64, cordonFor time.Duration, latencySamples int) {
+	if snapshot == nil {
+		return
+	}
+	decay = math.Max(0, math.Min(1, decay))
+	now := time.Now()
+
+	for _, e := range snapshot.Metrics {
+		mt := t.getMetrics(t.getKey(e.Upstream, e.Network, e.Method))
+		mt.RequestsTotal.Add(int64(math.Round(float64(e.Requests) * decay)))
+		mt.ErrorsTotal.Add(int64(math.Round(float64(e.Errors) * decay)))
+		mt.SelfRateLimitedTotal.Add(int64(math.Round(float64(e.SelfRateLimited) * decay)))
+		mt.RemoteRateLimitedTotal.Add(int64(math.Round(float64(e.RemoteRateLimited) * decay)))
+		if e.LatencyP90 > 0 {
+			for i := int(math.Round(float64(latencySamples) * decay)); i > 0; i-- {
+				mt.LatencySecs.Add(e.LatencyP90)
+			}
+		}
+
+		reason := "restored from snapshot: " + e.CordonedReason
+		if e.CordonedUntil > now.UnixNano() {
+			t.CordonUntil(e.Upstream, e.Network, e.Method, time.Unix(0, e.CordonedUntil), reason)
+		} else if e.Cordoned && cordonFor > 0 {
+			t.CordonUntil(e.Upstream, e.Network, e.Method, now.Add(cordonFor), reason)
+		}
+	}
+}
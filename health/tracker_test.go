@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -277,6 +278,80 @@ func TestTracker(t *testing.T) {
 		assert.GreaterOrEqual(t, metrics1.LatencySecs.P90(), 0.02)
 		assert.LessOrEqual(t, metrics1.LatencySecs.P90(), 0.03)
 	})
+
+	t.Run("CordonUntilExpiresAutomatically", func(t *testing.T) {
+		tracker := NewTracker(projectID, windowSize)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker.Bootstrap(ctx)
+
+		ups := newFakeUpstream("a")
+		tracker.CordonUntil(ups.Config().Id, networkID, "method1", time.Now().Add(100*time.Millisecond), "rate limited")
+
+		assert.True(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"))
+		assert.False(t, tracker.IsCordoned(ups.Config().Id, networkID, "method2"))
+
+		time.Sleep(150 * time.Millisecond)
+
+		assert.False(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"))
+	})
+
+	t.Run("CordonUntilDoesNotOverrideManualCordon", func(t *testing.T) {
+		tracker := NewTracker(projectID, windowSize)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker.Bootstrap(ctx)
+
+		ups := newFakeUpstream("a")
+		tracker.Cordon(ups.Config().Id, networkID, "method1", "excluded by selection policy")
+		tracker.CordonUntil(ups.Config().Id, networkID, "method1", time.Now().Add(50*time.Millisecond), "rate limited")
+
+		assert.Equal(t, "excluded by selection policy", tracker.GetUpstreamMethodMetrics(ups.Config().Id, networkID, "method1").CordonedReason.Load())
+		assert.True(t, tracker.IsTemporarilyCordoned(ups.Config().Id, networkID, "method1"))
+
+		time.Sleep(100 * time.Millisecond)
+
+		assert.False(t, tracker.IsTemporarilyCordoned(ups.Config().Id, networkID, "method1"))
+		assert.True(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"))
+		tracker.Uncordon(ups.Config().Id, networkID, "method1")
+		assert.False(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"))
+	})
+
+	t.Run("UncordonResetsGaugeWhenTemporaryCordonExpired", func(t *testing.T) {
+		tracker := NewTracker(projectID, windowSize)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker.Bootstrap(ctx)
+
+		ups := newFakeUpstream("a")
+		tracker.CordonUntil(ups.Config().Id, networkID, "method1", time.Now().Add(10*time.Millisecond), "rate limited")
+		tracker.Cordon(ups.Config().Id, networkID, "method1", "excluded by selection policy")
+		time.Sleep(20 * time.Millisecond)
+
+		tracker.Uncordon(ups.Config().Id, networkID, "method1")
+		assert.Equal(t, float64(0), testutil.ToFloat64(MetricUpstreamCordoned.WithLabelValues(projectID, networkID, ups.Config().Id, "method1")))
+	})
+
+	t.Run("ProbeCordonIsIndependentOfManualCordon", func(t *testing.T) {
+		tracker := NewTracker(projectID, windowSize)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tracker.Bootstrap(ctx)
+
+		ups := newFakeUpstream("a")
+		tracker.Cordon(ups.Config().Id, networkID, "*", "excluded by selection policy")
+		tracker.CordonByProbe(ups.Config().Id, networkID, "*", "failed health probe eth_blockNumber")
+
+		tracker.UncordonProbe(ups.Config().Id, networkID, "*")
+		assert.True(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"), "probe recovery must not lift selection policy cordon")
+
+		tracker.CordonByProbe(ups.Config().Id, networkID, "*", "failed health probe eth_blockNumber")
+		tracker.Uncordon(ups.Config().Id, networkID, "*")
+		assert.True(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"), "selection policy must not lift probe cordon")
+
+		tracker.UncordonProbe(ups.Config().Id, networkID, "*")
+		assert.False(t, tracker.IsCordoned(ups.Config().Id, networkID, "method1"))
+	})
 }
 
 type fakeUpstream struct {
@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+)
+
+// AccessLogRecord describes a single served json-rpc request (i.e. each item of a batch request has its own record).
+type AccessLogRecord struct {
+	ProjectId     string
+	NetworkId     string
+	Method        string
+	ConsumerId    string
+	Upstreams     []string
+	Attempts      int
+	Retries       int
+	Hedges        int
+	CacheHit      bool
+	Status        int
+	ErrorCode     string
+	Duration      time.Duration
+	RequestBytes  int
+	ResponseBytes int64
+	Batch         bool
+	Params        []interface{}
+}
+
+type AccessLogger struct {
+	cfg    *common.AccessLogConfig
+	writer io.Writer
+	logger zerolog.Logger
+}
+
+func NewAccessLogger(cfg *common.AccessLogConfig) (*AccessLogger, error) {
+	var w io.Writer
+	switch cfg.Output {
+	case "file":
+		rf, err := NewRotatingFile(cfg.FilePath, int64(cfg.MaxSizeMB)*1024*1024, cfg.MaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open access log file: %w", err)
+		}
+		w = rf
+	default:
+		w = os.Stdout
+	}
+
+	return &AccessLogger{
+		cfg:    cfg,
+		writer: w,
+		logger: zerolog.New(w).With().Timestamp().Logger(),
+	}, nil
+}
+
+// ShouldSample decides whether a request with the given method and status code must be logged,
+// based on the first matching sampling rule or the default sample rate.
+func (a *AccessLogger) ShouldSample(method string, status int) bool {
+	rate := 1.0
+	if a.cfg.SampleRate != nil {
+		rate = *a.cfg.SampleRate
+	}
+	st := strconv.Itoa(status)
+	for _, rule := range a.cfg.Sampling {
+		if m, _ := common.WildcardMatch(rule.Method, method); !m {
+			continue
+		}
+		if s, _ := common.WildcardMatch(rule.Status, st); !s {
+			continue
+		}
+		rate = rule.Rate
+		break
+	}
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate) // #nosec G404
+}
+
+// IncludeParams returns true when records must carry the request params.
+func (a *AccessLogger) IncludeParams() bool {
+	return a.cfg.IncludeParams
+}
+
+func (a *AccessLogger) Log(rec *AccessLogRecord) {
+	if !a.ShouldSample(rec.Method, rec.Status) {
+		return
+	}
+
+	evt := a.logger.Log().
+		Str("projectId", rec.ProjectId).
+		Str("networkId", rec.NetworkId).
+		Str("method", rec.Method).
+		Str("consumerId", rec.ConsumerId).
+		Strs("upstreams", rec.Upstreams).
+		Int("attempts", rec.Attempts).
+		Int("retries", rec.Retries).
+		Int("hedges", rec.Hedges).
+		Bool("cacheHit", rec.CacheHit).
+		Int("status", rec.Status).
+		Int64("durationMs", rec.Duration.Milliseconds()).
+		Int("requestBytes", rec.RequestBytes).
+		Int64("responseBytes", rec.ResponseBytes).
+		Bool("batch", rec.Batch)
+	if rec.ErrorCode != "" {
+		evt = evt.Str("errorCode", rec.ErrorCode)
+	}
+	if a.cfg.IncludeParams && rec.Params != nil {
+		params := rec.Params
+		for _, pattern := range a.cfg.RedactMethods {
+			if m, _ := common.WildcardMatch(pattern, rec.Method); m {
+				params = util.RedactParams(params)
+				break
+			}
+		}
+		evt = evt.Interface("params", params)
+	}
+	evt.Send()
+}
+
+func (a *AccessLogger) Close() error {
+	if c, ok := a.writer.(io.Closer); ok && a.writer != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogger(t *testing.T) {
+	newFileLogger := func(t *testing.T, cfg *common.AccessLogConfig) (*AccessLogger, string) {
+		path := filepath.Join(t.TempDir(), "access.log")
+		cfg.Enabled = true
+		cfg.Output = "file"
+		cfg.FilePath = path
+		cfg.SetDefaults()
+		al, err := NewAccessLogger(cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = al.Close() })
+		return al, path
+	}
+
+	t.Run("SamplingRulesMatchMethodAndStatus", func(t *testing.T) {
+		al, _ := newFileLogger(t, &common.AccessLogConfig{
+			SampleRate: util.Float64Ptr(1),
+			Sampling: []*common.AccessLogSamplingConfig{
+				{Method: "eth_*", Status: "5*", Rate: 1},
+				{Method: "eth_getBlock*", Rate: 0},
+			},
+		})
+
+		assert.True(t, al.ShouldSample("eth_getBlockByNumber", 503))
+		assert.False(t, al.ShouldSample("eth_getBlockByNumber", 200))
+		assert.True(t, al.ShouldSample("eth_call", 200))
+	})
+
+	t.Run("ZeroSampleRateIsKept", func(t *testing.T) {
+		al, _ := newFileLogger(t, &common.AccessLogConfig{
+			SampleRate: util.Float64Ptr(0),
+			Sampling: []*common.AccessLogSamplingConfig{
+				{Method: "*", Status: "5*", Rate: 1},
+			},
+		})
+
+		assert.False(t, al.ShouldSample("eth_call", 200))
+		assert.True(t, al.ShouldSample("eth_call", 500))
+	})
+
+	t.Run("RedactsParamsOfSensitiveMethods", func(t *testing.T) {
+		al, path := newFileLogger(t, &common.AccessLogConfig{IncludeParams: true})
+
+		al.Log(&AccessLogRecord{Method: "eth_sendRawTransaction", Status: 200, Params: []interface{}{"0xf86c0a8502540be400"}})
+		al.Log(&AccessLogRecord{Method: "eth_getBalance", Status: 200, Params: []interface{}{"0xabc", "latest"}})
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 2)
+		assert.NotContains(t, lines[0], "0xf86c0a8502540be400")
+		assert.Contains(t, lines[0], `"params":["redacted=`)
+		assert.Contains(t, lines[1], `"params":["0xabc","latest"]`)
+	})
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	rf, err := NewRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	for _, line := range []string{"first-line\n", "second-line\n", "third-line\n", "fourth-line\n"} {
+		_, err := rf.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fourth-line\n", string(current))
+	backup1, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "third-line\n", string(backup1))
+	backup2, err := os.ReadFile(path + ".2")
+	require.NoError(t, err)
+	assert.Equal(t, "second-line\n", string(backup2))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFile_KeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	// A non-empty directory in place of the first backup makes the rename fail
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "keep"), 0o755))
+
+	rf, err := NewRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("first-line\n"))
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("second-line\n"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.RemoveAll(path+".1"))
+	_, err = rf.Write([]byte("third-line\n"))
+	require.NoError(t, err)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third-line\n", string(current))
+	backup1, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "first-line\n", string(backup1))
+}
@@ -0,0 +1,247 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+)
+
+// Tracer batches ended spans and exports them to an OpenTelemetry collector using OTLP over HTTP (JSON encoding).
+type Tracer struct {
+	logger        *zerolog.Logger
+	client        *http.Client
+	endpoint      string
+	headers       map[string]string
+	serviceName   string
+	sampleRate    float64
+	batchSize     int
+	flushInterval time.Duration
+
+	queue chan *Span
+}
+
+func NewTracer(ctx context.Context, logger *zerolog.Logger, cfg *common.TracingConfig) (*Tracer, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = "/v1/traces"
+	}
+	flushInterval, err := time.ParseDuration(cfg.FlushInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing flushInterval: %w", err)
+	}
+
+	lg := logger.With().Str("component", "tracer").Logger()
+	t := &Tracer{
+		logger:        &lg,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		endpoint:      endpoint.String(),
+		headers:       cfg.Headers,
+		serviceName:   cfg.ServiceName,
+		sampleRate:    1,
+		batchSize:     cfg.BatchSize,
+		flushInterval: flushInterval,
+		queue:         make(chan *Span, cfg.BatchSize*4),
+	}
+	if cfg.SampleRate != nil {
+		t.sampleRate = *cfg.SampleRate
+	}
+	go t.run(ctx)
+
+	return t, nil
+}
+
+func (t *Tracer) shouldSample() bool {
+	return t.sampleRate >= 1 || rand.Float64() < t.sampleRate // #nosec G404
+}
+
+// enqueue never blocks the request path, spans are dropped when the exporter cannot keep up.
+func (t *Tracer) enqueue(s *Span) {
+	select {
+	case t.queue <- s:
+	default:
+		t.logger.Debug().Str("span", s.name).Msg("dropping span because export queue is full")
+	}
+}
+
+func (t *Tracer) run(ctx context.Context) {
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, t.batchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.export(ctx, batch); err != nil {
+			t.logger.Warn().Err(err).Int("spans", len(batch)).Msg("failed to export spans")
+		}
+		batch = make([]*Span, 0, t.batchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for len(t.queue) > 0 {
+				batch = append(batch, <-t.queue)
+			}
+			sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			flush(sctx)
+			cancel()
+			return
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) >= t.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+func (t *Tracer) export(ctx context.Context, spans []*Span) error {
+	payload, err := common.SonicCfg.Marshal(t.toOtlp(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector responded with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func (t *Tracer) toOtlp(spans []*Span) *otlpExportRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		os := otlpSpan{
+			TraceId:           hex.EncodeToString(s.tc.TraceId[:]),
+			SpanId:            hex.EncodeToString(s.tc.SpanId[:]),
+			Name:              s.name,
+			Kind:              int(s.kind),
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        toOtlpAttributes(s.attrs),
+		}
+		if s.parentSpanId != [8]byte{} {
+			os.ParentSpanId = hex.EncodeToString(s.parentSpanId[:])
+		}
+		if s.err != nil {
+			os.Status = &otlpStatus{Code: 2, Message: s.err.Error()}
+		}
+		s.mu.Unlock()
+		out = append(out, os)
+	}
+
+	return &otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: toOtlpAttributes([]Attribute{String("service.name", t.serviceName)}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "github.com/erpc/erpc"},
+						Spans: out,
+					},
+				},
+			},
+		},
+	}
+}
+
+func toOtlpAttributes(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]interface{}{"stringValue": val}
+		case int64:
+			// OTLP JSON encodes 64-bit integers as strings
+			v = map[string]interface{}{"intValue": strconv.FormatInt(val, 10)}
+		case bool:
+			v = map[string]interface{}{"boolValue": val}
+		case float64:
+			v = map[string]interface{}{"doubleValue": val}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprintf("%v", val)}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return kvs
+}
@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is an io.Writer appending to a file that is rotated once it reaches maxSize bytes,
+// keeping up to maxBackups previous files named <path>.1 (most recent) to <path>.<maxBackups>.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) // #nosec G302
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	var err error
+	if rf.maxBackups > 0 {
+		_ = os.Remove(fmt.Sprintf("%s.%d", rf.path, rf.maxBackups))
+		for i := rf.maxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", rf.path, i), fmt.Sprintf("%s.%d", rf.path, i+1))
+		}
+		err = os.Rename(rf.path, rf.path+".1")
+	} else {
+		err = os.Remove(rf.path)
+	}
+	// The file is reopened even when moving it away failed, so that later writes
+	// keep appending to the current file (and retry rotation) instead of a closed one.
+	if openErr := rf.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Close()
+}
@@ -0,0 +1,223 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const TraceparentHeader = "traceparent"
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// TraceContext identifies a span within a trace as defined by W3C Trace Context.
+type TraceContext struct {
+	TraceId [16]byte
+	SpanId  [8]byte
+	Sampled bool
+}
+
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceId != [16]byte{} && tc.SpanId != [8]byte{}
+}
+
+// Traceparent returns the value of the "traceparent" header for this context.
+func (tc TraceContext) Traceparent() string {
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(tc.TraceId[:]) + "-" + hex.EncodeToString(tc.SpanId[:]) + "-" + flags
+}
+
+// ParseTraceparent parses a "traceparent" header value (version-traceid-parentid-flags).
+func ParseTraceparent(value string) (TraceContext, bool) {
+	var tc TraceContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return tc, false
+	}
+	if _, err := hex.Decode(tc.TraceId[:], []byte(parts[1])); err != nil {
+		return tc, false
+	}
+	if _, err := hex.Decode(tc.SpanId[:], []byte(parts[2])); err != nil {
+		return tc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return tc, false
+	}
+	tc.Sampled = flags[0]&0x01 == 0x01
+	return tc, tc.IsValid()
+}
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a timed operation within a trace. A nil span is valid and ignores all calls,
+// so that callers do not need to check whether tracing is enabled or the trace is sampled.
+type Span struct {
+	tracer       *Tracer
+	name         string
+	kind         SpanKind
+	tc           TraceContext
+	parentSpanId [8]byte
+	start        time.Time
+
+	mu    sync.Mutex
+	end   time.Time
+	attrs []Attribute
+	err   error
+	ended bool
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed with the given error (nil errors are ignored).
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.enqueue(s)
+}
+
+type spanContextKey struct{}
+type remoteParentContextKey struct{}
+
+var globalTracer atomic.Pointer[Tracer]
+
+// SetTracer sets the tracer used by StartSpan, nil disables tracing.
+func SetTracer(t *Tracer) {
+	globalTracer.Store(t)
+}
+
+// StartSpan starts a child span of the span (or remote parent) carried by ctx, or a new trace otherwise.
+// It returns a nil span when tracing is disabled or the trace is not sampled.
+func StartSpan(ctx context.Context, kind SpanKind, name string, attrs ...Attribute) (context.Context, *Span) {
+	t := globalTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+
+	var tc TraceContext
+	var parentSpanId [8]byte
+	if parent, ok := ctx.Value(spanContextKey{}).(*Span); ok && parent != nil {
+		tc.TraceId = parent.tc.TraceId
+		tc.Sampled = parent.tc.Sampled
+		parentSpanId = parent.tc.SpanId
+	} else if remote, ok := ctx.Value(remoteParentContextKey{}).(TraceContext); ok && remote.IsValid() {
+		tc.TraceId = remote.TraceId
+		tc.Sampled = remote.Sampled
+		parentSpanId = remote.SpanId
+	} else {
+		_, _ = rand.Read(tc.TraceId[:])
+		tc.Sampled = t.shouldSample()
+	}
+	_, _ = rand.Read(tc.SpanId[:])
+
+	if !tc.Sampled {
+		// Keep propagating the trace (as not sampled) so that downstream services make the same decision
+		return context.WithValue(ctx, remoteParentContextKey{}, tc), nil
+	}
+
+	span := &Span{
+		tracer:       t,
+		name:         name,
+		kind:         kind,
+		tc:           tc,
+		parentSpanId: parentSpanId,
+		start:        time.Now(),
+		attrs:        attrs,
+	}
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// Extract returns a context carrying the trace context of incoming "traceparent" header (if any and valid).
+func Extract(ctx context.Context, headers http.Header) context.Context {
+	if tc, ok := ParseTraceparent(headers.Get(TraceparentHeader)); ok {
+		return context.WithValue(ctx, remoteParentContextKey{}, tc)
+	}
+	return ctx
+}
+
+// Inject sets the "traceparent" header based on the current span (or remote parent) carried by ctx.
+func Inject(ctx context.Context, headers http.Header) {
+	if tc, ok := traceContextOf(ctx); ok {
+		headers.Set(TraceparentHeader, tc.Traceparent())
+	}
+}
+
+// WithTraceFrom returns dst carrying the span (or remote parent) of src, useful for background work
+// (e.g. cache writes) that must outlive the request context but still belong to the same trace.
+func WithTraceFrom(dst context.Context, src context.Context) context.Context {
+	if span, ok := src.Value(spanContextKey{}).(*Span); ok && span != nil {
+		return context.WithValue(dst, spanContextKey{}, span)
+	}
+	if remote, ok := src.Value(remoteParentContextKey{}).(TraceContext); ok {
+		return context.WithValue(dst, remoteParentContextKey{}, remote)
+	}
+	return dst
+}
+
+func traceContextOf(ctx context.Context) (TraceContext, bool) {
+	if span, ok := ctx.Value(spanContextKey{}).(*Span); ok && span != nil {
+		return span.tc, true
+	}
+	if remote, ok := ctx.Value(remoteParentContextKey{}).(TraceContext); ok && remote.IsValid() {
+		return remote, true
+	}
+	return TraceContext{}, false
+}
@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceparent(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		tc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.True(t, ok)
+		assert.True(t, tc.Sampled)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.Traceparent())
+	})
+
+	t.Run("RejectsInvalidValues", func(t *testing.T) {
+		for _, v := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+		} {
+			_, ok := ParseTraceparent(v)
+			assert.False(t, ok, v)
+		}
+	})
+}
+
+func TestStartSpan(t *testing.T) {
+	logger := log.Logger
+	tracer := &Tracer{logger: &logger, sampleRate: 1, queue: make(chan *Span, 10)}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	t.Run("ContinuesRemoteTrace", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		ctx := Extract(context.Background(), headers)
+
+		ctx, parent := StartSpan(ctx, SpanKindServer, "parent")
+		require.NotNil(t, parent)
+		_, child := StartSpan(ctx, SpanKindInternal, "child")
+		require.NotNil(t, child)
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", parent.tc.Traceparent()[3:35])
+		assert.Equal(t, parent.tc.TraceId, child.tc.TraceId)
+		assert.Equal(t, parent.tc.SpanId, child.parentSpanId)
+
+		out := http.Header{}
+		Inject(ctx, out)
+		assert.Equal(t, parent.tc.Traceparent(), out.Get(TraceparentHeader))
+
+		child.End()
+		child.End()
+		parent.End()
+		assert.Len(t, tracer.queue, 2)
+	})
+
+	t.Run("PropagatesUnsampledTrace", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		ctx, span := StartSpan(Extract(context.Background(), headers), SpanKindServer, "unsampled")
+		assert.Nil(t, span)
+		span.End()
+
+		out := http.Header{}
+		Inject(ctx, out)
+		assert.Contains(t, out.Get(TraceparentHeader), "4bf92f3577b34da6a3ce929d0e0e4736")
+		assert.True(t, strings.HasSuffix(out.Get(TraceparentHeader), "-00"))
+	})
+}
+
+func TestNewTracer_SampleRate(t *testing.T) {
+	logger := log.Logger
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newTracer := func(t *testing.T, sampleRate *float64) *Tracer {
+		cfg := &common.TracingConfig{Enabled: true, Endpoint: "http://otel.localhost:4318", SampleRate: sampleRate}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		tracer, err := NewTracer(ctx, &logger, cfg)
+		require.NoError(t, err)
+		return tracer
+	}
+
+	assert.Equal(t, float64(1), newTracer(t, nil).sampleRate)
+
+	tracer := newTracer(t, util.Float64Ptr(0))
+	assert.Equal(t, float64(0), tracer.sampleRate)
+	SetTracer(tracer)
+	defer SetTracer(nil)
+	_, span := StartSpan(context.Background(), SpanKindServer, "root")
+	assert.Nil(t, span, "new traces must not be sampled when sampleRate is 0")
+}
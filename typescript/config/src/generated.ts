@@ -38,6 +38,7 @@ export interface Config {
   projects: (ProjectConfig | undefined)[];
   rateLimiters?: RateLimiterConfig;
   metrics?: MetricsConfig;
+  tracing?: TracingConfig;
 }
 export interface ServerConfig {
   listenV4?: boolean;
@@ -51,6 +52,49 @@ export interface ServerConfig {
   enableGzip?: boolean;
   tls?: TLSConfig;
   aliasing?: AliasingConfig;
+  accessLog?: AccessLogConfig;
+  /**
+   * DebugHeaders controls whether responses carry X-ERPC-* headers exposing routing decisions
+   * (upstream, cache, attempts, retries, hedges and duration), and whether batch items can carry
+   * a "debug" field when requested via the debug directive.
+   */
+  debugHeaders?: boolean;
+}
+/**
+ * AccessLogConfig enables writing one structured (JSON) record per served request to stdout or a file.
+ */
+export interface AccessLogConfig {
+  enabled: boolean;
+  /**
+   * Output is either "stdout" or "file".
+   */
+  output?: "stdout" | "file";
+  /**
+   * FilePath is required when output is "file", the file is rotated once it reaches maxSizeMB.
+   */
+  filePath?: string;
+  maxSizeMB?: number /* int */;
+  maxBackups?: number /* int */;
+  /**
+   * SampleRate (between 0 and 1) applies to requests that do not match any of the sampling rules,
+   * 0 disables logging of such requests.
+   */
+  sampleRate?: number /* float64 */;
+  sampling?: (AccessLogSamplingConfig | undefined)[];
+  /**
+   * IncludeParams adds request params to each record, params of methods matching redactMethods are redacted.
+   */
+  includeParams?: boolean;
+  redactMethods?: string[];
+}
+/**
+ * AccessLogSamplingConfig sets the sample rate of requests matching a method and response status code,
+ * both of which can use "*" as wildcard (e.g. "eth_get*" and "5*"). The first matching rule applies.
+ */
+export interface AccessLogSamplingConfig {
+  method?: string;
+  status?: string;
+  rate: number /* float64 */;
 }
 export interface AdminConfig {
   auth?: AuthConfig;
@@ -152,12 +196,64 @@ export interface ProjectConfig {
   networks?: (NetworkConfig | undefined)[];
   rateLimitBudget?: string;
   healthCheck?: HealthCheckConfig;
+  capture?: CaptureConfig;
+  metering?: MeteringConfig;
+}
+/**
+ * MeteringConfig aggregates usage per consumer (the identity resolved by auth strategies) per minute in memory,
+ * and periodically flushes completed minutes to a postgresql table or appends them to a newline-delimited JSON file.
+ */
+export interface MeteringConfig {
+  file?: string;
+  connector?: ConnectorConfig;
+  ttl?: Duration;
+  flushInterval?: Duration;
+  defaultComputeUnits?: number /* int */;
+  computeUnits?: (MethodComputeUnitsConfig | undefined)[];
+}
+/**
+ * MethodComputeUnitsConfig sets how many compute units a request counts for when its method matches
+ * the given pattern (can use "*" as wildcard). The first matching entry applies.
+ */
+export interface MethodComputeUnitsConfig {
+  method: string;
+  units: number /* int */;
+}
+/**
+ * CaptureConfig stores requests along with the raw response (or error) of each upstream attempt and the final
+ * response, for requests matching one of the sampling rules (or sent with the capture directive when allowed).
+ * Captures are appended to a local JSONL file which can be replayed, or written to a connector (keyed by capture id).
+ */
+export interface CaptureConfig {
+  file?: string;
+  connector?: ConnectorConfig;
+  ttl?: Duration;
+  sampling?: (CaptureSamplingConfig | undefined)[];
+  /**
+   * AllowDirective lets clients capture their requests with the capture directive.
+   */
+  allowDirective?: boolean;
+  /**
+   * Params of methods matching any of these patterns are redacted in the captured request.
+   */
+  redactMethods?: string[];
+}
+/**
+ * CaptureSamplingConfig captures a ratio of requests (between 0 and 1) of networks and methods matching
+ * the given patterns, both of which can use "*" as wildcard.
+ */
+export interface CaptureSamplingConfig {
+  network?: string;
+  method?: string;
+  rate: number /* float64 */;
 }
 export interface NetworkDefaults {
   rateLimitBudget?: string;
   failsafe?: FailsafeConfig;
   selectionPolicy?: SelectionPolicyConfig;
   directiveDefaults?: DirectiveDefaultsConfig;
+  upstreamSelection?: UpstreamSelectionConfig;
+  upstreamGroups?: (UpstreamGroupConfig | undefined)[];
 }
 export interface CORSConfig {
   allowedOrigins: string[];
@@ -182,6 +278,30 @@ export interface UpstreamConfig {
   rateLimitBudget?: string;
   rateLimitAutoTune?: RateLimitAutoTuneConfig;
   routing?: RoutingConfig;
+  maxConcurrency?: number /* int */;
+  maxConcurrencyQueueTimeout?: Duration;
+  healthProbes?: (HealthProbeConfig | undefined)[];
+}
+/**
+ * HealthProbeConfig defines an active probe periodically sent to the upstream independent of user traffic.
+ */
+export interface HealthProbeConfig {
+  method: string;
+  params?: any[];
+  interval?: Duration;
+  timeout?: Duration;
+  expect?: HealthProbeExpectConfig;
+  failureThreshold?: number /* int */;
+  successThreshold?: number /* int */;
+  cordonMethod?: string;
+}
+/**
+ * HealthProbeExpectConfig defines how a probe result is matched, all defined conditions must pass.
+ */
+export interface HealthProbeExpectConfig {
+  notEmpty?: boolean;
+  equals?: any;
+  pattern?: string;
 }
 export interface RoutingConfig {
   scoreMultipliers: (ScoreMultiplierConfig | undefined)[];
@@ -196,6 +316,7 @@ export interface ScoreMultiplierConfig {
   throttledRate: number /* float64 */;
   blockHeadLag: number /* float64 */;
   finalizationLag: number /* float64 */;
+  inFlight: number /* float64 */;
 }
 export type Alias = UpstreamConfig;
 export interface RateLimitAutoTuneConfig {
@@ -218,12 +339,32 @@ export interface EvmUpstreamConfig {
   nodeType?: EvmNodeType;
   statePollerInterval?: string;
   maxAvailableRecentBlocks?: number /* int64 */;
+  maxBlockHeadLag?: number /* int64 */;
+  maxFinalizationLag?: number /* int64 */;
 }
 export interface FailsafeConfig {
   retry?: RetryPolicyConfig;
   circuitBreaker?: CircuitBreakerPolicyConfig;
   timeout?: TimeoutPolicyConfig;
   hedge?: HedgePolicyConfig;
+  methodOverrides?: (FailsafeMethodOverrideConfig | undefined)[];
+  retryBudget?: RetryBudgetConfig;
+}
+/**
+ * RetryBudgetConfig is a token bucket where each request deposits "ratio" tokens (expiring after "window"),
+ * and each retry or hedge withdraws one token. When the bucket is empty retries and hedges are suppressed.
+ * Ratio and MinRetries can explicitly be set to 0, e.g. to only allow minRetries regardless of traffic.
+ */
+export interface RetryBudgetConfig {
+  ratio?: number /* float64 */;
+  window: Duration;
+  minRetries?: number /* int */;
+}
+export interface FailsafeMethodOverrideConfig {
+  method: string;
+  retry?: RetryPolicyConfig;
+  timeout?: TimeoutPolicyConfig;
+  hedge?: HedgePolicyConfig;
 }
 export interface RetryPolicyConfig {
   maxAttempts: number /* int */;
@@ -238,13 +379,31 @@ export interface CircuitBreakerPolicyConfig {
   halfOpenAfter: string;
   successThresholdCount: number /* uint */;
   successThresholdCapacity: number /* uint */;
+  scope?: CircuitBreakerScope;
+  methodGroups?: (CircuitBreakerMethodGroupConfig | undefined)[];
+}
+export type CircuitBreakerScope = string;
+export const CircuitBreakerScopeUpstream: CircuitBreakerScope = "upstream";
+export const CircuitBreakerScopeMethod: CircuitBreakerScope = "method";
+export const CircuitBreakerScopeMethodGroup: CircuitBreakerScope = "methodGroup";
+export interface CircuitBreakerMethodGroupConfig {
+  name: string;
+  methods: string;
 }
 export interface TimeoutPolicyConfig {
   duration: Duration;
+  quantile?: number /* float64 */;
+  factor?: number /* float64 */;
+  minDuration?: Duration;
+  maxDuration?: Duration;
 }
 export interface HedgePolicyConfig {
   delay: string;
   maxCount: number /* int */;
+  quantile?: number /* float64 */;
+  factor?: number /* float64 */;
+  minDelay?: Duration;
+  maxDelay?: Duration;
 }
 export interface RateLimiterConfig {
   budgets: RateLimitBudgetConfig[];
@@ -261,6 +420,28 @@ export interface RateLimitRuleConfig {
 }
 export interface HealthCheckConfig {
   scoreMetricsWindowSize: string;
+  minHealthyUpstreams?: number /* int */;
+  maxErrorRate?: number /* float64 */;
+  sharedState?: SharedStateConfig;
+  persistence?: HealthPersistenceConfig;
+}
+export interface HealthPersistenceConfig {
+  file?: string;
+  connector?: ConnectorConfig;
+  interval?: Duration;
+  maxAge?: Duration;
+  decayHalfLife?: Duration;
+}
+/**
+ * SharedStateConfig enables sharing upstream health signals (cordons, error rates, rate-limit hits
+ * and latest blocks) across eRPC replicas so that the whole fleet reacts to an outage together.
+ * Signals are broadcast via pub/sub of the connector, which must use the redis driver.
+ */
+export interface SharedStateConfig {
+  connector: ConnectorConfig;
+  channel?: string;
+  syncInterval?: Duration;
+  staleAfter?: Duration;
 }
 export interface NetworkConfig {
   architecture: 'evm';
@@ -269,10 +450,53 @@ export interface NetworkConfig {
   evm?: EvmNetworkConfig;
   selectionPolicy?: SelectionPolicyConfig;
   directiveDefaults?: DirectiveDefaultsConfig;
+  upstreamSelection?: UpstreamSelectionConfig;
+  upstreamGroups?: (UpstreamGroupConfig | undefined)[];
+}
+export interface UpstreamGroupConfig {
+  name: string;
+  minHealthyUpstreams?: number /* int */;
+}
+export type UpstreamSelectionMode = string;
+/**
+ * Always try upstreams in order of their score, the best upstream receives all traffic until its score drops.
+ */
+export const UpstreamSelectionModeOrdered: UpstreamSelectionMode = "ordered";
+/**
+ * Pick upstreams by random sampling weighted by their score, so that load is spread proportionally.
+ */
+export const UpstreamSelectionModeWeightedRandom: UpstreamSelectionMode = "weighted-random";
+/**
+ * Pick the better of two random upstreams based on in-flight requests and p90 latency.
+ */
+export const UpstreamSelectionModePowerOfTwoChoices: UpstreamSelectionMode = "power-of-two-choices";
+export interface UpstreamSelectionConfig {
+  mode: UpstreamSelectionMode;
+  sticky?: StickySessionConfig;
+}
+export type StickySessionKey = string;
+export const StickySessionKeyIdentity: StickySessionKey = "identity";
+export const StickySessionKeyHeader: StickySessionKey = "header";
+export const StickySessionKeyIP: StickySessionKey = "ip";
+export interface StickySessionConfig {
+  /**
+   * What identifies a client session: authenticated consumer identity, a request header or client IP.
+   */
+  keyBy: StickySessionKey;
+  /**
+   * Header name used as session key when keyBy is "header".
+   */
+  header?: string;
+  /**
+   * How long requests of a session are pinned to the upstream that served it.
+   */
+  window: Duration;
+  maxSessions: number /* int */;
 }
 export interface DirectiveDefaultsConfig {
   retryEmpty?: boolean;
   retryPending?: boolean;
+  verifyEmpty?: boolean;
   skipCacheRead?: boolean;
   useUpstream?: string;
 }
@@ -293,6 +517,8 @@ export const AuthTypeSecret: AuthType = "secret";
 export const AuthTypeJwt: AuthType = "jwt";
 export const AuthTypeSiwe: AuthType = "siwe";
 export const AuthTypeNetwork: AuthType = "network";
+export const AuthTypeDatabase: AuthType = "database";
+export const AuthTypeMtls: AuthType = "mtls";
 export interface AuthConfig {
   strategies: TsAuthStrategyConfig[];
 }
@@ -305,6 +531,8 @@ export interface AuthStrategyConfig {
   secret?: SecretStrategyConfig;
   jwt?: JwtStrategyConfig;
   siwe?: SiweStrategyConfig;
+  database?: DatabaseStrategyConfig;
+  mtls?: MtlsStrategyConfig;
 }
 export interface SecretStrategyConfig {
   value: string;
@@ -315,9 +543,82 @@ export interface JwtStrategyConfig {
   allowedAlgorithms: string[];
   requiredClaims: string[];
   verificationKeys: { [key: string]: string};
+  /**
+   * JWKS endpoint of the identity provider, used in addition to (or instead of) static verification keys.
+   */
+  jwksUri?: string;
+  jwksRefreshInterval?: Duration;
+  jwksMinRefreshInterval?: Duration;
+  /**
+   * Optional mapping of token claims to per-consumer authorization (allowed networks, methods and rate limit budget).
+   */
+  claimsMapping?: JwtClaimsMappingConfig;
+}
+export interface JwtClaimsMappingConfig {
+  /**
+   * Claim used as the consumer identifier (default: "sub").
+   */
+  userIdClaim?: string;
+  /**
+   * Claim containing the allowed networks, either chain ids (e.g. 42161) or network ids (e.g. "evm:42161").
+   */
+  networksClaim?: string;
+  /**
+   * Claim containing the allowed methods, wildcards are supported (e.g. "eth_*").
+   */
+  methodsClaim?: string;
+  /**
+   * Claim containing the tier of the consumer, which selects a rate limit budget from tierBudgets.
+   */
+  tierClaim?: string;
+  tierBudgets?: { [key: string]: string};
+}
+export interface DatabaseStrategyConfig {
+  /**
+   * Connector where API keys are stored, keyed by the hex-encoded sha256 hash of the key.
+   */
+  connector?: TsConnectorConfig;
+  /**
+   * How long a verified (or rejected) key is cached in memory, which also
+   * defines how quickly a revoked key stops being accepted.
+   */
+  cacheTTL: Duration;
+  cacheMaxItems: number /* int */;
+}
+export interface MtlsStrategyConfig {
+  /**
+   * Allowed client certificate identities matched against subject CN and SANs (DNS, email, URI), wildcards are supported.
+   */
+  allowedSubjects?: string[];
+  /**
+   * Allowed hex-encoded sha256 fingerprints of client certificates' public key (SPKI).
+   */
+  allowedFingerprints?: string[];
+  /**
+   * Rate limit budget per certificate identity, keyed by subject CN/SAN or SPKI fingerprint.
+   */
+  identityBudgets?: { [key: string]: string};
 }
 export interface SiweStrategyConfig {
   allowedDomains: string[];
+  /**
+   * Maximum duration a signed message is accepted for after its issuedAt, even if its expirationTime is later.
+   */
+  sessionLifetime?: Duration;
+  /**
+   * When set, messages must carry a nonce issued by eRPC which is bound to the first message that uses it.
+   */
+  nonce?: SiweNonceConfig;
+}
+export interface SiweNonceConfig {
+  /**
+   * Connector where issued nonces are stored, which must be shared when running multiple eRPC instances.
+   */
+  connector?: TsConnectorConfig;
+  /**
+   * How long an issued nonce can be used to sign in before it expires.
+   */
+  ttl: Duration;
 }
 export interface NetworkStrategyConfig {
   allowedIPs: string[];
@@ -333,6 +634,21 @@ export interface MetricsConfig {
   hostV6?: string;
   port?: number /* int */;
 }
+/**
+ * TracingConfig enables exporting request traces to an OpenTelemetry collector via OTLP over HTTP.
+ */
+export interface TracingConfig {
+  enabled: boolean;
+  /**
+   * Endpoint of the OTLP/HTTP collector (e.g. http://localhost:4318), "/v1/traces" is appended when no path is set.
+   */
+  endpoint: string;
+  headers?: { [key: string]: string};
+  serviceName?: string;
+  sampleRate?: number /* float64 */;
+  batchSize?: number /* int */;
+  flushInterval?: Duration;
+}
 
 //////////
 // source: data.go
@@ -450,3 +766,17 @@ export const EvmSyncingStateUnknown: EvmSyncingState = 0;
 export const EvmSyncingStateSyncing: EvmSyncingState = 1;
 export const EvmSyncingStateNotSyncing: EvmSyncingState = 2;
 export type Upstream = any;
+
+//////////
+// source: user.go
+
+/**
+ * User is the identity of a consumer resolved by an auth strategy. Besides the identifier
+ * it can carry per-consumer restrictions (e.g. stored alongside an API key in the database).
+ */
+export interface User {
+  id: string;
+  rateLimitBudget?: string;
+  allowedNetworks?: string[];
+  allowedMethods?: string[];
+}
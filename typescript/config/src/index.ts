@@ -31,6 +31,14 @@ export {
   EvmNodeTypeLight,
   // Architecture export
   ArchitectureEvm,
+  // Upstream selection modes
+  UpstreamSelectionModeOrdered,
+  UpstreamSelectionModeWeightedRandom,
+  UpstreamSelectionModePowerOfTwoChoices,
+  // Sticky session keys
+  StickySessionKeyIdentity,
+  StickySessionKeyHeader,
+  StickySessionKeyIP,
   // Upstream types const exprots
   UpstreamTypeEvm,
   UpstreamTypeEvmAlchemy,
@@ -46,6 +54,8 @@ export {
   AuthTypeJwt,
   AuthTypeSiwe,
   AuthTypeNetwork,
+  AuthTypeDatabase,
+  AuthTypeMtls,
 } from "./generated";
 export type {
   Config,
@@ -64,27 +74,48 @@ export type {
   UpstreamConfig,
   RoutingConfig,
   ScoreMultiplierConfig,
+  HealthProbeConfig,
+  HealthProbeExpectConfig,
   RateLimitAutoTuneConfig,
   JsonRpcUpstreamConfig,
   EvmUpstreamConfig,
   FailsafeConfig,
+  FailsafeMethodOverrideConfig,
+  RetryBudgetConfig,
+  UpstreamGroupConfig,
   RetryPolicyConfig,
   CircuitBreakerPolicyConfig,
+  CircuitBreakerMethodGroupConfig,
   TimeoutPolicyConfig,
   HedgePolicyConfig,
   RateLimiterConfig,
   RateLimitBudgetConfig,
   RateLimitRuleConfig,
   HealthCheckConfig,
+  SharedStateConfig,
+  HealthPersistenceConfig,
   NetworkConfig,
   EvmNetworkConfig,
   SelectionPolicyConfig,
+  UpstreamSelectionConfig,
+  StickySessionConfig,
   AuthStrategyConfig,
   SecretStrategyConfig,
   JwtStrategyConfig,
+  JwtClaimsMappingConfig,
   SiweStrategyConfig,
+  SiweNonceConfig,
   NetworkStrategyConfig,
+  DatabaseStrategyConfig,
+  MtlsStrategyConfig,
   MetricsConfig,
+  TracingConfig,
+  AccessLogConfig,
+  AccessLogSamplingConfig,
+  CaptureConfig,
+  CaptureSamplingConfig,
+  MeteringConfig,
+  MethodComputeUnitsConfig,
 } from "./generated";
 
 import type { Config } from './generated'
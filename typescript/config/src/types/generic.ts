@@ -1,8 +1,10 @@
 import type {
     DynamoDBConnectorConfig,
     AuthStrategyConfig as GenAuthStrategyConfig,
+    DatabaseStrategyConfig,
     JwtStrategyConfig,
     MemoryConnectorConfig,
+    MtlsStrategyConfig,
     NetworkStrategyConfig,
     PostgreSQLConnectorConfig,
     RedisConnectorConfig,
@@ -96,14 +98,14 @@ import type {
   /**
    * Supported auth type
    */
-  export type AuthType = "secret" | "jwt" | "siwe" | "network";
+  export type AuthType = "secret" | "jwt" | "siwe" | "network" | "database" | "mtls";
   
   /**
    * Connector config depending on the upstream type
    */
   export type AuthStrategyConfig = Omit<
     GenAuthStrategyConfig,
-    "type" | "network" | "secret" | "jwt" | "siwe"
+    "type" | "network" | "secret" | "jwt" | "siwe" | "database" | "mtls"
   > &
     (
       | {
@@ -122,5 +124,13 @@ import type {
           type: "siwe";
           secret: SiweStrategyConfig;
         }
+      | {
+          type: "database";
+          database: DatabaseStrategyConfig;
+        }
+      | {
+          type: "mtls";
+          mtls: MtlsStrategyConfig;
+        }
     );
   
\ No newline at end of file
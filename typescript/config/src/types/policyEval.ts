@@ -11,6 +11,7 @@ export type PolicyEvalUpstreamMetrics = {
   p90LatencySecs: number;
   blockHeadLag: number;
   finalizationLag: number;
+  inFlightRequests: number;
 };
 
 /**
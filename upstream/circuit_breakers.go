@@ -0,0 +1,151 @@
+package upstream
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/rs/zerolog"
+)
+
+// maxMethodCircuitBreakers caps breakers created with "method" scope since methods are chosen by clients,
+// breakers of methods seen after the cap is reached share the upstream-wide "*" breaker.
+const maxMethodCircuitBreakers = 128
+
+// circuitBreakers holds the circuit breakers of an upstream keyed by the configured scope (the whole upstream,
+// each method, or each method group) so that failures of one method do not block healthy traffic of others.
+// Breakers and their executors are created lazily when a key is seen for the first time.
+type circuitBreakers struct {
+	logger     *zerolog.Logger
+	projectId  string
+	upstreamId string
+	cfg        *common.CircuitBreakerPolicyConfig
+
+	mu       sync.RWMutex
+	breakers map[string]circuitbreaker.CircuitBreaker[*common.NormalizedResponse]
+	// failsafe method pattern + breaker key -> executor composing other policies (retry, hedge, timeout) with the breaker
+	executors map[string]failsafe.Executor[*common.NormalizedResponse]
+}
+
+func newCircuitBreakers(
+	logger *zerolog.Logger,
+	projectId string,
+	upstreamId string,
+	cfg *common.CircuitBreakerPolicyConfig,
+) (*circuitBreakers, error) {
+	if cfg.HalfOpenAfter != "" {
+		if _, err := time.ParseDuration(cfg.HalfOpenAfter); err != nil {
+			return nil, common.NewErrFailsafeConfiguration(fmt.Errorf("failed to parse circuitBreaker.halfOpenAfter: %v", err), map[string]interface{}{
+				"entity": upstreamId,
+				"policy": cfg,
+			})
+		}
+	}
+
+	return &circuitBreakers{
+		logger:     logger,
+		projectId:  projectId,
+		upstreamId: upstreamId,
+		cfg:        cfg,
+		breakers:   make(map[string]circuitbreaker.CircuitBreaker[*common.NormalizedResponse]),
+		executors:  make(map[string]failsafe.Executor[*common.NormalizedResponse]),
+	}, nil
+}
+
+// scopeKey returns the key of the breaker responsible for the method, based on the configured scope.
+// It must be called while holding mu.
+func (c *circuitBreakers) scopeKey(method string) string {
+	switch c.cfg.Scope {
+	case common.CircuitBreakerScopeMethod:
+		if method == "" {
+			break
+		}
+		if _, ok := c.breakers[method]; ok || len(c.breakers) < maxMethodCircuitBreakers {
+			return method
+		}
+	case common.CircuitBreakerScopeMethodGroup:
+		for _, g := range c.cfg.MethodGroups {
+			if match, err := common.WildcardMatch(g.Methods, method); err == nil && match {
+				return g.Name
+			}
+		}
+	}
+	return "*"
+}
+
+// executor returns an executor composing policies of the failsafe executor picked for the method
+// with the breaker responsible for the method.
+func (c *circuitBreakers) executor(fe *FailsafeExecutor, method string) (failsafe.Executor[*common.NormalizedResponse], error) {
+	c.mu.RLock()
+	key := c.scopeKey(method)
+	exec, ok := c.executors[fe.Method+common.KeySeparator+key]
+	c.mu.RUnlock()
+	if ok {
+		return exec, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key = c.scopeKey(method)
+	execKey := fe.Method + common.KeySeparator + key
+	if exec, ok := c.executors[execKey]; ok {
+		return exec, nil
+	}
+
+	cb, ok := c.breakers[key]
+	if !ok {
+		var err error
+		cb, err = c.createBreaker(key)
+		if err != nil {
+			return nil, err
+		}
+		c.breakers[key] = cb
+	}
+
+	exec = failsafe.NewExecutor[*common.NormalizedResponse](orderedFailsafePolicies(common.ScopeUpstream, fe.Policies, cb)...)
+	c.executors[execKey] = exec
+
+	return exec, nil
+}
+
+func (c *circuitBreakers) createBreaker(key string) (circuitbreaker.CircuitBreaker[*common.NormalizedResponse], error) {
+	lg := c.logger.With().Str("circuitBreakerKey", key).Logger()
+	cb, err := createCircuitBreakerPolicy(&lg, c.upstreamId, c.cfg, func(from, to circuitbreaker.State) {
+		health.MetricUpstreamCircuitBreakerTransitionsTotal.WithLabelValues(c.projectId, c.upstreamId, key, from.String(), to.String()).Inc()
+		health.MetricUpstreamCircuitBreakerState.WithLabelValues(c.projectId, c.upstreamId, key).Set(float64(circuitBreakerStateValue(to)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	health.MetricUpstreamCircuitBreakerState.WithLabelValues(c.projectId, c.upstreamId, key).Set(float64(circuitBreakerStateValue(cb.State())))
+
+	return cb, nil
+}
+
+// States returns the current state (closed, half-open, open) of each breaker created so far.
+func (c *circuitBreakers) States() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	states := make(map[string]string, len(c.breakers))
+	for key, cb := range c.breakers {
+		states[key] = cb.State().String()
+	}
+	return states
+}
+
+// circuitBreakerStateValue maps states to the values reported in metrics (0=closed, 1=half-open, 2=open).
+func circuitBreakerStateValue(state circuitbreaker.State) int {
+	switch state {
+	case circuitbreaker.HalfOpenState:
+		return 1
+	case circuitbreaker.OpenState:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,97 @@
+package upstream
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	newCircuitBreakersForScope := func(t *testing.T, scope common.CircuitBreakerScope) *circuitBreakers {
+		cfg := &common.CircuitBreakerPolicyConfig{
+			FailureThresholdCount:    1,
+			FailureThresholdCapacity: 1,
+			SuccessThresholdCount:    1,
+			SuccessThresholdCapacity: 1,
+			HalfOpenAfter:            "1m",
+			Scope:                    scope,
+			MethodGroups: []*common.CircuitBreakerMethodGroupConfig{
+				{Name: "tracing", Methods: "debug_*|trace_*"},
+			},
+		}
+		cbs, err := newCircuitBreakers(&log.Logger, "test", "rpc1", cfg)
+		require.NoError(t, err)
+		return cbs
+	}
+
+	fe := &FailsafeExecutor{Method: "*", Policies: map[string]failsafe.Policy[*common.NormalizedResponse]{}}
+
+	t.Run("ScopeKeys", func(t *testing.T) {
+		assert.Equal(t, "*", newCircuitBreakersForScope(t, common.CircuitBreakerScopeUpstream).scopeKey("eth_call"))
+		assert.Equal(t, "eth_call", newCircuitBreakersForScope(t, common.CircuitBreakerScopeMethod).scopeKey("eth_call"))
+
+		grouped := newCircuitBreakersForScope(t, common.CircuitBreakerScopeMethodGroup)
+		assert.Equal(t, "tracing", grouped.scopeKey("debug_traceTransaction"))
+		assert.Equal(t, "tracing", grouped.scopeKey("trace_block"))
+		assert.Equal(t, "*", grouped.scopeKey("eth_call"))
+	})
+
+	t.Run("OpenBreakerOfOneMethodDoesNotBlockOthers", func(t *testing.T) {
+		cbs := newCircuitBreakersForScope(t, common.CircuitBreakerScopeMethod)
+
+		_, err := cbs.executor(fe, "debug_traceTransaction")
+		require.NoError(t, err)
+		cbs.breakers["debug_traceTransaction"].Open()
+
+		exec, err := cbs.executor(fe, "debug_traceTransaction")
+		require.NoError(t, err)
+		_, err = exec.Get(func() (*common.NormalizedResponse, error) {
+			return common.NewNormalizedResponse(), nil
+		})
+		assert.Error(t, err, "open breaker must reject executions")
+
+		exec, err = cbs.executor(fe, "eth_call")
+		require.NoError(t, err)
+		_, err = exec.Get(func() (*common.NormalizedResponse, error) {
+			return common.NewNormalizedResponse(), nil
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"debug_traceTransaction": "open",
+			"eth_call":               "closed",
+		}, cbs.States())
+	})
+
+	t.Run("UpstreamScopeSharesOneBreaker", func(t *testing.T) {
+		cbs := newCircuitBreakersForScope(t, common.CircuitBreakerScopeUpstream)
+
+		_, err := cbs.executor(fe, "debug_traceTransaction")
+		require.NoError(t, err)
+		_, err = cbs.executor(fe, "eth_call")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"*": "closed"}, cbs.States())
+	})
+
+	t.Run("MethodScopeCapsNumberOfBreakers", func(t *testing.T) {
+		cbs := newCircuitBreakersForScope(t, common.CircuitBreakerScopeMethod)
+
+		for i := 0; i < maxMethodCircuitBreakers; i++ {
+			_, err := cbs.executor(fe, fmt.Sprintf("custom_method%d", i))
+			require.NoError(t, err)
+		}
+		_, err := cbs.executor(fe, "custom_overflow")
+		require.NoError(t, err)
+
+		states := cbs.States()
+		assert.Len(t, states, maxMethodCircuitBreakers+1)
+		assert.Contains(t, states, "*")
+		assert.NotContains(t, states, "custom_overflow")
+		assert.Equal(t, "custom_method0", cbs.scopeKey("custom_method0"), "existing breakers must keep being used")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
 	"github.com/failsafe-go/failsafe-go"
 	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/failsafe-go/failsafe-go/hedgepolicy"
@@ -14,7 +15,7 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity string, fsCfg *common.FailsafeConfig) (map[string]failsafe.Policy[*common.NormalizedResponse], error) {
+func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity string, fsCfg *common.FailsafeConfig, tracker *health.Tracker, budget *RetryBudget) (map[string]failsafe.Policy[*common.NormalizedResponse], error) {
 	// The order of policies below are important as per docs of failsafe-go
 	var policies = map[string]failsafe.Policy[*common.NormalizedResponse]{}
 
@@ -36,26 +37,18 @@ func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity s
 	}
 
 	if fsCfg.Retry != nil {
-		p, err := createRetryPolicy(scope, entity, fsCfg.Retry)
+		p, err := createRetryPolicy(scope, entity, fsCfg.Retry, budget)
 		if err != nil {
 			return nil, err
 		}
 		policies["retry"] = p
 	}
 
-	// CircuitBreaker does not make sense for network-level requests
-	if scope == common.ScopeUpstream {
-		if fsCfg.CircuitBreaker != nil {
-			p, err := createCircuitBreakerPolicy(&lg, entity, fsCfg.CircuitBreaker)
-			if err != nil {
-				return nil, err
-			}
-			policies["cb"] = p
-		}
-	}
+	// CircuitBreaker does not make sense for network-level requests, and for upstream-level requests
+	// breakers are created lazily per scope key (upstream, method or method group) by circuitBreakers.
 
 	if fsCfg.Hedge != nil {
-		p, err := createHedgePolicy(&lg, entity, fsCfg.Hedge)
+		p, err := createHedgePolicy(&lg, scope, entity, fsCfg.Hedge, tracker, budget)
 		if err != nil {
 			return nil, err
 		}
@@ -76,7 +69,183 @@ func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity s
 	return policies, nil
 }
 
-func createCircuitBreakerPolicy(logger *zerolog.Logger, entity string, cfg *common.CircuitBreakerPolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+// FailsafeExecutor is the executor (and its policies) used for methods matching the Method pattern.
+type FailsafeExecutor struct {
+	Method          string
+	Policies        map[string]failsafe.Policy[*common.NormalizedResponse]
+	Executor        failsafe.Executor[*common.NormalizedResponse]
+	Timeout         *time.Duration
+	AdaptiveTimeout *AdaptiveDuration
+	// RetryBudget is shared by all executors of the same network or upstream (nil when not configured).
+	RetryBudget *RetryBudget
+}
+
+// TimeoutFor returns the timeout to apply for a request, based on the observed latency (in seconds)
+// at the configured quantile when adaptive timeout is enabled, otherwise the fixed timeout.
+func (fe *FailsafeExecutor) TimeoutFor(latencyAt func(quantile float64) float64) *time.Duration {
+	if fe.Timeout == nil || fe.AdaptiveTimeout == nil || latencyAt == nil {
+		return fe.Timeout
+	}
+	d := fe.AdaptiveTimeout.Compute(latencyAt(fe.AdaptiveTimeout.Quantile), *fe.Timeout)
+	return &d
+}
+
+// AdaptiveDuration derives a duration from an observed latency quantile multiplied by a factor,
+// clamped between Min and Max (when set).
+type AdaptiveDuration struct {
+	Quantile float64
+	Factor   float64
+	Min      time.Duration
+	Max      time.Duration
+}
+
+func newAdaptiveDuration(quantile, factor float64, minValue, maxValue string) (*AdaptiveDuration, error) {
+	if quantile <= 0 {
+		return nil, nil
+	}
+	ad := &AdaptiveDuration{Quantile: quantile, Factor: factor}
+	var err error
+	if minValue != "" {
+		if ad.Min, err = time.ParseDuration(minValue); err != nil {
+			return nil, err
+		}
+	}
+	if maxValue != "" {
+		if ad.Max, err = time.ParseDuration(maxValue); err != nil {
+			return nil, err
+		}
+	}
+	return ad, nil
+}
+
+// Compute returns the adaptive duration for the given latency, or fallback when there is no latency data yet.
+func (a *AdaptiveDuration) Compute(latencySecs float64, fallback time.Duration) time.Duration {
+	if latencySecs <= 0 {
+		return fallback
+	}
+	d := time.Duration(latencySecs * a.Factor * float64(time.Second))
+	if a.Min > 0 && d < a.Min {
+		d = a.Min
+	}
+	if a.Max > 0 && d > a.Max {
+		d = a.Max
+	}
+	return d
+}
+
+// CreateFailSafeExecutors builds one executor per method override (in the configured order) followed by
+// the default executor that matches all methods, so that PickFailsafeExecutor can pick by first match.
+func CreateFailSafeExecutors(logger *zerolog.Logger, scope common.Scope, entity string, fsCfg *common.FailsafeConfig, tracker *health.Tracker) ([]*FailsafeExecutor, error) {
+	executors := []*FailsafeExecutor{}
+	var budget *RetryBudget
+	if fsCfg != nil && fsCfg.RetryBudget != nil {
+		var err error
+		budget, err = NewRetryBudget(fsCfg.RetryBudget)
+		if err != nil {
+			return nil, common.NewErrFailsafeConfiguration(err, map[string]interface{}{
+				"entity": entity,
+				"policy": fsCfg.RetryBudget,
+			})
+		}
+	}
+	if fsCfg != nil {
+		for _, o := range fsCfg.MethodOverrides {
+			fe, err := createFailsafeExecutor(logger, scope, entity, o.Method, fsCfg.WithMethodOverride(o), tracker, budget)
+			if err != nil {
+				return nil, err
+			}
+			executors = append(executors, fe)
+		}
+	}
+
+	fe, err := createFailsafeExecutor(logger, scope, entity, "*", fsCfg, tracker, budget)
+	if err != nil {
+		return nil, err
+	}
+	return append(executors, fe), nil
+}
+
+func createFailsafeExecutor(logger *zerolog.Logger, scope common.Scope, entity string, method string, fsCfg *common.FailsafeConfig, tracker *health.Tracker, budget *RetryBudget) (*FailsafeExecutor, error) {
+	lg := logger.With().Str("failsafeMethod", method).Logger()
+	policies, err := CreateFailSafePolicies(&lg, scope, entity, fsCfg, tracker, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeoutDuration *time.Duration
+	var adaptiveTimeout *AdaptiveDuration
+	if fsCfg != nil && fsCfg.Timeout != nil {
+		d, err := time.ParseDuration(fsCfg.Timeout.Duration)
+		if err != nil {
+			return nil, err
+		}
+		timeoutDuration = &d
+		adaptiveTimeout, err = newAdaptiveDuration(fsCfg.Timeout.Quantile, fsCfg.Timeout.Factor, fsCfg.Timeout.MinDuration, fsCfg.Timeout.MaxDuration)
+		if err != nil {
+			return nil, err
+		}
+		if adaptiveTimeout != nil && adaptiveTimeout.Max == 0 {
+			// Same upper bound as the timeout policy, so adaptive timeout is never cut off by the policy
+			adaptiveTimeout.Max = d
+		}
+	}
+
+	return &FailsafeExecutor{
+		Method:          method,
+		Policies:        policies,
+		Executor:        failsafe.NewExecutor[*common.NormalizedResponse](orderedFailsafePolicies(scope, policies, nil)...),
+		Timeout:         timeoutDuration,
+		AdaptiveTimeout: adaptiveTimeout,
+		RetryBudget:     budget,
+	}, nil
+}
+
+// PickFailsafeExecutor returns the first executor whose method pattern matches, falling back to the last (default) one.
+func PickFailsafeExecutor(executors []*FailsafeExecutor, method string) *FailsafeExecutor {
+	for _, fe := range executors {
+		if fe.Method == "*" {
+			return fe
+		}
+		if match, err := common.WildcardMatch(fe.Method, method); err == nil && match {
+			return fe
+		}
+	}
+	return executors[len(executors)-1]
+}
+
+// orderedFailsafePolicies composes policies in the order they must wrap each other (outermost first).
+// For network-level the timeout applies to the overall lifecycle so it wraps everything, and for upstream-level
+// it is the per-attempt timeout: retry, circuit breaker (if any), hedge and finally timeout.
+func orderedFailsafePolicies(
+	scope common.Scope,
+	policies map[string]failsafe.Policy[*common.NormalizedResponse],
+	cb failsafe.Policy[*common.NormalizedResponse],
+) []failsafe.Policy[*common.NormalizedResponse] {
+	ordered := []failsafe.Policy[*common.NormalizedResponse]{}
+	if p, ok := policies["timeout"]; ok && scope == common.ScopeNetwork {
+		ordered = append(ordered, p)
+	}
+	if p, ok := policies["retry"]; ok {
+		ordered = append(ordered, p)
+	}
+	if cb != nil {
+		ordered = append(ordered, cb)
+	}
+	if p, ok := policies["hedge"]; ok {
+		ordered = append(ordered, p)
+	}
+	if p, ok := policies["timeout"]; ok && scope == common.ScopeUpstream {
+		ordered = append(ordered, p)
+	}
+	return ordered
+}
+
+func createCircuitBreakerPolicy(
+	logger *zerolog.Logger,
+	entity string,
+	cfg *common.CircuitBreakerPolicyConfig,
+	onStateChanged func(from, to circuitbreaker.State),
+) (circuitbreaker.CircuitBreaker[*common.NormalizedResponse], error) {
 	builder := circuitbreaker.Builder[*common.NormalizedResponse]()
 
 	if cfg.FailureThresholdCount > 0 {
@@ -116,6 +285,9 @@ func createCircuitBreakerPolicy(logger *zerolog.Logger, entity string, cfg *comm
 			Uint("failureRate", mt.FailureRate()).
 			Uint("successRate", mt.SuccessRate()).
 			Msgf("circuit breaker state changed from %s to %s", event.OldState, event.NewState)
+		if onStateChanged != nil {
+			onStateChanged(event.OldState, event.NewState)
+		}
 	})
 	builder.OnFailure(func(event failsafe.ExecutionEvent[*common.NormalizedResponse]) {
 		err := event.LastError()
@@ -175,7 +347,7 @@ func createCircuitBreakerPolicy(logger *zerolog.Logger, entity string, cfg *comm
 	return builder.Build(), nil
 }
 
-func createHedgePolicy(logger *zerolog.Logger, entity string, cfg *common.HedgePolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+func createHedgePolicy(logger *zerolog.Logger, scope common.Scope, entity string, cfg *common.HedgePolicyConfig, tracker *health.Tracker, budget *RetryBudget) (failsafe.Policy[*common.NormalizedResponse], error) {
 	delay, err := time.ParseDuration(cfg.Delay)
 	if err != nil {
 		return nil, common.NewErrFailsafeConfiguration(fmt.Errorf("failed to parse hedge.delay: %v", err), map[string]interface{}{
@@ -183,7 +355,33 @@ func createHedgePolicy(logger *zerolog.Logger, entity string, cfg *common.HedgeP
 			"policy": cfg,
 		})
 	}
-	builder := hedgepolicy.BuilderWithDelay[*common.NormalizedResponse](delay)
+	adaptiveDelay, err := newAdaptiveDuration(cfg.Quantile, cfg.Factor, cfg.MinDelay, cfg.MaxDelay)
+	if err != nil {
+		return nil, common.NewErrFailsafeConfiguration(fmt.Errorf("failed to parse hedge delay bounds: %v", err), map[string]interface{}{
+			"entity": entity,
+			"policy": cfg,
+		})
+	}
+
+	var builder hedgepolicy.HedgePolicyBuilder[*common.NormalizedResponse]
+	if adaptiveDelay != nil && tracker != nil {
+		// Hedge only when the request is an outlier compared to the observed latency of the upstream being tried.
+		builder = hedgepolicy.BuilderWithDelayFunc[*common.NormalizedResponse](func(exec failsafe.ExecutionAttempt[*common.NormalizedResponse]) time.Duration {
+			req, ok := exec.Context().Value(common.RequestContextKey).(*common.NormalizedRequest)
+			if !ok || req == nil {
+				return delay
+			}
+			ups := req.LastUpstream()
+			if ups == nil {
+				return delay
+			}
+			method, _ := req.Method()
+			qt := tracker.GetUpstreamMethodMetrics(ups.Config().Id, req.NetworkId(), method).LatencySecs
+			return adaptiveDelay.Compute(qt.Quantile(adaptiveDelay.Quantile), delay)
+		})
+	} else {
+		builder = hedgepolicy.BuilderWithDelay[*common.NormalizedResponse](delay)
+	}
 
 	if cfg.MaxCount > 0 {
 		builder = builder.WithMaxHedges(cfg.MaxCount)
@@ -205,6 +403,12 @@ func createHedgePolicy(logger *zerolog.Logger, entity string, cfg *common.HedgeP
 			}
 		}
 
+		if budget != nil && !budget.TryWithdraw() {
+			health.MetricFailsafeRetryBudgetExhaustedTotal.WithLabelValues(string(scope), entity, "hedge").Inc()
+			logger.Debug().Str("method", method).Msgf("ignoring hedge because retry budget is exhausted")
+			return false
+		}
+
 		logger.Trace().Str("method", method).Interface("id", req.ID()).Msgf("attempting to hedge request")
 
 		// Continue with the next hedge
@@ -214,7 +418,7 @@ func createHedgePolicy(logger *zerolog.Logger, entity string, cfg *common.HedgeP
 	return builder.Build(), nil
 }
 
-func createRetryPolicy(scope common.Scope, entity string, cfg *common.RetryPolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+func createRetryPolicy(scope common.Scope, entity string, cfg *common.RetryPolicyConfig, budget *RetryBudget) (failsafe.Policy[*common.NormalizedResponse], error) {
 	builder := retrypolicy.Builder[*common.NormalizedResponse]()
 
 	if cfg.MaxAttempts > 0 {
@@ -261,6 +465,20 @@ func createRetryPolicy(scope common.Scope, entity string, cfg *common.RetryPolic
 		}
 	}
 
+	if budget != nil {
+		// Checked on each handled failure, while the token is only taken when the retry actually starts.
+		builder = builder.AbortIf(func(result *common.NormalizedResponse, err error) bool {
+			if budget.Exhausted() {
+				health.MetricFailsafeRetryBudgetExhaustedTotal.WithLabelValues(string(scope), entity, "retry").Inc()
+				return true
+			}
+			return false
+		})
+		builder = builder.OnRetry(func(event failsafe.ExecutionEvent[*common.NormalizedResponse]) {
+			budget.TryWithdraw()
+		})
+	}
+
 	builder.HandleIf(func(result *common.NormalizedResponse, err error) bool {
 		// 400 / 404 / 405 / 413 -> No Retry
 		// RPC-RPC client-side error (invalid params) -> No Retry
@@ -382,6 +600,17 @@ func createTimeoutPolicy(logger *zerolog.Logger, entity string, cfg *common.Time
 	}
 
 	timeoutDuration, err := time.ParseDuration(cfg.Duration)
+	if err == nil && cfg.Quantile > 0 {
+		// With adaptive timeout the effective deadline is enforced via request context, so the policy only
+		// acts as a hard cap at the upper bound of adaptive timeout (which is duration when maxDuration is not set).
+		maxDuration := timeoutDuration
+		if cfg.MaxDuration != "" {
+			maxDuration, err = time.ParseDuration(cfg.MaxDuration)
+		}
+		if maxDuration > timeoutDuration {
+			timeoutDuration = maxDuration
+		}
+	}
 	builder := timeout.Builder[*common.NormalizedResponse](timeoutDuration)
 
 	if logger.GetLevel() == zerolog.TraceLevel {
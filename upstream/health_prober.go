@@ -0,0 +1,248 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+)
+
+const (
+	HealthProbeOutcomeSuccess  = "success"
+	HealthProbeOutcomeFailure  = "failure"
+	HealthProbeOutcomeMismatch = "mismatch"
+	HealthProbeOutcomeSkipped  = "skipped"
+)
+
+// HealthProber periodically sends the configured probes to an upstream (independent of user traffic),
+// and cordons/uncordons the upstream based on consecutive failures/successes of each probe.
+type HealthProber struct {
+	Enabled bool
+
+	logger   *zerolog.Logger
+	upstream *Upstream
+	network  common.Network
+	tracker  *health.Tracker
+	probes   []*healthProbe
+}
+
+type healthProbe struct {
+	cfg      *common.HealthProbeConfig
+	interval time.Duration
+	timeout  time.Duration
+	pattern  *regexp.Regexp
+	equals   interface{}
+
+	mu                   sync.Mutex
+	consecutiveFailures  int
+	consecutiveSuccesses int
+	cordoned             bool
+}
+
+func NewHealthProber(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	ntw common.Network,
+	up *Upstream,
+	tracker *health.Tracker,
+) (*HealthProber, error) {
+	lg := logger.With().Str("upstreamId", up.config.Id).Str("component", "healthProber").Logger()
+	p := &HealthProber{
+		logger:   &lg,
+		network:  ntw,
+		upstream: up,
+		tracker:  tracker,
+	}
+
+	if err := p.initialize(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *HealthProber) initialize(ctx context.Context) error {
+	cfg := p.upstream.config
+	if len(cfg.HealthProbes) == 0 {
+		return nil
+	}
+
+	for _, pcfg := range cfg.HealthProbes {
+		hp := &healthProbe{cfg: pcfg}
+		var err error
+		hp.interval, err = time.ParseDuration(pcfg.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid health probe interval: %v", err)
+		}
+		hp.timeout, err = time.ParseDuration(pcfg.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid health probe timeout: %v", err)
+		}
+		if pcfg.Expect != nil {
+			if pcfg.Expect.Pattern != "" {
+				hp.pattern, err = regexp.Compile(pcfg.Expect.Pattern)
+				if err != nil {
+					return fmt.Errorf("invalid health probe pattern: %v", err)
+				}
+			}
+			if pcfg.Expect.Equals != nil {
+				hp.equals, err = normalizeProbeValue(pcfg.Expect.Equals)
+				if err != nil {
+					return fmt.Errorf("invalid health probe expected value: %v", err)
+				}
+			}
+		}
+		p.probes = append(p.probes, hp)
+	}
+
+	p.Enabled = true
+	p.logger.Info().Int("probes", len(p.probes)).Msgf("bootstrapped health prober for upstream")
+
+	for _, hp := range p.probes {
+		go func(hp *healthProbe) {
+			ticker := time.NewTicker(hp.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					p.logger.Debug().Msg("shutting down health prober due to context cancellation")
+					return
+				case <-ticker.C:
+					p.Probe(ctx, hp)
+				}
+			}
+		}(hp)
+	}
+
+	return nil
+}
+
+// Probe sends a single probe to the upstream and updates cordon state based on the outcome.
+func (p *HealthProber) Probe(ctx context.Context, hp *healthProbe) {
+	upsId := p.upstream.config.Id
+	netId := p.network.Id()
+	method := hp.cfg.Method
+
+	outcome, reason := p.execute(ctx, hp)
+	health.MetricUpstreamHealthProbeTotal.WithLabelValues(p.upstream.ProjectId, netId, upsId, method, outcome).Inc()
+
+	lg := p.logger.With().Str("method", method).Str("outcome", outcome).Logger()
+	if outcome == HealthProbeOutcomeSkipped {
+		lg.Debug().Str("reason", reason).Msg("health probe skipped")
+		return
+	}
+
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+
+	if outcome == HealthProbeOutcomeSuccess {
+		hp.consecutiveFailures = 0
+		hp.consecutiveSuccesses++
+		if hp.cordoned && hp.consecutiveSuccesses >= hp.cfg.SuccessThreshold {
+			lg.Info().Msg("health probe recovered, uncordoning upstream")
+			p.tracker.UncordonProbe(upsId, netId, hp.cfg.CordonMethod)
+			hp.cordoned = false
+		}
+		health.MetricUpstreamHealthProbeHealthy.WithLabelValues(p.upstream.ProjectId, netId, upsId, method).Set(1)
+		return
+	}
+
+	hp.consecutiveSuccesses = 0
+	hp.consecutiveFailures++
+	lg.Debug().Str("reason", reason).Int("consecutiveFailures", hp.consecutiveFailures).Msg("health probe failed")
+	if hp.consecutiveFailures >= hp.cfg.FailureThreshold {
+		if !hp.cordoned {
+			lg.Warn().Str("reason", reason).Msg("health probe failed consecutively, cordoning upstream")
+		}
+		// Probe cordon is kept apart from selection policy cordons, so neither lifts the other one.
+		p.tracker.CordonByProbe(upsId, netId, hp.cfg.CordonMethod, fmt.Sprintf("failed health probe %s: %s", method, reason))
+		hp.cordoned = true
+		health.MetricUpstreamHealthProbeHealthy.WithLabelValues(p.upstream.ProjectId, netId, upsId, method).Set(0)
+	}
+}
+
+func (p *HealthProber) execute(ctx context.Context, hp *healthProbe) (outcome string, reason string) {
+	params, err := common.SonicCfg.Marshal(hp.cfg.Params)
+	if err != nil {
+		return HealthProbeOutcomeFailure, err.Error()
+	}
+	pr := common.NewNormalizedRequest([]byte(
+		fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":%s}`, util.RandomID(), hp.cfg.Method, params),
+	))
+	pr.SetNetwork(p.network)
+
+	pctx, cancel := context.WithTimeout(ctx, hp.timeout)
+	defer cancel()
+
+	// Forward applies the upstream's rate limit budget and records request/failure metrics,
+	// which means probe results are also reflected in the upstream score.
+	resp, err := p.upstream.Forward(pctx, pr, true)
+	if err != nil {
+		if common.IsCapacityIssue(err) {
+			return HealthProbeOutcomeSkipped, common.ErrorSummary(err)
+		}
+		return HealthProbeOutcomeFailure, common.ErrorSummary(err)
+	}
+
+	jrr, err := resp.JsonRpcResponse()
+	if err != nil {
+		return HealthProbeOutcomeFailure, err.Error()
+	}
+	if jrr == nil {
+		return HealthProbeOutcomeFailure, "empty response"
+	}
+	if jrr.Error != nil {
+		return HealthProbeOutcomeFailure, jrr.Error.Message
+	}
+
+	if mismatch := hp.match(resp, jrr.Result); mismatch != "" {
+		p.tracker.RecordUpstreamFailure(p.upstream.config.Id, p.network.Id(), hp.cfg.Method, "HealthProbeMismatch")
+		return HealthProbeOutcomeMismatch, mismatch
+	}
+
+	return HealthProbeOutcomeSuccess, ""
+}
+
+// match returns a non-empty reason when the result does not satisfy the expected conditions.
+func (hp *healthProbe) match(resp *common.NormalizedResponse, result []byte) string {
+	exp := hp.cfg.Expect
+	if exp == nil {
+		return ""
+	}
+	if exp.NotEmpty && resp.IsResultEmptyish() {
+		return "result is empty"
+	}
+	if hp.pattern != nil && !hp.pattern.Match(result) {
+		return fmt.Sprintf("result does not match pattern %s", hp.pattern.String())
+	}
+	if hp.equals != nil {
+		var actual interface{}
+		if err := common.SonicCfg.Unmarshal(result, &actual); err != nil {
+			return fmt.Sprintf("cannot parse result: %v", err)
+		}
+		if !reflect.DeepEqual(hp.equals, actual) {
+			return fmt.Sprintf("result %s does not equal expected value", util.Mem2Str(result))
+		}
+	}
+	return ""
+}
+
+// normalizeProbeValue round-trips a config value through JSON so it can be compared with parsed results.
+func normalizeProbeValue(v interface{}) (interface{}, error) {
+	b, err := common.SonicCfg.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := common.SonicCfg.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
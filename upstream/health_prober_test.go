@@ -0,0 +1,67 @@
+package upstream
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthProber_Match(t *testing.T) {
+	newResponse := func(t *testing.T, result string) (*common.NormalizedResponse, []byte) {
+		jrr, err := common.NewJsonRpcResponseFromBytes([]byte(`1`), []byte(result), nil)
+		assert.NoError(t, err)
+		return common.NewNormalizedResponse().WithJsonRpcResponse(jrr), []byte(result)
+	}
+
+	t.Run("NoExpectationAlwaysMatches", func(t *testing.T) {
+		hp := &healthProbe{cfg: &common.HealthProbeConfig{Method: "eth_chainId"}}
+		resp, result := newResponse(t, `"0x0"`)
+		assert.Empty(t, hp.match(resp, result))
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		hp := &healthProbe{cfg: &common.HealthProbeConfig{
+			Method: "eth_getBlockByNumber",
+			Expect: &common.HealthProbeExpectConfig{NotEmpty: true},
+		}}
+		resp, result := newResponse(t, `null`)
+		assert.Equal(t, "result is empty", hp.match(resp, result))
+
+		resp, result = newResponse(t, `{"number":"0x1"}`)
+		assert.Empty(t, hp.match(resp, result))
+	})
+
+	t.Run("Pattern", func(t *testing.T) {
+		hp := &healthProbe{
+			cfg: &common.HealthProbeConfig{
+				Method: "eth_call",
+				Expect: &common.HealthProbeExpectConfig{Pattern: "^\"0x[0-9a-f]{64}\"$"},
+			},
+			pattern: regexp.MustCompile("^\"0x[0-9a-f]{64}\"$"),
+		}
+		resp, result := newResponse(t, `"0x"`)
+		assert.NotEmpty(t, hp.match(resp, result))
+
+		resp, result = newResponse(t, `"0x0000000000000000000000000000000000000000000000000000000000000001"`)
+		assert.Empty(t, hp.match(resp, result))
+	})
+
+	t.Run("Equals", func(t *testing.T) {
+		equals, err := normalizeProbeValue(map[string]interface{}{"chainId": 1})
+		assert.NoError(t, err)
+		hp := &healthProbe{
+			cfg: &common.HealthProbeConfig{
+				Method: "custom_status",
+				Expect: &common.HealthProbeExpectConfig{Equals: map[string]interface{}{"chainId": 1}},
+			},
+			equals: equals,
+		}
+		resp, result := newResponse(t, `{"chainId":2}`)
+		assert.NotEmpty(t, hp.match(resp, result))
+
+		resp, result = newResponse(t, `{"chainId":1}`)
+		assert.Empty(t, hp.match(resp, result))
+	})
+}
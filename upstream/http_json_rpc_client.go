@@ -16,6 +16,7 @@ import (
 
 	"github.com/bytedance/sonic/ast"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/rs/zerolog"
 )
@@ -557,6 +558,16 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 		return nil, err
 	}
 
+	ctx, span := telemetry.StartSpan(
+		ctx,
+		telemetry.SpanKindClient,
+		"erpc.upstream.call",
+		telemetry.String("erpc.upstream", c.upstream.Config().Id),
+		telemetry.String("rpc.method", jrReq.Method),
+		telemetry.String("server.address", c.Url.Host),
+	)
+	defer span.End()
+
 	reqStartTime := time.Now()
 	httpReq, err := c.prepareRequest(ctx, requestBody)
 	if err != nil {
@@ -574,6 +585,7 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		span.RecordError(err)
 		cause := context.Cause(ctx)
 		if cause == nil {
 			cause = ctx.Err()
@@ -603,7 +615,11 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 		WithBody(bodyReader).
 		WithExpectedSize(int(resp.ContentLength))
 
-	return nr, c.normalizeJsonRpcError(resp, nr)
+	span.SetAttributes(telemetry.Int("http.response.status_code", resp.StatusCode))
+	err = c.normalizeJsonRpcError(resp, nr)
+	span.RecordError(err)
+
+	return nr, err
 }
 
 func (c *GenericHttpJsonRpcClient) prepareRequest(ctx context.Context, body []byte) (*http.Request, error) {
@@ -637,6 +653,7 @@ func (c *GenericHttpJsonRpcClient) prepareRequest(ctx context.Context, body []by
 	}
 
 	httpReq.Header.Set("Accept-Encoding", "gzip")
+	telemetry.Inject(ctx, httpReq.Header)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("User-Agent", fmt.Sprintf("erpc (%s/%s; Project/%s; Budget/%s)",
 		common.ErpcVersion,
@@ -732,6 +749,17 @@ func extractJsonRpcError(r *http.Response, nr *common.NormalizedResponse, jr *co
 		var details map[string]interface{} = make(map[string]interface{})
 		details["statusCode"] = r.StatusCode
 		details["headers"] = util.ExtractUsefulHeaders(r)
+		if resetAt, limit, window := util.ExtractRateLimitHints(r.Header, time.Now()); !resetAt.IsZero() || limit > 0 {
+			if !resetAt.IsZero() {
+				details["rateLimitResetAt"] = resetAt
+			}
+			if limit > 0 {
+				details["rateLimitLimit"] = limit
+				if window > 0 {
+					details["rateLimitWindow"] = window
+				}
+			}
+		}
 
 		if ver := getVendorSpecificErrorIfAny(r, nr, jr, details); ver != nil {
 			return ver
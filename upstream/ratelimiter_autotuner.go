@@ -39,6 +39,7 @@ func NewRateLimitAutoTuner(
 	maxBudget int,
 ) *RateLimitAutoTuner {
 	return &RateLimitAutoTuner{
+		logger:             logger,
 		budget:             budget,
 		errorCounts:        make(map[string]*ErrorCounter),
 		lastAdjustments:    make(map[string]time.Time),
@@ -116,3 +117,45 @@ func (arl *RateLimitAutoTuner) adjustBudget(method string) {
 		arl.errorCounts[method].totalCount = 0
 	}
 }
+
+// RecordLimitHint lowers the budget of rules matching the method to the limit reported by the
+// upstream itself (e.g. X-RateLimit-Limit header), since the vendor knows its own limits better
+// than our error-rate based estimation. The limit is scaled from the vendor window to the period of
+// each rule, and ignored when the window is unknown. The budget is never raised by a hint and stays
+// within min/max bounds.
+func (arl *RateLimitAutoTuner) RecordLimitHint(method string, limit int, window time.Duration) {
+	if limit <= 0 || window <= 0 {
+		return
+	}
+
+	arl.mu.Lock()
+	defer arl.mu.Unlock()
+
+	rules, err := arl.budget.GetRulesByMethod(method)
+	if err != nil {
+		arl.logger.Warn().Err(err).Msgf("failed to get rules for method %s", method)
+		return
+	}
+
+	for _, rule := range rules {
+		period, err := time.ParseDuration(rule.Config.Period)
+		if err != nil || period <= 0 {
+			continue
+		}
+		newMaxCount := int(math.Floor(float64(limit) * float64(period) / float64(window)))
+		if arl.minBudget > 0 && newMaxCount < arl.minBudget {
+			newMaxCount = arl.minBudget
+		}
+		if arl.maxBudget > 0 && newMaxCount > arl.maxBudget {
+			newMaxCount = arl.maxBudget
+		}
+		if newMaxCount <= 0 || uint(newMaxCount) >= rule.Config.MaxCount {
+			continue
+		}
+		if err := arl.budget.AdjustBudget(rule, uint(newMaxCount)); err != nil {
+			arl.logger.Warn().Err(err).Msgf("failed to adjust budget for method %s based on upstream hint", method)
+		}
+	}
+
+	arl.lastAdjustments[method] = time.Now()
+}
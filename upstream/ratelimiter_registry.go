@@ -2,6 +2,7 @@ package upstream
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -108,3 +109,84 @@ func (r *RateLimitersRegistry) GetBudget(budgetId string) (*RateLimiterBudget, e
 func (r *RateLimitersRegistry) GetBudgets() []*common.RateLimitBudgetConfig {
 	return r.cfg.Budgets
 }
+
+// BudgetRuleSnapshot holds the current max count of a rule whose budget was adjusted at runtime (e.g. by auto-tuner).
+type BudgetRuleSnapshot struct {
+	Budget   string `json:"budget"`
+	Index    int    `json:"index"`
+	Method   string `json:"method"`
+	MaxCount uint   `json:"maxCount"`
+}
+
+// SnapshotBudgets returns rules whose max count differs from the configured value.
+func (r *RateLimitersRegistry) SnapshotBudgets() []*BudgetRuleSnapshot {
+	snapshot := make([]*BudgetRuleSnapshot, 0)
+	if r.cfg == nil {
+		return snapshot
+	}
+
+	for _, budgetCfg := range r.cfg.Budgets {
+		budget, err := r.GetBudget(budgetCfg.Id)
+		if err != nil || budget == nil {
+			continue
+		}
+		budget.rulesMu.RLock()
+		for i, rule := range budget.Rules {
+			if i >= len(budgetCfg.Rules) || rule.Config.MaxCount == budgetCfg.Rules[i].MaxCount {
+				continue
+			}
+			snapshot = append(snapshot, &BudgetRuleSnapshot{
+				Budget:   budgetCfg.Id,
+				Index:    i,
+				Method:   rule.Config.Method,
+				MaxCount: rule.Config.MaxCount,
+			})
+		}
+		budget.rulesMu.RUnlock()
+	}
+
+	return snapshot
+}
+
+// RestoreBudgets re-applies previously adjusted max counts, moved back towards the configured value
+// based on the decay factor (1 means as-is, 0 means configured value).
+func (r *RateLimitersRegistry) RestoreBudgets(snapshot []*BudgetRuleSnapshot, decay float64) {
+	if r.cfg == nil {
+		return
+	}
+	decay = math.Max(0, math.Min(1, decay))
+
+	for _, rs := range snapshot {
+		budget, err := r.GetBudget(rs.Budget)
+		if err != nil || budget == nil {
+			continue
+		}
+		var configured *common.RateLimitRuleConfig
+		for _, budgetCfg := range r.cfg.Budgets {
+			if budgetCfg.Id == rs.Budget && rs.Index < len(budgetCfg.Rules) {
+				configured = budgetCfg.Rules[rs.Index]
+			}
+		}
+		if configured == nil || configured.Method != rs.Method {
+			// Config has changed since the snapshot was taken
+			continue
+		}
+
+		budget.rulesMu.RLock()
+		if rs.Index >= len(budget.Rules) {
+			budget.rulesMu.RUnlock()
+			continue
+		}
+		rule := budget.Rules[rs.Index]
+		budget.rulesMu.RUnlock()
+
+		target := float64(configured.MaxCount) + (float64(rs.MaxCount)-float64(configured.MaxCount))*decay
+		newMaxCount := uint(math.Max(1, math.Round(target)))
+		if newMaxCount == rule.Config.MaxCount {
+			continue
+		}
+		if err := budget.AdjustBudget(rule, newMaxCount); err != nil {
+			r.logger.Warn().Err(err).Str("budget", rs.Budget).Str("method", rs.Method).Msg("failed to restore rate limiter budget")
+		}
+	}
+}
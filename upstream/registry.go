@@ -40,6 +40,8 @@ type UpstreamsHealth struct {
 	Upstreams       []*Upstream                              `json:"upstreams"`
 	SortedUpstreams map[string]map[string][]string           `json:"sortedUpstreams"`
 	UpstreamScores  map[string]map[string]map[string]float64 `json:"upstreamScores"`
+	// upstreamId -> scope key (e.g. method or method group) -> state
+	CircuitBreakers map[string]map[string]string `json:"circuitBreakers,omitempty"`
 }
 
 func NewUpstreamsRegistry(
@@ -322,6 +324,79 @@ func (u *UpstreamsRegistry) sortAndFilterUpstreams(networkId, method string, ups
 	return activeUpstreams
 }
 
+// SelectUpstreams returns the order in which upstreams are tried for a single request. The list is
+// already sorted by score (and cordoned upstreams filtered) in the background, "ordered" mode uses it as is
+// while other modes randomize the order per request to spread the load while still favoring better upstreams.
+func (u *UpstreamsRegistry) SelectUpstreams(networkId, method string, upsList []*Upstream, mode common.UpstreamSelectionMode) []*Upstream {
+	if len(upsList) < 2 {
+		return upsList
+	}
+
+	switch mode {
+	case common.UpstreamSelectionModeWeightedRandom:
+		return u.selectWeightedRandom(networkId, method, upsList)
+	case common.UpstreamSelectionModePowerOfTwoChoices:
+		return u.selectPowerOfTwoChoices(networkId, method, upsList)
+	default:
+		return upsList
+	}
+}
+
+// selectWeightedRandom orders upstreams by weighted random sampling without replacement (Efraimidis-Spirakis),
+// where each upstream's weight is its score. Upstreams without a positive score go last in their original order.
+func (u *UpstreamsRegistry) selectWeightedRandom(networkId, method string, upsList []*Upstream) []*Upstream {
+	keys := make(map[*Upstream]float64, len(upsList))
+	u.upstreamsMu.RLock()
+	for _, ups := range upsList {
+		score := u.upstreamScores[ups.Config().Id][networkId][method]
+		if score > 0 {
+			keys[ups] = -math.Log(1-rand.Float64()) / score
+		} else {
+			keys[ups] = math.Inf(1)
+		}
+	}
+	u.upstreamsMu.RUnlock()
+
+	selected := make([]*Upstream, len(upsList))
+	copy(selected, upsList)
+	sort.SliceStable(selected, func(i, j int) bool {
+		return keys[selected[i]] < keys[selected[j]]
+	})
+
+	return selected
+}
+
+// selectPowerOfTwoChoices repeatedly picks two random upstreams among remaining ones and takes the one with
+// lower expected wait, i.e. (in-flight requests + 1) * p90 latency, which avoids herding on a single upstream.
+func (u *UpstreamsRegistry) selectPowerOfTwoChoices(networkId, method string, upsList []*Upstream) []*Upstream {
+	remaining := make([]*Upstream, len(upsList))
+	copy(remaining, upsList)
+	selected := make([]*Upstream, 0, len(upsList))
+
+	cost := func(ups *Upstream) float64 {
+		metrics := u.metricsTracker.GetUpstreamMethodMetrics(ups.Config().Id, networkId, method)
+		// Upstreams without latency data yet are assumed to be fast so that they receive some traffic
+		latency := math.Max(metrics.LatencySecs.P90(), 0.001)
+		return float64(metrics.InFlightRequests.Load()+1) * latency
+	}
+
+	for len(remaining) > 1 {
+		i := rand.Intn(len(remaining))
+		j := rand.Intn(len(remaining) - 1)
+		if j >= i {
+			j++
+		}
+		pick := i
+		if cost(remaining[j]) < cost(remaining[i]) {
+			pick = j
+		}
+		selected = append(selected, remaining[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+	}
+
+	return append(selected, remaining...)
+}
+
 func (u *UpstreamsRegistry) RefreshUpstreamNetworkMethodScores() error {
 	u.upstreamsMu.Lock()
 	defer u.upstreamsMu.Unlock()
@@ -397,7 +472,7 @@ func (u *UpstreamsRegistry) scheduleScoreCalculationTimers(ctx context.Context)
 }
 
 func (u *UpstreamsRegistry) updateScoresAndSort(networkId, method string, upsList []*Upstream) {
-	var p90Latencies, errorRates, totalRequests, throttledRates, blockHeadLags, finalizationLags []float64
+	var p90Latencies, errorRates, totalRequests, throttledRates, blockHeadLags, finalizationLags, inFlights []float64
 
 	for _, ups := range upsList {
 		metrics := u.metricsTracker.GetUpstreamMethodMetrics(ups.Config().Id, networkId, method)
@@ -407,6 +482,7 @@ func (u *UpstreamsRegistry) updateScoresAndSort(networkId, method string, upsLis
 		errorRates = append(errorRates, metrics.ErrorRate())
 		throttledRates = append(throttledRates, metrics.ThrottledRate())
 		totalRequests = append(totalRequests, float64(metrics.RequestsTotal.Load()))
+		inFlights = append(inFlights, float64(metrics.InFlightRequests.Load()))
 	}
 
 	normP90Latencies := normalizeValues(p90Latencies)
@@ -415,6 +491,7 @@ func (u *UpstreamsRegistry) updateScoresAndSort(networkId, method string, upsLis
 	normTotalRequests := normalizeValues(totalRequests)
 	normBlockHeadLags := normalizeValues(blockHeadLags)
 	normFinalizationLags := normalizeValues(finalizationLags)
+	normInFlights := normalizeValues(inFlights)
 	for i, ups := range upsList {
 		upsId := ups.Config().Id
 		score := u.calculateScore(
@@ -427,6 +504,7 @@ func (u *UpstreamsRegistry) updateScoresAndSort(networkId, method string, upsLis
 			normThrottledRates[i],
 			normBlockHeadLags[i],
 			normFinalizationLags[i],
+			normInFlights[i],
 		)
 		// Upstream might not have scores initialized yet (especially when networkId is *)
 		// TODO add a test case to send request to network A when network B is defined in config but no requests sent yet
@@ -451,7 +529,8 @@ func (u *UpstreamsRegistry) calculateScore(
 	normErrorRate,
 	normThrottledRate,
 	normBlockHeadLag,
-	normFinalizationLag float64,
+	normFinalizationLag,
+	normInFlight float64,
 ) float64 {
 	mul := ups.getScoreMultipliers(networkId, method)
 
@@ -487,6 +566,11 @@ func (u *UpstreamsRegistry) calculateScore(
 		score += expCurve(1-normFinalizationLag) * mul.FinalizationLag
 	}
 
+	// Higher score for fewer requests currently in-flight
+	if mul.InFlight > 0 {
+		score += expCurve(1-normInFlight) * mul.InFlight
+	}
+
 	return score * mul.Overall
 }
 
@@ -549,10 +633,18 @@ func (u *UpstreamsRegistry) GetUpstreamsHealth() (*UpstreamsHealth, error) {
 		}
 	}
 
+	circuitBreakers := make(map[string]map[string]string)
+	for _, ups := range u.allUpstreams {
+		if states := ups.CircuitBreakerStates(); len(states) > 0 {
+			circuitBreakers[ups.Config().Id] = states
+		}
+	}
+
 	return &UpstreamsHealth{
 		Upstreams:       u.allUpstreams,
 		SortedUpstreams: sortedUpstreams,
 		UpstreamScores:  upstreamScores,
+		CircuitBreakers: circuitBreakers,
 	}, nil
 }
 
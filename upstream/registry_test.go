@@ -457,6 +457,7 @@ func TestUpstreamsRegistry_DynamicScenarios(t *testing.T) {
 					ups.throttledRate,
 					ups.blockHeadLag,
 					ups.finalizationLag,
+					0,
 				)
 				scores[i] = float64(score)
 				totalScore += float64(score)
@@ -720,6 +721,7 @@ func TestUpstreamsRegistry_Multiplier(t *testing.T) {
 					ups.metrics.throttledRate,
 					ups.metrics.blockHeadLag,
 					ups.metrics.finalizationLag,
+					0,
 				)
 				scores[i] = float64(score)
 				totalScore += float64(score)
@@ -738,6 +740,65 @@ func TestUpstreamsRegistry_Multiplier(t *testing.T) {
 	}
 }
 
+func TestUpstreamsRegistry_SelectionModes(t *testing.T) {
+	logger := log.Logger
+	projectID := "test-project"
+	networkID := "evm:123"
+	method := "eth_call"
+
+	t.Run("WeightedRandomSpreadsLoadFavoringHigherScores", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		registry, metricsTracker := createTestRegistry(ctx, projectID, &logger, 10*time.Hour)
+		_, _ = registry.GetSortedUpstreams(networkID, method)
+
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-a", method, 10, 0.10)
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-b", method, 10, 0.20)
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-c", method, 10, 0.30)
+		registry.RefreshUpstreamNetworkMethodScores()
+
+		upsList, err := registry.GetSortedUpstreams(networkID, method)
+		assert.NoError(t, err)
+
+		firsts := map[string]int{}
+		for i := 0; i < 2000; i++ {
+			selected := registry.SelectUpstreams(networkID, method, upsList, common.UpstreamSelectionModeWeightedRandom)
+			assert.Len(t, selected, len(upsList))
+			firsts[selected[0].Config().Id]++
+		}
+
+		assert.Greater(t, firsts["upstream-a"], firsts["upstream-b"])
+		assert.Greater(t, firsts["upstream-b"], 0)
+		assert.Greater(t, firsts["upstream-c"], 0)
+		// The pre-sorted list must not be mutated
+		assert.Equal(t, "upstream-a", upsList[0].Config().Id)
+	})
+
+	t.Run("PowerOfTwoChoicesAvoidsBusyUpstream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		registry, metricsTracker := createTestRegistry(ctx, projectID, &logger, 10*time.Hour)
+		_, _ = registry.GetSortedUpstreams(networkID, method)
+
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-a", method, 10, 0.10)
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-b", method, 10, 0.10)
+		simulateRequestsWithLatency(metricsTracker, networkID, "upstream-c", method, 10, 0.10)
+		for i := 0; i < 50; i++ {
+			defer metricsTracker.RecordUpstreamInFlight("upstream-a", networkID, method)()
+		}
+
+		upsList, err := registry.GetSortedUpstreams(networkID, method)
+		assert.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			selected := registry.SelectUpstreams(networkID, method, upsList, common.UpstreamSelectionModePowerOfTwoChoices)
+			assert.Len(t, selected, len(upsList))
+			// Busy upstream always loses the comparison so it can only be tried last
+			assert.Equal(t, "upstream-a", selected[len(selected)-1].Config().Id)
+		}
+	})
+}
+
 func createTestRegistry(ctx context.Context, projectID string, logger *zerolog.Logger, windowSize time.Duration) (*UpstreamsRegistry, *health.Tracker) {
 	metricsTracker := health.NewTracker(projectID, windowSize)
 	metricsTracker.Bootstrap(ctx)
@@ -0,0 +1,96 @@
+package upstream
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+)
+
+const retryBudgetSlots = 10
+
+// RetryBudget is a token bucket shared by all retries and hedges of a network or upstream.
+// Each request deposits "ratio" tokens and each retry or hedge withdraws one token. Deposits and withdrawals
+// are tracked in time slots so that they expire after the rolling window, and "minRetries" tokens are always
+// available so that low-traffic entities can still retry.
+type RetryBudget struct {
+	ratio      float64
+	minRetries int64
+	slotSize   time.Duration
+
+	mu    sync.Mutex
+	slots [retryBudgetSlots]retryBudgetSlot
+}
+
+type retryBudgetSlot struct {
+	epoch       int64
+	requests    int64
+	withdrawals int64
+}
+
+func NewRetryBudget(cfg *common.RetryBudgetConfig) (*RetryBudget, error) {
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse retryBudget.window: %v", err)
+	}
+	slotSize := window / retryBudgetSlots
+	if slotSize <= 0 {
+		slotSize = time.Millisecond
+	}
+	b := &RetryBudget{slotSize: slotSize}
+	if cfg.Ratio != nil {
+		b.ratio = *cfg.Ratio
+	}
+	if cfg.MinRetries != nil {
+		b.minRetries = int64(*cfg.MinRetries)
+	}
+	return b, nil
+}
+
+// Deposit records a new request, which adds "ratio" tokens to the budget.
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentSlot(time.Now()).requests++
+}
+
+// TryWithdraw takes one token for a retry or hedge, returning false when the budget is exhausted.
+func (b *RetryBudget) TryWithdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.balance(now) < 1 {
+		return false
+	}
+	b.currentSlot(now).withdrawals++
+	return true
+}
+
+// Exhausted returns true when there is no token left for another retry or hedge.
+func (b *RetryBudget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balance(time.Now()) < 1
+}
+
+func (b *RetryBudget) currentSlot(now time.Time) *retryBudgetSlot {
+	epoch := now.UnixNano() / int64(b.slotSize)
+	slot := &b.slots[epoch%retryBudgetSlots]
+	if slot.epoch != epoch {
+		*slot = retryBudgetSlot{epoch: epoch}
+	}
+	return slot
+}
+
+func (b *RetryBudget) balance(now time.Time) float64 {
+	epoch := now.UnixNano() / int64(b.slotSize)
+	var requests, withdrawals int64
+	for _, slot := range b.slots {
+		if slot.epoch > epoch-retryBudgetSlots {
+			requests += slot.requests
+			withdrawals += slot.withdrawals
+		}
+	}
+	return float64(b.minRetries) + float64(requests)*b.ratio - float64(withdrawals)
+}
@@ -0,0 +1,63 @@
+package upstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget(t *testing.T) {
+	t.Run("AllowsMinRetriesWithoutTraffic", func(t *testing.T) {
+		b, err := NewRetryBudget(&common.RetryBudgetConfig{Ratio: util.Float64Ptr(0.2), Window: "10s", MinRetries: util.IntPtr(2)})
+		require.NoError(t, err)
+
+		assert.True(t, b.TryWithdraw())
+		assert.True(t, b.TryWithdraw())
+		assert.False(t, b.TryWithdraw())
+		assert.True(t, b.Exhausted())
+	})
+
+	t.Run("CapsRetriesToRatioOfRequests", func(t *testing.T) {
+		b, err := NewRetryBudget(&common.RetryBudgetConfig{Ratio: util.Float64Ptr(0.2), Window: "10s"})
+		require.NoError(t, err)
+
+		for i := 0; i < 50; i++ {
+			b.Deposit()
+		}
+		allowed := 0
+		for i := 0; i < 50; i++ {
+			if b.TryWithdraw() {
+				allowed++
+			}
+		}
+		assert.Equal(t, 10, allowed)
+	})
+
+	t.Run("ExplicitZeroRatioIsKept", func(t *testing.T) {
+		cfg := &common.RetryBudgetConfig{Ratio: util.Float64Ptr(0), MinRetries: util.IntPtr(1)}
+		cfg.SetDefaults()
+		require.NoError(t, cfg.Validate())
+		b, err := NewRetryBudget(cfg)
+		require.NoError(t, err)
+
+		for i := 0; i < 50; i++ {
+			b.Deposit()
+		}
+		assert.True(t, b.TryWithdraw())
+		assert.False(t, b.TryWithdraw(), "requests must not add tokens when ratio is 0")
+	})
+
+	t.Run("TokensExpireAfterWindow", func(t *testing.T) {
+		b, err := NewRetryBudget(&common.RetryBudgetConfig{Ratio: util.Float64Ptr(1), Window: "100ms"})
+		require.NoError(t, err)
+
+		b.Deposit()
+		assert.False(t, b.Exhausted())
+		time.Sleep(150 * time.Millisecond)
+		assert.True(t, b.Exhausted())
+	})
+}
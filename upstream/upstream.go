@@ -12,12 +12,16 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/health"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/erpc/erpc/vendors"
 	"github.com/failsafe-go/failsafe-go"
 	"github.com/rs/zerolog"
 )
 
+// Upper bound for how long an upstream is cordoned based on its own Retry-After or X-RateLimit-Reset hints.
+const maxRemoteRateLimitCordon = 5 * time.Minute
+
 type Upstream struct {
 	ProjectId string
 	Client    ClientInterface
@@ -28,11 +32,15 @@ type Upstream struct {
 	vendor common.Vendor
 
 	metricsTracker       *health.Tracker
-	timeoutDuration      *time.Duration
-	failsafeExecutor     failsafe.Executor[*common.NormalizedResponse]
+	failsafeExecutors    []*FailsafeExecutor
+	circuitBreakers      *circuitBreakers
 	rateLimitersRegistry *RateLimitersRegistry
 	rateLimiterAutoTuner *RateLimitAutoTuner
 
+	// Bulkhead to cap concurrent in-flight requests towards this upstream (nil when maxConcurrency is not set)
+	concurrencySlots        chan struct{}
+	concurrencyQueueTimeout time.Duration
+
 	methodCheckResults    map[string]bool
 	methodCheckResultsMu  sync.RWMutex
 	supportedNetworkIds   map[string]bool
@@ -56,24 +64,31 @@ func NewUpstream(
 ) (*Upstream, error) {
 	lg := logger.With().Str("upstreamId", cfg.Id).Logger()
 
-	policiesMap, err := CreateFailSafePolicies(&lg, common.ScopeUpstream, cfg.Id, cfg.Failsafe)
+	failsafeExecutors, err := CreateFailSafeExecutors(&lg, common.ScopeUpstream, cfg.Id, cfg.Failsafe, mt)
 	if err != nil {
 		return nil, err
 	}
-	policiesArray := []failsafe.Policy[*common.NormalizedResponse]{}
-	for _, policy := range policiesMap {
-		policiesArray = append(policiesArray, policy)
-	}
 
-	var timeoutDuration *time.Duration
-	if cfg.Failsafe != nil && cfg.Failsafe.Timeout != nil {
-		d, err := time.ParseDuration(cfg.Failsafe.Timeout.Duration)
-		timeoutDuration = &d
+	var cbs *circuitBreakers
+	if cfg.Failsafe != nil && cfg.Failsafe.CircuitBreaker != nil {
+		cbs, err = newCircuitBreakers(&lg, projectId, cfg.Id, cfg.Failsafe.CircuitBreaker)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	var concurrencySlots chan struct{}
+	var concurrencyQueueTimeout time.Duration
+	if cfg.MaxConcurrency > 0 {
+		concurrencySlots = make(chan struct{}, cfg.MaxConcurrency)
+		if cfg.MaxConcurrencyQueueTimeout != "" {
+			concurrencyQueueTimeout, err = time.ParseDuration(cfg.MaxConcurrencyQueueTimeout)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	vn := vr.LookupByUpstream(cfg)
 
 	pup := &Upstream{
@@ -84,11 +99,14 @@ func NewUpstream(
 		config:               cfg,
 		vendor:               vn,
 		metricsTracker:       mt,
-		timeoutDuration:      timeoutDuration,
-		failsafeExecutor:     failsafe.NewExecutor[*common.NormalizedResponse](policiesArray...),
+		failsafeExecutors:    failsafeExecutors,
+		circuitBreakers:      cbs,
 		rateLimitersRegistry: rlr,
 		methodCheckResults:   map[string]bool{},
 		supportedNetworkIds:  map[string]bool{},
+
+		concurrencySlots:        concurrencySlots,
+		concurrencyQueueTimeout: concurrencyQueueTimeout,
 	}
 
 	pup.initRateLimitAutoTuner()
@@ -263,6 +281,17 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 		}
 	}
 
+	//
+	// Apply max concurrency bulkhead
+	//
+	release, err := u.acquireConcurrencySlot(ctx)
+	if err != nil {
+		lg.Debug().Err(err).Int("maxConcurrency", cfg.MaxConcurrency).Msgf("upstream is saturated, skipping")
+		health.MetricUpstreamMaxConcurrencyReachedTotal.WithLabelValues(u.ProjectId, netId, cfg.Id, method).Inc()
+		return nil, err
+	}
+	defer release()
+
 	//
 	// Prepare and normalize the request object
 	//
@@ -310,6 +339,8 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 			)
 			timer := u.metricsTracker.RecordUpstreamDurationStart(cfg.Id, netId, method)
 			defer timer.ObserveDuration()
+			inFlightDone := u.metricsTracker.RecordUpstreamInFlight(cfg.Id, netId, method)
+			defer inFlightDone()
 			resp, errCall := jsonRpcClient.SendRequest(ctx, req)
 			if resp != nil {
 				jrr, _ := resp.JsonRpcResponse()
@@ -344,7 +375,7 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 					}
 				} else {
 					if common.HasErrorCode(errCall, common.ErrCodeEndpointCapacityExceeded) {
-						u.recordRemoteRateLimit(netId, method)
+						u.recordRemoteRateLimit(netId, method, errCall)
 					} else if common.HasErrorCode(errCall, common.ErrCodeUpstreamRequestSkipped) {
 						health.MetricUpstreamSkippedTotal.WithLabelValues(u.ProjectId, cfg.Id, netId, method).Inc()
 					} else if common.HasErrorCode(errCall, common.ErrCodeEndpointMissingData) {
@@ -393,7 +424,17 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 			return resp, nil
 		}
 
-		executor := u.failsafeExecutor
+		fe := PickFailsafeExecutor(u.failsafeExecutors, method)
+		if fe.RetryBudget != nil {
+			fe.RetryBudget.Deposit()
+		}
+		executor := fe.Executor
+		if u.circuitBreakers != nil {
+			executor, err = u.circuitBreakers.executor(fe, method)
+			if err != nil {
+				return nil, err
+			}
+		}
 		resp, execErr := executor.
 			WithContext(ctx).
 			GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
@@ -406,19 +447,31 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 						return nil, ctxErr
 					}
 				}
-				if u.timeoutDuration != nil {
+				if timeout := fe.TimeoutFor(u.latencyQuantile(netId, method)); timeout != nil {
 					var cancelFn context.CancelFunc
 					ectx, cancelFn = context.WithTimeoutCause(
-						ectx, *u.timeoutDuration,
+						ectx, *timeout,
 						// TODO 5ms is a workaround to ensure context carries the timeout deadline (used when calling upstreams),
 						//      but allow the failsafe execution to fail with timeout first for proper error handling.
 						//      Is there a way to do this cleanly? e.g. if failsafe lib works via context rather than Ticker?
-						common.NewErrEndpointRequestTimeout(*u.timeoutDuration+5*time.Millisecond),
+						common.NewErrEndpointRequestTimeout(*timeout+5*time.Millisecond),
 					)
 					defer cancelFn()
 				}
 
-				return tryForward(ectx, exec)
+				ectx, span := telemetry.StartSpan(
+					ectx,
+					telemetry.SpanKindInternal,
+					"erpc.upstream.attempt",
+					telemetry.String("erpc.upstream", cfg.Id),
+					telemetry.Int("erpc.attempts", exec.Attempts()),
+					telemetry.Int("erpc.retries", exec.Retries()),
+					telemetry.Int("erpc.hedges", exec.Hedges()),
+				)
+				resp, err := tryForward(ectx, exec)
+				span.RecordError(err)
+				span.End()
+				return resp, err
 			})
 
 		if _, ok := execErr.(common.StandardError); !ok {
@@ -445,10 +498,20 @@ func (u *Upstream) Forward(ctx context.Context, req *common.NormalizedRequest, b
 	}
 }
 
-func (u *Upstream) Executor() failsafe.Executor[*common.NormalizedResponse] {
-	// TODO extend this to per-network and/or per-method because of either upstream performance diff
-	// or if user wants diff policies (retry/cb/integrity) per network/method.
-	return u.failsafeExecutor
+// Executor returns the failsafe executor matching the method (based on failsafe.methodOverrides).
+func (u *Upstream) Executor(method string) failsafe.Executor[*common.NormalizedResponse] {
+	// TODO extend this to per-network because of either upstream performance diff
+	// or if user wants diff policies (retry/cb/integrity) per network.
+	return PickFailsafeExecutor(u.failsafeExecutors, method).Executor
+}
+
+// CircuitBreakerStates returns the state of each circuit breaker of the upstream keyed by scope key
+// (e.g. method or method group name), or nil when no circuit breaker is configured.
+func (u *Upstream) CircuitBreakerStates() map[string]string {
+	if u.circuitBreakers == nil {
+		return nil
+	}
+	return u.circuitBreakers.States()
 }
 
 func (u *Upstream) EvmGetChainId(ctx context.Context) (string, error) {
@@ -564,14 +627,34 @@ func (u *Upstream) recordRequestSuccess(method string) {
 	}
 }
 
-func (u *Upstream) recordRemoteRateLimit(netId, method string) {
+func (u *Upstream) recordRemoteRateLimit(netId, method string, err error) {
 	u.metricsTracker.RecordUpstreamRemoteRateLimited(
 		u.config.Id,
 		netId,
 		method,
 	)
 
+	var resetAt time.Time
+	var limit int
+	var window time.Duration
+	var cee *common.ErrEndpointCapacityExceeded
+	if errors.As(err, &cee) {
+		resetAt, limit, window = cee.RateLimitHints()
+	}
+
+	if !resetAt.IsZero() {
+		// Avoid cordoning for too long in case upstream returns an unreasonable value
+		maxUntil := time.Now().Add(maxRemoteRateLimitCordon)
+		if resetAt.After(maxUntil) {
+			resetAt = maxUntil
+		}
+		u.metricsTracker.CordonUntil(u.config.Id, netId, method, resetAt, "remote rate limited until reset time")
+	}
+
 	if u.rateLimiterAutoTuner != nil {
+		if limit > 0 {
+			u.rateLimiterAutoTuner.RecordLimitHint(method, limit, window)
+		}
 		u.rateLimiterAutoTuner.RecordError(method)
 	}
 }
@@ -747,6 +830,20 @@ func (u *Upstream) shouldSkip(req *common.NormalizedRequest) (reason error, skip
 		}
 	}
 
+	// Cordons with an expiry (e.g. until a rate limit resets) take effect right away, rather than when
+	// the upstreams list is re-sorted on the next scores refresh.
+	if u.metricsTracker != nil {
+		if ntw := req.Network(); ntw != nil && u.metricsTracker.IsTemporarilyCordoned(u.config.Id, ntw.Id(), method) {
+			return common.NewErrUpstreamCordoned(u.config.Id, method), true
+		}
+	}
+
+	if u.config.Evm != nil && (u.config.Evm.MaxBlockHeadLag > 0 || u.config.Evm.MaxFinalizationLag > 0) {
+		if err := u.checkBlockLag(req); err != nil {
+			return err, true
+		}
+	}
+
 	// if block can be determined from request and upstream is only full-node and block is historical skip
 	if u.config.Evm != nil && u.config.Evm.MaxAvailableRecentBlocks > 0 {
 		if u.config.Evm.NodeType == common.EvmNodeTypeFull {
@@ -778,6 +875,100 @@ func (u *Upstream) shouldSkip(req *common.NormalizedRequest) (reason error, skip
 	return nil, false
 }
 
+// checkBlockLag excludes the upstream when its current block head or finalization lag exceeds the configured
+// max, but only for requests targeting recent (or not yet finalized) blocks. Lag is re-evaluated on every request
+// so the upstream is automatically re-included once it catches up.
+func (u *Upstream) checkBlockLag(req *common.NormalizedRequest) error {
+	if u.metricsTracker == nil {
+		return nil
+	}
+	ntw := req.Network()
+	if ntw == nil {
+		return nil
+	}
+	blockRef, bn, _ := req.EvmBlockRefAndNumber()
+	// Requests that do not target a specific block (e.g. realtime methods, or block param omitted which
+	// defaults to latest) are served from the head of the chain
+	headRef := bn == 0 && (blockRef == "*" || blockRef == "")
+
+	var statePoller common.EvmStatePoller
+	if sp := ntw.EvmStatePollerOf(u.config.Id); sp != nil && !sp.IsObjectNull() {
+		statePoller = sp
+	}
+
+	cfg := u.config.Evm
+	mts := u.metricsTracker.GetUpstreamMethodMetrics(u.config.Id, ntw.Id(), "*")
+
+	if cfg.MaxBlockHeadLag > 0 {
+		if lag := mts.BlockHeadLag.Load(); lag > cfg.MaxBlockHeadLag {
+			recent := headRef || blockRef == "latest" || blockRef == "pending"
+			if !recent && bn > 0 && statePoller != nil {
+				recent = bn > statePoller.LatestBlock()
+			}
+			if recent {
+				return common.NewErrUpstreamLagging(u.config.Id, "blockHeadLag", lag, cfg.MaxBlockHeadLag)
+			}
+		}
+	}
+
+	if cfg.MaxFinalizationLag > 0 {
+		if lag := mts.FinalizationLag.Load(); lag > cfg.MaxFinalizationLag {
+			unfinalized := headRef || blockRef == "finalized" || blockRef == "safe"
+			if !unfinalized && bn > 0 && statePoller != nil {
+				unfinalized = bn > statePoller.FinalizedBlock()
+			}
+			if unfinalized {
+				return common.NewErrUpstreamLagging(u.config.Id, "finalizationLag", lag, cfg.MaxFinalizationLag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// acquireConcurrencySlot reserves one of the maxConcurrency slots of this upstream, waiting up to
+// maxConcurrencyQueueTimeout for a slot to be freed. The returned function must be called to release the slot.
+func (u *Upstream) acquireConcurrencySlot(ctx context.Context) (func(), error) {
+	if u.concurrencySlots == nil {
+		return func() {}, nil
+	}
+	release := func() { <-u.concurrencySlots }
+
+	select {
+	case u.concurrencySlots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if u.concurrencyQueueTimeout > 0 {
+		timer := time.NewTimer(u.concurrencyQueueTimeout)
+		defer timer.Stop()
+		select {
+		case u.concurrencySlots <- struct{}{}:
+			return release, nil
+		case <-timer.C:
+		case <-ctx.Done():
+			if cause := context.Cause(ctx); cause != nil {
+				return nil, cause
+			}
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, common.NewErrUpstreamMaxConcurrencyReached(u.config.Id, u.config.MaxConcurrency)
+}
+
+// latencyQuantile returns a function computing the observed latency (in seconds) of this upstream
+// for the method at a given quantile, used for adaptive timeouts.
+func (u *Upstream) latencyQuantile(netId, method string) func(q float64) float64 {
+	if u.metricsTracker == nil {
+		return nil
+	}
+	return func(q float64) float64 {
+		return u.metricsTracker.GetUpstreamMethodMetrics(u.config.Id, netId, method).LatencySecs.Quantile(q)
+	}
+}
+
 func (u *Upstream) getScoreMultipliers(networkId, method string) *common.ScoreMultiplierConfig {
 	if u.config.Routing != nil {
 		for _, mul := range u.config.Routing.ScoreMultipliers {
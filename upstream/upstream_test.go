@@ -1,9 +1,14 @@
 package upstream
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -251,3 +256,351 @@ func TestUpstream_SkipLogic(t *testing.T) {
 		assert.ErrorIs(t, reason, common.NewErrUpstreamMethodIgnored("eth_get_block_by_number", "test"))
 	})
 }
+
+func TestUpstream_MaxConcurrency(t *testing.T) {
+	t.Run("SkipsWhenSaturated", func(t *testing.T) {
+		upstream := &Upstream{
+			config: &common.UpstreamConfig{
+				Id:             "test",
+				MaxConcurrency: 1,
+			},
+			concurrencySlots: make(chan struct{}, 1),
+		}
+
+		release, err := upstream.acquireConcurrencySlot(context.Background())
+		assert.NoError(t, err)
+
+		_, err = upstream.acquireConcurrencySlot(context.Background())
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUpstreamMaxConcurrencyReached))
+
+		release()
+		release, err = upstream.acquireConcurrencySlot(context.Background())
+		assert.NoError(t, err)
+		release()
+	})
+
+	t.Run("QueuesUntilSlotIsFreed", func(t *testing.T) {
+		upstream := &Upstream{
+			config: &common.UpstreamConfig{
+				Id:             "test",
+				MaxConcurrency: 1,
+			},
+			concurrencySlots:        make(chan struct{}, 1),
+			concurrencyQueueTimeout: time.Second,
+		}
+
+		releaseFirst, err := upstream.acquireConcurrencySlot(context.Background())
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			releaseFirst()
+		}()
+
+		releaseSecond, err := upstream.acquireConcurrencySlot(context.Background())
+		assert.NoError(t, err)
+		releaseSecond()
+	})
+
+	t.Run("NoLimitWhenNotConfigured", func(t *testing.T) {
+		upstream := &Upstream{
+			config: &common.UpstreamConfig{
+				Id: "test",
+			},
+		}
+
+		for i := 0; i < 10; i++ {
+			_, err := upstream.acquireConcurrencySlot(context.Background())
+			assert.NoError(t, err)
+		}
+	})
+}
+
+type lagTestNetwork struct {
+	poller common.EvmStatePoller
+}
+
+func (n *lagTestNetwork) Id() string                               { return "evm:1" }
+func (n *lagTestNetwork) Architecture() common.NetworkArchitecture { return common.ArchitectureEvm }
+func (n *lagTestNetwork) Config() *common.NetworkConfig            { return &common.NetworkConfig{} }
+func (n *lagTestNetwork) EvmChainId() (int64, error)               { return 1, nil }
+func (n *lagTestNetwork) EvmStatePollerOf(string) common.EvmStatePoller {
+	return n.poller
+}
+
+type lagTestPoller struct {
+	latest    int64
+	finalized int64
+}
+
+func (p *lagTestPoller) LatestBlock() int64                      { return p.latest }
+func (p *lagTestPoller) FinalizedBlock() int64                   { return p.finalized }
+func (p *lagTestPoller) IsBlockFinalized(bn int64) (bool, error) { return bn <= p.finalized, nil }
+func (p *lagTestPoller) SuggestFinalizedBlock(int64)             {}
+func (p *lagTestPoller) SuggestLatestBlock(int64)                {}
+func (p *lagTestPoller) IsObjectNull() bool                      { return p == nil }
+
+func TestUpstream_BlockLagExclusion(t *testing.T) {
+	newRequest := func(body string) *common.NormalizedRequest {
+		req := common.NewNormalizedRequest([]byte(body))
+		req.SetNetwork(&lagTestNetwork{poller: &lagTestPoller{latest: 400, finalized: 300}})
+		return req
+	}
+
+	tracker := health.NewTracker("test", time.Minute)
+	tracker.GetUpstreamMethodMetrics("test", "evm:1", "*")
+	tracker.SetLatestBlockNumber("other", "evm:1", 1000)
+	tracker.SetLatestBlockNumber("test", "evm:1", 400)
+
+	upstream := &Upstream{
+		config: &common.UpstreamConfig{
+			Id: "test",
+			Evm: &common.EvmUpstreamConfig{
+				MaxBlockHeadLag: 100,
+			},
+		},
+		metricsTracker: tracker,
+	}
+
+	t.Run("SkipsLaggingUpstreamForLatestBlock", func(t *testing.T) {
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_getBlockByNumber","params":["latest",false]}`))
+		assert.True(t, skip)
+		assert.True(t, common.HasErrorCode(reason, common.ErrCodeUpstreamLagging))
+	})
+
+	t.Run("AllowsLaggingUpstreamForHistoricalBlock", func(t *testing.T) {
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_getBlockByNumber","params":["0x64",false]}`))
+		assert.False(t, skip)
+		assert.Nil(t, reason)
+	})
+
+	t.Run("SkipsLaggingUpstreamForRealtimeMethod", func(t *testing.T) {
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_blockNumber","params":[]}`))
+		assert.True(t, skip)
+		assert.True(t, common.HasErrorCode(reason, common.ErrCodeUpstreamLagging))
+	})
+
+	t.Run("SkipsLaggingUpstreamWhenBlockParamIsMissing", func(t *testing.T) {
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_getBalance","params":["0x0000000000000000000000000000000000000000"]}`))
+		assert.True(t, skip)
+		assert.True(t, common.HasErrorCode(reason, common.ErrCodeUpstreamLagging))
+	})
+
+	t.Run("AllowsLaggingUpstreamForStaticMethod", func(t *testing.T) {
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_chainId","params":[]}`))
+		assert.False(t, skip)
+		assert.Nil(t, reason)
+	})
+
+	t.Run("ReincludesUpstreamOnceCaughtUp", func(t *testing.T) {
+		tracker.SetLatestBlockNumber("test", "evm:1", 950)
+		reason, skip := upstream.shouldSkip(newRequest(`{"method":"eth_getBlockByNumber","params":["latest",false]}`))
+		assert.False(t, skip)
+		assert.Nil(t, reason)
+	})
+}
+
+func TestUpstream_RecordRemoteRateLimit(t *testing.T) {
+	logger := zerolog.Nop()
+	registry, err := NewRateLimitersRegistry(&common.RateLimiterConfig{
+		Budgets: []*common.RateLimitBudgetConfig{
+			{
+				Id: "vendor",
+				Rules: []*common.RateLimitRuleConfig{
+					{Method: "*", MaxCount: 1000, Period: "1m", WaitTime: "0s"},
+				},
+			},
+		},
+	}, &logger)
+	assert.NoError(t, err)
+	budget, err := registry.GetBudget("vendor")
+	assert.NoError(t, err)
+
+	tracker := health.NewTracker("test", time.Minute)
+	upstream := &Upstream{
+		config:               &common.UpstreamConfig{Id: "rpc1"},
+		metricsTracker:       tracker,
+		rateLimiterAutoTuner: NewRateLimitAutoTuner(&logger, budget, time.Minute, 0.1, 1.05, 0.9, 0, 0),
+	}
+
+	capacityExceeded := func(details map[string]interface{}) error {
+		return common.NewErrEndpointCapacityExceeded(
+			common.NewErrJsonRpcExceptionInternal(429, common.JsonRpcErrorCapacityExceeded, "too many requests", nil, details),
+		)
+	}
+	maxCount := func() uint {
+		rules, err := budget.GetRulesByMethod("eth_call")
+		assert.NoError(t, err)
+		return rules[0].Config.MaxCount
+	}
+
+	t.Run("CordonsUntilResetAndScalesLimitToRulePeriod", func(t *testing.T) {
+		upstream.recordRemoteRateLimit("evm:1", "eth_call", capacityExceeded(map[string]interface{}{
+			"rateLimitResetAt": time.Now().Add(time.Minute),
+			"rateLimitLimit":   5,
+			"rateLimitWindow":  time.Second,
+		}))
+
+		assert.True(t, tracker.IsTemporarilyCordoned("rpc1", "evm:1", "eth_call"))
+		assert.False(t, tracker.IsTemporarilyCordoned("rpc1", "evm:1", "eth_getLogs"))
+		assert.Equal(t, uint(300), maxCount(), "5 per second must be scaled to 300 per minute")
+	})
+
+	t.Run("CapsCordonDuration", func(t *testing.T) {
+		upstream.recordRemoteRateLimit("evm:1", "eth_getBalance", capacityExceeded(map[string]interface{}{
+			"rateLimitResetAt": time.Now().Add(24 * time.Hour),
+		}))
+
+		until := tracker.GetUpstreamMethodMetrics("rpc1", "evm:1", "eth_getBalance").CordonedUntil.Load()
+		assert.LessOrEqual(t, until, time.Now().Add(maxRemoteRateLimitCordon).UnixNano())
+	})
+
+	t.Run("IgnoresLimitWithoutWindow", func(t *testing.T) {
+		upstream.recordRemoteRateLimit("evm:1", "eth_call", capacityExceeded(map[string]interface{}{
+			"rateLimitLimit": 1,
+		}))
+
+		assert.Equal(t, uint(300), maxCount())
+	})
+
+	t.Run("SkipsUpstreamWhileCordoned", func(t *testing.T) {
+		req := common.NewNormalizedRequest([]byte(`{"method":"eth_call","params":[]}`))
+		req.SetNetwork(&lagTestNetwork{})
+		reason, skip := upstream.shouldSkip(req)
+		assert.True(t, skip)
+		assert.True(t, common.HasErrorCode(reason, common.ErrCodeUpstreamCordoned))
+	})
+}
+
+func TestUpstream_FailsafeMethodOverrides(t *testing.T) {
+	fsCfg := &common.FailsafeConfig{
+		Timeout: &common.TimeoutPolicyConfig{Duration: "3s"},
+		Retry: &common.RetryPolicyConfig{
+			MaxAttempts: 3,
+		},
+		MethodOverrides: []*common.FailsafeMethodOverrideConfig{
+			{
+				Method:  "debug_*|trace_*",
+				Timeout: &common.TimeoutPolicyConfig{Duration: "60s"},
+			},
+			{
+				Method:  "eth_getLogs",
+				Timeout: &common.TimeoutPolicyConfig{Duration: "20s"},
+				Retry:   &common.RetryPolicyConfig{MaxAttempts: 1},
+			},
+		},
+	}
+	logger := zerolog.Nop()
+	executors, err := CreateFailSafeExecutors(&logger, common.ScopeUpstream, "test", fsCfg, nil)
+	assert.NoError(t, err)
+	assert.Len(t, executors, 3)
+
+	fe := PickFailsafeExecutor(executors, "debug_traceBlockByNumber")
+	assert.Equal(t, 60*time.Second, *fe.Timeout)
+	assert.Contains(t, fe.Policies, "retry", "policies not overridden must be inherited")
+
+	fe = PickFailsafeExecutor(executors, "eth_getLogs")
+	assert.Equal(t, 20*time.Second, *fe.Timeout)
+
+	fe = PickFailsafeExecutor(executors, "eth_call")
+	assert.Equal(t, "*", fe.Method)
+	assert.Equal(t, 3*time.Second, *fe.Timeout)
+
+	executors, err = CreateFailSafeExecutors(&logger, common.ScopeUpstream, "test", nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, PickFailsafeExecutor(executors, "eth_call").Timeout)
+}
+
+func TestUpstream_AdaptiveTimeout(t *testing.T) {
+	fsCfg := &common.FailsafeConfig{
+		Timeout: &common.TimeoutPolicyConfig{
+			Duration:    "10s",
+			Quantile:    0.99,
+			Factor:      2,
+			MinDuration: "500ms",
+			MaxDuration: "10s",
+		},
+	}
+	logger := zerolog.Nop()
+	executors, err := CreateFailSafeExecutors(&logger, common.ScopeUpstream, "test", fsCfg, nil)
+	assert.NoError(t, err)
+	fe := PickFailsafeExecutor(executors, "eth_call")
+
+	at := func(latency float64) func(q float64) float64 {
+		return func(q float64) float64 {
+			assert.Equal(t, 0.99, q)
+			return latency
+		}
+	}
+	assert.Equal(t, 10*time.Second, *fe.TimeoutFor(at(0)), "must fall back to duration without latency data")
+	assert.Equal(t, 3*time.Second, *fe.TimeoutFor(at(1.5)))
+	assert.Equal(t, 500*time.Millisecond, *fe.TimeoutFor(at(0.01)), "must be clamped to minDuration")
+	assert.Equal(t, 10*time.Second, *fe.TimeoutFor(at(30)), "must be clamped to maxDuration")
+	assert.Equal(t, 10*time.Second, *fe.TimeoutFor(nil))
+}
+
+func TestUpstream_AdaptiveTimeoutWithoutMaxDuration(t *testing.T) {
+	fsCfg := &common.FailsafeConfig{
+		Timeout: &common.TimeoutPolicyConfig{
+			Duration: "2s",
+			Quantile: 0.99,
+			Factor:   2,
+		},
+	}
+	logger := zerolog.Nop()
+	executors, err := CreateFailSafeExecutors(&logger, common.ScopeUpstream, "test", fsCfg, nil)
+	assert.NoError(t, err)
+	fe := PickFailsafeExecutor(executors, "eth_call")
+
+	at := func(latency float64) func(q float64) float64 {
+		return func(q float64) float64 { return latency }
+	}
+	assert.Equal(t, 1*time.Second, *fe.TimeoutFor(at(0.5)))
+	assert.Equal(t, 2*time.Second, *fe.TimeoutFor(at(30)), "must be capped at duration like the timeout policy")
+}
+
+func TestUpstream_AdaptiveHedgeDelay(t *testing.T) {
+	logger := zerolog.Nop()
+	tracker := health.NewTracker("test", time.Minute)
+	for i := 0; i < 10; i++ {
+		tracker.RecordUpstreamDuration("rpc1", "evm:1", "eth_call", 50*time.Millisecond)
+	}
+	ups := &Upstream{config: &common.UpstreamConfig{Id: "rpc1"}, metricsTracker: tracker}
+
+	policy, err := createHedgePolicy(&logger, common.ScopeUpstream, "test", &common.HedgePolicyConfig{
+		Delay:    "5s",
+		MaxCount: 1,
+		Quantile: 0.9,
+		Factor:   2,
+		MinDelay: "10ms",
+	}, tracker, nil)
+	assert.NoError(t, err)
+
+	// Returns how long it took for the hedged attempt to be started.
+	hedgeAfter := func(method string) time.Duration {
+		req := common.NewNormalizedRequest([]byte(`{"method":"` + method + `","params":[]}`))
+		req.SetNetwork(&lagTestNetwork{})
+		req.SetLastUpstream(ups)
+		ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), common.RequestContextKey, req), time.Second)
+		defer cancel()
+
+		start := time.Now()
+		_, _ = failsafe.NewExecutor[*common.NormalizedResponse](policy).
+			WithContext(ctx).
+			GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+				if exec.Hedges() == 0 {
+					<-exec.Context().Done()
+					return nil, exec.Context().Err()
+				}
+				return common.NewNormalizedResponse(), nil
+			})
+		return time.Since(start)
+	}
+
+	t.Run("HedgesBasedOnObservedLatency", func(t *testing.T) {
+		assert.Less(t, hedgeAfter("eth_call"), 500*time.Millisecond)
+	})
+
+	t.Run("FallsBackToDelayWithoutLatencyData", func(t *testing.T) {
+		assert.GreaterOrEqual(t, hedgeAfter("eth_getBalance"), time.Second)
+	})
+}
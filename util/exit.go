@@ -7,4 +7,6 @@ var OsExit = os.Exit
 var (
 	ExitCodeERPCStartFailed  = 1001
 	ExitCodeHttpServerFailed = 1002
+	ExitCodeReplayFailed     = 1003
+	ExitCodeReplayMismatch   = 1004
 )
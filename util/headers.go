@@ -1,7 +1,9 @@
 package util
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	"net/http"
 )
@@ -34,3 +36,99 @@ func ExtractUsefulHeaders(r *http.Response) map[string]interface{} {
 
 	return result
 }
+
+// ExtractRateLimitHints parses the rate-limit related headers that upstreams commonly return
+// along with a 429 (Retry-After, X-RateLimit-Reset and X-RateLimit-Limit) and returns the time
+// at which the limit resets, the vendor-reported limit and the window it applies to (when given as
+// a "w" parameter, e.g. "100;w=1" or via RateLimit-Policy). Zero values mean no hint was found.
+func ExtractRateLimitHints(h http.Header, now time.Time) (resetAt time.Time, limit int, window time.Duration) {
+	if h == nil {
+		return
+	}
+
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			if secs > 0 {
+				resetAt = now.Add(time.Duration(secs * float64(time.Second)))
+			}
+		} else if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			resetAt = t
+		}
+	}
+
+	if resetAt.IsZero() {
+		for _, k := range []string{"X-RateLimit-Reset", "X-Rate-Limit-Reset", "RateLimit-Reset"} {
+			if v := strings.TrimSpace(h.Get(k)); v != "" {
+				if t := parseRateLimitReset(v, now); !t.IsZero() {
+					resetAt = t
+					break
+				}
+			}
+		}
+	}
+
+	for _, k := range []string{"X-RateLimit-Limit", "X-Rate-Limit-Limit", "RateLimit-Limit"} {
+		if v := strings.TrimSpace(h.Get(k)); v != "" {
+			window = parseRateLimitWindow(v)
+			// Some vendors return a policy list such as "100, 100;w=1" so only the first number is used.
+			if idx := strings.IndexAny(v, ",;"); idx > 0 {
+				v = strings.TrimSpace(v[:idx])
+			}
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				limit = n
+				break
+			}
+		}
+	}
+	if window == 0 {
+		window = parseRateLimitWindow(h.Get("RateLimit-Policy"))
+	}
+
+	return
+}
+
+// parseRateLimitWindow returns the window (in seconds) of the first "w" parameter of a rate-limit policy value.
+func parseRateLimitWindow(v string) time.Duration {
+	for _, param := range strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == ',' }) {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "w=") {
+			continue
+		}
+		if secs, err := strconv.ParseFloat(strings.TrimPrefix(param, "w="), 64); err == nil && secs > 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+	}
+	return 0
+}
+
+// parseRateLimitReset handles the different flavors vendors use for the reset header:
+// a unix timestamp in seconds or milliseconds, a number of seconds, or a duration like "1s".
+func parseRateLimitReset(v string, now time.Time) time.Time {
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		if n <= 0 {
+			return time.Time{}
+		}
+		switch {
+		case n > 1e12:
+			t := time.UnixMilli(int64(n))
+			if t.After(now) {
+				return t
+			}
+			return time.Time{}
+		case n > 1e9:
+			t := time.Unix(int64(n), 0)
+			if t.After(now) {
+				return t
+			}
+			return time.Time{}
+		default:
+			return now.Add(time.Duration(n * float64(time.Second)))
+		}
+	}
+
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return now.Add(d)
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractRateLimitHints(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("RetryAfterSeconds", func(t *testing.T) {
+		resetAt, limit, window := ExtractRateLimitHints(http.Header{"Retry-After": []string{"2.5"}}, now)
+		assert.Equal(t, now.Add(2500*time.Millisecond), resetAt)
+		assert.Zero(t, limit)
+		assert.Zero(t, window)
+	})
+
+	t.Run("RetryAfterHttpDate", func(t *testing.T) {
+		at := now.Add(time.Minute).UTC()
+		resetAt, _, _ := ExtractRateLimitHints(http.Header{"Retry-After": []string{at.Format(http.TimeFormat)}}, now)
+		assert.True(t, at.Equal(resetAt))
+	})
+
+	t.Run("RetryAfterTakesPrecedenceOverReset", func(t *testing.T) {
+		resetAt, _, _ := ExtractRateLimitHints(http.Header{
+			"Retry-After":       []string{"1"},
+			"X-Ratelimit-Reset": []string{"30"},
+		}, now)
+		assert.Equal(t, now.Add(time.Second), resetAt)
+	})
+
+	t.Run("LimitWithWindow", func(t *testing.T) {
+		_, limit, window := ExtractRateLimitHints(http.Header{"X-Ratelimit-Limit": []string{"100, 100;w=60"}}, now)
+		assert.Equal(t, 100, limit)
+		assert.Equal(t, time.Minute, window)
+	})
+
+	t.Run("LimitWithWindowFromPolicy", func(t *testing.T) {
+		_, limit, window := ExtractRateLimitHints(http.Header{
+			"Ratelimit-Limit":  []string{"25"},
+			"Ratelimit-Policy": []string{"25;w=1"},
+		}, now)
+		assert.Equal(t, 25, limit)
+		assert.Equal(t, time.Second, window)
+	})
+
+	t.Run("LimitWithoutWindow", func(t *testing.T) {
+		_, limit, window := ExtractRateLimitHints(http.Header{"X-Ratelimit-Limit": []string{"50"}}, now)
+		assert.Equal(t, 50, limit)
+		assert.Zero(t, window)
+	})
+
+	t.Run("InvalidValuesAreIgnored", func(t *testing.T) {
+		resetAt, limit, window := ExtractRateLimitHints(http.Header{
+			"Retry-After":       []string{"soon"},
+			"X-Ratelimit-Limit": []string{"many"},
+		}, now)
+		assert.True(t, resetAt.IsZero())
+		assert.Zero(t, limit)
+		assert.Zero(t, window)
+
+		resetAt, _, _ = ExtractRateLimitHints(nil, now)
+		assert.True(t, resetAt.IsZero())
+	})
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	assert.Equal(t, now.Add(30*time.Second), parseRateLimitReset("30", now), "small numbers are seconds from now")
+	assert.Equal(t, time.Unix(1700000060, 0), parseRateLimitReset("1700000060", now), "unix timestamp in seconds")
+	assert.Equal(t, time.UnixMilli(1700000060000), parseRateLimitReset("1700000060000", now), "unix timestamp in milliseconds")
+	assert.Equal(t, now.Add(1500*time.Millisecond), parseRateLimitReset("1.5s", now))
+
+	assert.True(t, parseRateLimitReset("1699999000", now).IsZero(), "timestamps in the past are ignored")
+	assert.True(t, parseRateLimitReset("1699999000000", now).IsZero())
+	assert.True(t, parseRateLimitReset("0", now).IsZero())
+	assert.True(t, parseRateLimitReset("-5", now).IsZero())
+	assert.True(t, parseRateLimitReset("tomorrow", now).IsZero())
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"net/url"
 	"strings"
 )
@@ -32,3 +33,18 @@ func RedactEndpoint(endpoint string) string {
 
 	return redactedEndpoint
 }
+
+// RedactParams replaces each json-rpc param with a short hash of its value, so that sensitive params
+// (e.g. signed transactions or messages to sign) are not revealed but identical requests can still be correlated.
+func RedactParams(params []interface{}) []interface{} {
+	if params == nil {
+		return nil
+	}
+	redacted := make([]interface{}, len(params))
+	for i, p := range params {
+		hasher := sha256.New()
+		hasher.Write(Str2Mem(fmt.Sprintf("%v", p)))
+		redacted[i] = "redacted=" + hex.EncodeToString(hasher.Sum(nil))[:5]
+	}
+	return redacted
+}